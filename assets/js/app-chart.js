@@ -2,6 +2,74 @@
   "use strict";
 
   var resizeObservers = new WeakMap();
+  var annotationsRegistered = false;
+
+  // annotations draws the lines of options.plugins.annotations on the time axis, such as the notes
+  // of the maintenance log, and shades the spans of the lines that have an end
+  var annotationsPlugin = {
+    id: "annotations",
+    afterDatasetsDraw: function (chart, _args, options) {
+      var lines = options && options.lines;
+      var scale = chart.scales && chart.scales.x;
+      if (!lines || !lines.length || !scale) {
+        return;
+      }
+
+      var area = chart.chartArea;
+      var ctx = chart.ctx;
+      ctx.save();
+      ctx.lineWidth = 1;
+      ctx.font = "11px sans-serif";
+      ctx.textBaseline = "middle";
+
+      lines.forEach(function (line) {
+        var x = scale.getPixelForValue(scale.parse(line.x));
+        ctx.strokeStyle = line.color || options.color || "#6b7280";
+        ctx.fillStyle = line.color || options.color || "#6b7280";
+
+        if (line.to) {
+          var to = scale.getPixelForValue(scale.parse(line.to));
+          var left = Math.max(x, area.left);
+          var right = Math.min(to, area.right);
+          if (isFinite(left) && isFinite(right) && right > left) {
+            ctx.globalAlpha = 0.12;
+            ctx.fillRect(left, area.top, right - left, area.bottom - area.top);
+            ctx.globalAlpha = 1;
+          }
+        }
+
+        if (!isFinite(x) || x < area.left || x > area.right) {
+          return;
+        }
+        ctx.setLineDash([4, 4]);
+        ctx.beginPath();
+        ctx.moveTo(x, area.top);
+        ctx.lineTo(x, area.bottom);
+        ctx.stroke();
+
+        ctx.setLineDash([]);
+        ctx.beginPath();
+        ctx.arc(x, area.top + 4, 3.5, 0, 2 * Math.PI);
+        ctx.fill();
+
+        if (line.label) {
+          var right = x + 8 + ctx.measureText(line.label).width <= area.right;
+          ctx.textAlign = right ? "left" : "right";
+          ctx.fillText(line.label, right ? x + 8 : x - 8, area.top + 4);
+        }
+      });
+
+      ctx.restore();
+    },
+  };
+
+  function registerPlugins() {
+    if (annotationsRegistered || typeof Chart.register !== "function") {
+      return;
+    }
+    Chart.register(annotationsPlugin);
+    annotationsRegistered = true;
+  }
 
   function parseJSONNode(id) {
     if (!id) {
@@ -86,6 +154,7 @@
     }
 
     try {
+      registerPlugins();
       new Chart(canvas, config);
       ensureResizeObserver(canvas);
     } catch (err) {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/diwise/diwise-web/internal/application"
+	"github.com/diwise/diwise-web/internal/application/storage"
 	"github.com/diwise/service-chassis/pkg/infrastructure/net/http/authn"
 	"github.com/diwise/service-chassis/pkg/infrastructure/servicerunner"
 )
@@ -34,12 +35,25 @@ const (
 	oauth2SkipVerify
 
 	contentSecurityPolicy
+
+	chartPointBudget
+
+	storageURL
+	attachmentStorageURL
+
+	ruleEvaluationInterval
+	dataQualityInterval
+
+	municipalBounds
+	propertySchemas
 )
 
 type AppConfig struct {
 	app *application.App
 	pte authn.PhantomTokenExchange
 
+	store storage.Store
+
 	cancelContext context.CancelFunc
 }
 
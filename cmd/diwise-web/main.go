@@ -7,10 +7,15 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/diwise/diwise-web/internal/application"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/application/properties"
+	"github.com/diwise/diwise-web/internal/application/storage"
 	"github.com/diwise/diwise-web/internal/presentation/api"
 	"github.com/diwise/diwise-web/internal/presentation/api/authz"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
@@ -25,6 +30,7 @@ import (
 	"github.com/diwise/service-chassis/pkg/infrastructure/servicerunner"
 
 	"github.com/google/uuid"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 const serviceName string = "diwise-web"
@@ -37,6 +43,10 @@ func DefaultFlags() FlagMap {
 
 		devModeEnabled:        "false",
 		contentSecurityPolicy: "strict",
+		chartPointBudget:      "500",
+
+		ruleEvaluationInterval: "5m",
+		dataQualityInterval:    "1h",
 	}
 }
 
@@ -54,6 +64,18 @@ func main() {
 
 	ctx = helpers.WithVersion(ctx, serviceVersion)
 
+	pointBudget, err := strconv.Atoi(flags[chartPointBudget])
+	exitIf(err, logger, "failed to parse chart point budget", "value", flags[chartPointBudget])
+	ctx = helpers.WithChartPointBudget(ctx, pointBudget)
+
+	bounds, err := geo.ParseBoundingBox(flags[municipalBounds])
+	exitIf(err, logger, "failed to parse municipal bounds", "value", flags[municipalBounds])
+	ctx = helpers.WithMunicipalBounds(ctx, bounds)
+
+	schemas, err := properties.Load(flags[propertySchemas])
+	exitIf(err, logger, "failed to load property schemas", "path", flags[propertySchemas])
+	ctx = helpers.WithPropertySchemas(ctx, schemas)
+
 	cfg, err := newConfig(ctx, flags)
 	exitIf(err, logger, "failed to create application config")
 
@@ -116,8 +138,19 @@ func initialize(ctx context.Context, flags FlagMap, cfg *AppConfig) (servicerunn
 					}
 				}
 
+				svcCfg.store, err = storage.Open(ctx, flags[storageURL])
+				if err != nil {
+					return fmt.Errorf("failed to open storage: %s", err.Error())
+				}
+
+				blobs, err := storage.OpenBlobs(ctx, flags[attachmentStorageURL])
+				if err != nil {
+					return fmt.Errorf("failed to open attachment storage: %s", err.Error())
+				}
+
 				svcCfg.app, err = application.New(ctx,
 					flags[devMgmtURL], flags[thingsURL], flags[adminURL], flags[alarmsURL], flags[measurementsURL],
+					svcCfg.store, blobs,
 				)
 				if err != nil {
 					return err
@@ -185,6 +218,23 @@ func initialize(ctx context.Context, flags FlagMap, cfg *AppConfig) (servicerunn
 		}),
 		onrunning(func(ctx context.Context, svcCfg *AppConfig) error {
 			logging.GetFromContext(ctx).Info("diwise-web is running and waiting for connections", "approot", flags[appRoot])
+
+			interval, err := time.ParseDuration(flags[ruleEvaluationInterval])
+			if err != nil {
+				return fmt.Errorf("failed to parse rule evaluation interval: %s", err.Error())
+			}
+			if interval > 0 {
+				go svcCfg.app.RunRuleEvaluator(ctx, interval, evaluatorAuthentication(flags, devModeEnabled))
+			}
+
+			interval, err = time.ParseDuration(flags[dataQualityInterval])
+			if err != nil {
+				return fmt.Errorf("failed to parse data quality interval: %s", err.Error())
+			}
+			if interval > 0 {
+				go svcCfg.app.RunDataQualityChecker(ctx, interval, evaluatorAuthentication(flags, devModeEnabled))
+			}
+
 			return nil
 		}),
 		onshutdown(func(ctx context.Context, svcCfg *AppConfig) error {
@@ -197,6 +247,10 @@ func initialize(ctx context.Context, flags FlagMap, cfg *AppConfig) (servicerunn
 				svcCfg.pte.Shutdown()
 			}
 
+			if svcCfg.store != nil {
+				svcCfg.store.Close()
+			}
+
 			return nil
 		}),
 	)
@@ -204,6 +258,28 @@ func initialize(ctx context.Context, flags FlagMap, cfg *AppConfig) (servicerunn
 	return runner, nil
 }
 
+// evaluatorAuthentication lets the rule evaluator and the data quality checker call the backend with
+// the client credentials of diwise-web, since they do not run on behalf of a logged in user.
+func evaluatorAuthentication(flags FlagMap, devModeEnabled bool) func(context.Context) (context.Context, error) {
+	if devModeEnabled {
+		return func(ctx context.Context) (context.Context, error) { return ctx, nil }
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:     flags[oauth2ClientID],
+		ClientSecret: flags[oauth2ClientSecret],
+		TokenURL:     strings.TrimSuffix(flags[oauth2RealmURL], "/") + "/protocol/openid-connect/token",
+	}
+
+	return func(ctx context.Context) (context.Context, error) {
+		token, err := credentials.Token(ctx)
+		if err != nil {
+			return ctx, err
+		}
+		return authz.WithToken(ctx, token.AccessToken), nil
+	}
+}
+
 func changeURLPortNumbers(_ context.Context, flags FlagMap, from, to string) FlagMap {
 	for _, flag := range []FlagType{appRoot, adminURL, alarmsURL, devMgmtURL, measurementsURL, thingsURL} {
 		flags[flag] = strings.Replace(flags[flag], ":"+from, ":"+to, 1)
@@ -220,6 +296,15 @@ func parseExternalConfig(ctx context.Context, flags FlagMap) (context.Context, F
 	flags[webAssetPath] = envOrDef(ctx, "DIWISEWEB_ASSET_PATH", "/opt/diwise/assets")
 	flags[contentSecurityPolicy] = envOrDef(ctx, "CONTENT_SECURITY_POLICY", flags[contentSecurityPolicy])
 	flags[grafanaURL] = envOrDef(ctx, "GRAFANA_URL", flags[grafanaURL])
+	flags[chartPointBudget] = envOrDef(ctx, "CHART_POINT_BUDGET", flags[chartPointBudget])
+	defaultStorageURL := "file:///opt/diwise/data"
+	flags[storageURL] = envOrDef(ctx, "DIWISEWEB_STORAGE_URL", defaultStorageURL)
+	defaultAttachmentStorageURL := "file:///opt/diwise/attachments"
+	flags[attachmentStorageURL] = envOrDef(ctx, "DIWISEWEB_ATTACHMENT_STORAGE_URL", defaultAttachmentStorageURL)
+	flags[ruleEvaluationInterval] = envOrDef(ctx, "RULE_EVALUATION_INTERVAL", flags[ruleEvaluationInterval])
+	flags[dataQualityInterval] = envOrDef(ctx, "DATA_QUALITY_INTERVAL", flags[dataQualityInterval])
+	flags[municipalBounds] = envOrDef(ctx, "MUNICIPAL_BOUNDS", flags[municipalBounds])
+	flags[propertySchemas] = envOrDef(ctx, "THING_PROPERTY_SCHEMAS", flags[propertySchemas])
 
 	defaultAppRoot := fmt.Sprintf("http://localhost:%s", flags[servicePort])
 	flags[appRoot] = envOrDef(ctx, "APP_ROOT", defaultAppRoot)
@@ -240,6 +325,13 @@ func parseExternalConfig(ctx context.Context, flags FlagMap) (context.Context, F
 	flag.Func("csp", "set content security policy to strict, report or off", apply(contentSecurityPolicy))
 	flag.Func("grafana", "url to embedded grafana instance", apply(grafanaURL))
 	flag.Func("web-assets", "path to web assets folder", apply(webAssetPath))
+	flag.Func("chart-points", "maximum number of points per chart series", apply(chartPointBudget))
+	flag.Func("storage", "storage url for saved views and other local data (file:// or sqlite://)", apply(storageURL))
+	flag.Func("attachments", "storage url for photos attached to things and sensors (file:// or s3://)", apply(attachmentStorageURL))
+	flag.Func("rule-interval", "how often the alert rules are evaluated, 0 to disable", apply(ruleEvaluationInterval))
+	flag.Func("quality-interval", "how often the measurements of the sensors are checked for suspect data, 0 to disable", apply(dataQualityInterval))
+	flag.Func("municipal-bounds", "area that things may be placed in as minlon,minlat,maxlon,maxlat, empty to allow anywhere", apply(municipalBounds))
+	flag.Func("property-schemas", "path to a json file with the properties of each type of thing, empty for the defaults", apply(propertySchemas))
 	flag.Parse()
 
 	if flags[devModeEnabled] != "true" {
@@ -256,6 +348,13 @@ func parseExternalConfig(ctx context.Context, flags FlagMap) (context.Context, F
 		flags[devMgmtURL] = appRoot + api.DevModePrefix + "/devices"
 		flags[thingsURL] = appRoot + api.DevModePrefix + "/things"
 		flags[measurementsURL] = appRoot + api.DevModePrefix + "/measurements"
+
+		if flags[storageURL] == defaultStorageURL {
+			flags[storageURL] = "file://" + filepath.Join(os.TempDir(), "diwise-web")
+		}
+		if flags[attachmentStorageURL] == defaultAttachmentStorageURL {
+			flags[attachmentStorageURL] = "file://" + filepath.Join(os.TempDir(), "diwise-web", "attachments")
+		}
 	}
 
 	flags[adminURL] = strings.Replace(flags[devMgmtURL], "devices", "admin", 1)
@@ -0,0 +1,262 @@
+package attachments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/attachments")
+
+// attachmentsCollection keeps the attachments of each thing and sensor as one document, in the same
+// way as the notes.
+const attachmentsCollection = "attachments"
+
+const maxNameLength = 200
+
+type Service struct {
+	store storage.Store
+	blobs storage.BlobStore
+	locks storage.Locks
+}
+
+func NewService(store storage.Store, blobs storage.BlobStore) *Service {
+	return &Service{store: store, blobs: blobs}
+}
+
+// GetAttachments returns the attachments of a thing or a sensor, the latest first.
+func (s *Service) GetAttachments(ctx context.Context, kind, subjectID string) ([]Attachment, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-attachments")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	attachments, err := s.attachments(ctx, kind, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(attachments, func(a, b Attachment) int { return b.Uploaded.Compare(a.Uploaded) })
+
+	return attachments, nil
+}
+
+// AddAttachment stores a photo uploaded by the current user together with a thumbnail of it. The
+// user must be known so that they can delete the photo again. The type of the file is told by its
+// contents rather than by its name.
+func (s *Service) AddAttachment(ctx context.Context, kind, subjectID, name string, data []byte) (Attachment, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "add-attachment")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	author, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	subjectID = strings.TrimSpace(subjectID)
+	if !slices.Contains(Kinds, kind) || subjectID == "" {
+		err = errors.Join(ErrInvalid, errors.New("attachments need a thing or a sensor"))
+		return Attachment{}, err
+	}
+	if len(data) > MaxSize {
+		err = ErrTooLarge
+		return Attachment{}, err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(ContentTypes, contentType) {
+		err = ErrUnsupportedType
+		return Attachment{}, err
+	}
+
+	thumb, size, err := thumbnail(data)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	attachment := Attachment{
+		ID:          uuid.NewString(),
+		Kind:        kind,
+		SubjectID:   subjectID,
+		Name:        attachmentName(name),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       size.X,
+		Height:      size.Y,
+		Author:      authz.Name(ctx),
+		AuthorID:    author,
+		Uploaded:    time.Now().UTC().Truncate(time.Second),
+	}
+
+	err = s.blobs.PutBlob(ctx, attachment.ID, contentType, data)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	err = s.blobs.PutBlob(ctx, attachment.thumbnailKey(), "image/jpeg", thumb)
+	if err == nil {
+		err = s.add(ctx, attachment)
+	}
+	if err != nil {
+		s.deleteBlobs(ctx, attachment)
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// add lists an attachment whose files have been stored.
+func (s *Service) add(ctx context.Context, attachment Attachment) error {
+	unlock := s.locks.Lock(key(attachment.Kind, attachment.SubjectID))
+	defer unlock()
+
+	attachments, err := s.attachments(ctx, attachment.Kind, attachment.SubjectID)
+	if err != nil {
+		return err
+	}
+
+	return s.save(ctx, attachment.Kind, attachment.SubjectID, append(attachments, attachment))
+}
+
+// GetAttachmentFile returns an attachment and its file, or its thumbnail. The caller must close the
+// body of the file.
+func (s *Service) GetAttachmentFile(ctx context.Context, kind, subjectID, id string, thumbnail bool) (Attachment, storage.Blob, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-attachment-file")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	attachments, err := s.attachments(ctx, kind, subjectID)
+	if err != nil {
+		return Attachment{}, storage.Blob{}, err
+	}
+
+	// only files attached to the given thing or sensor are served, never any other key in the store
+	i := slices.IndexFunc(attachments, func(a Attachment) bool { return a.ID == id })
+	if i < 0 {
+		err = storage.ErrNotFound
+		return Attachment{}, storage.Blob{}, err
+	}
+
+	attachment := attachments[i]
+	key := attachment.ID
+	if thumbnail {
+		key = attachment.thumbnailKey()
+	}
+
+	blob, err := s.blobs.GetBlob(ctx, key)
+	if err != nil {
+		return Attachment{}, storage.Blob{}, err
+	}
+
+	return attachment, blob, nil
+}
+
+// DeleteAttachment removes an attachment and its files. Only the one who uploaded an attachment may
+// remove it.
+func (s *Service) DeleteAttachment(ctx context.Context, kind, subjectID, id string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "delete-attachment")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	subject, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := s.locks.Lock(key(kind, subjectID))
+	defer unlock()
+
+	attachments, err := s.attachments(ctx, kind, subjectID)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(attachments, func(a Attachment) bool { return a.ID == id })
+	if i < 0 {
+		err = storage.ErrNotFound
+		return err
+	}
+	if attachments[i].AuthorID != subject {
+		err = ErrNotAuthor
+		return err
+	}
+
+	attachment := attachments[i]
+	err = s.save(ctx, kind, subjectID, slices.Delete(attachments, i, i+1))
+	if err != nil {
+		return err
+	}
+
+	s.deleteBlobs(ctx, attachment)
+
+	return nil
+}
+
+// deleteBlobs removes the files of an attachment. A file left behind is only logged, since the
+// attachment is no longer listed and the file cannot be reached.
+func (s *Service) deleteBlobs(ctx context.Context, attachment Attachment) {
+	for _, key := range []string{attachment.ID, attachment.thumbnailKey()} {
+		err := s.blobs.DeleteBlob(ctx, key)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logging.GetFromContext(ctx).Warn("could not delete attachment file", "key", key, "err", err.Error())
+		}
+	}
+}
+
+func (s *Service) attachments(ctx context.Context, kind, subjectID string) ([]Attachment, error) {
+	document, err := s.store.Get(ctx, attachmentsCollection, key(kind, subjectID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return []Attachment{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []Attachment{}
+	if err := json.Unmarshal(document, &attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (s *Service) save(ctx context.Context, kind, subjectID string, attachments []Attachment) error {
+	if len(attachments) == 0 {
+		return s.store.Delete(ctx, attachmentsCollection, key(kind, subjectID))
+	}
+
+	document, err := json.Marshal(attachments)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(ctx, attachmentsCollection, key(kind, subjectID), document)
+}
+
+func key(kind, subjectID string) string {
+	return kind + "/" + subjectID
+}
+
+// attachmentName keeps the file name of an upload without any path that a browser may have sent.
+func attachmentName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "" || name == "." || name == "/" {
+		return "photo"
+	}
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+	return name
+}
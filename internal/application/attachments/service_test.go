@@ -0,0 +1,179 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestAddAttachmentKeepsThePhotoAndAThumbnail(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	ctx := withUser("alice", "Alice Andersson")
+
+	photo := encodePNG(1200, 800)
+	attachment, err := svc.AddAttachment(ctx, KindDevice, "sensor-1", `C:\Users\alice\mounting.png`, photo)
+	is.NoErr(err)
+	is.Equal("mounting.png", attachment.Name)
+	is.Equal("image/png", attachment.ContentType)
+	is.Equal(1200, attachment.Width)
+	is.Equal("Alice Andersson", attachment.Author)
+
+	_, file, err := svc.GetAttachmentFile(ctx, KindDevice, "sensor-1", attachment.ID, false)
+	is.NoErr(err)
+	b, _ := io.ReadAll(file.Body)
+	file.Body.Close()
+	is.Equal(photo, b)
+
+	_, file, err = svc.GetAttachmentFile(ctx, KindDevice, "sensor-1", attachment.ID, true)
+	is.NoErr(err)
+	thumb, err := jpeg.Decode(file.Body)
+	file.Body.Close()
+	is.NoErr(err)
+	is.Equal(image.Pt(ThumbnailSize, 213), thumb.Bounds().Size())
+
+	// the files of one sensor cannot be reached through another
+	_, _, err = svc.GetAttachmentFile(ctx, KindDevice, "sensor-2", attachment.ID, false)
+	is.True(errors.Is(err, storage.ErrNotFound))
+
+	attachments, err := svc.GetAttachments(ctx, KindDevice, "sensor-1")
+	is.NoErr(err)
+	is.Equal(1, len(attachments))
+}
+
+func TestAddAttachmentOnlyAcceptsPhotos(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	ctx := withUser("alice", "")
+
+	_, err := svc.AddAttachment(ctx, KindThing, "outlet-1", "notes.pdf", []byte("%PDF-1.7\n"))
+	is.True(errors.Is(err, ErrUnsupportedType))
+
+	_, err = svc.AddAttachment(ctx, KindThing, "outlet-1", "big.png", make([]byte, MaxSize+1))
+	is.True(errors.Is(err, ErrTooLarge))
+
+	broken := encodePNG(10, 10)[:60]
+	_, err = svc.AddAttachment(ctx, KindThing, "outlet-1", "broken.png", broken)
+	is.True(errors.Is(err, ErrInvalid))
+
+	_, err = svc.AddAttachment(ctx, "building", "b-1", "photo.png", encodePNG(10, 10))
+	is.True(errors.Is(err, ErrInvalid))
+}
+
+func TestOnlyTheUploaderMayDeleteAnAttachment(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	alice, bob := withUser("alice", "Alice"), withUser("bob", "Bob")
+
+	attachment, err := svc.AddAttachment(alice, KindThing, "outlet-1", "outlet.png", encodePNG(40, 30))
+	is.NoErr(err)
+
+	err = svc.DeleteAttachment(bob, KindThing, "outlet-1", attachment.ID)
+	is.True(errors.Is(err, ErrNotAuthor))
+
+	is.NoErr(svc.DeleteAttachment(alice, KindThing, "outlet-1", attachment.ID))
+
+	_, err = svc.blobs.GetBlob(alice, attachment.ID)
+	is.True(errors.Is(err, storage.ErrNotFound))
+	_, err = svc.blobs.GetBlob(alice, attachment.thumbnailKey())
+	is.True(errors.Is(err, storage.ErrNotFound))
+
+	err = svc.DeleteAttachment(context.Background(), KindThing, "outlet-1", attachment.ID)
+	is.True(errors.Is(err, authz.ErrNoSubject))
+}
+
+func TestPhotosAreOnlyStoredForAKnownUser(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	_, err := svc.AddAttachment(context.Background(), KindThing, "outlet-1", "outlet.png", encodePNG(40, 30))
+	is.True(errors.Is(err, authz.ErrNoSubject))
+
+	attachments, err := svc.GetAttachments(context.Background(), KindThing, "outlet-1")
+	is.NoErr(err)
+	is.Equal(0, len(attachments))
+}
+
+func TestPhotosUploadedAtTheSameTimeAreAllKept(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	alice := withUser("alice", "Alice")
+	photo := encodePNG(40, 30)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, err := svc.AddAttachment(alice, KindThing, "outlet-1", "outlet.png", photo)
+			is.NoErr(err)
+		})
+	}
+	wg.Wait()
+
+	attachments, err := svc.GetAttachments(alice, KindThing, "outlet-1")
+	is.NoErr(err)
+	is.Equal(10, len(attachments))
+}
+
+func TestThumbnailsAreTurnedTheWayTheCameraSays(t *testing.T) {
+	is := is.New(t)
+
+	img := image.NewRGBA(image.Rect(0, 0, 60, 40))
+	buf := bytes.Buffer{}
+	is.NoErr(jpeg.Encode(&buf, img, nil))
+
+	// an exif segment that says that the photo should be turned 90° clockwise
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00")
+	photo := append([]byte{0xff, 0xd8, 0xff, 0xe1, 0x00, byte(len(exif) + 2)}, exif...)
+	photo = append(photo, buf.Bytes()[2:]...)
+
+	is.Equal(6, exifOrientation(photo))
+
+	thumb, size, err := thumbnail(photo)
+	is.NoErr(err)
+	is.Equal(image.Pt(40, 60), size)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(thumb))
+	is.NoErr(err)
+	is.Equal(image.Pt(40, 60), decoded.Bounds().Size())
+}
+
+func encodePNG(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+
+	buf := bytes.Buffer{}
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func newTestService(t *testing.T) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs, err := storage.NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(store, blobs)
+}
+
+func withUser(subject, name string) context.Context {
+	ctx := context.WithValue(context.Background(), authz.SubjectClaim, subject)
+	return context.WithValue(ctx, authz.NameClaim, name)
+}
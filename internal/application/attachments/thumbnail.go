@@ -0,0 +1,194 @@
+package attachments
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// samples is how many pixels along each side of the area behind a thumbnail pixel are averaged,
+// which keeps the work per thumbnail the same however large the photo is.
+const samples = 4
+
+// thumbnail makes a JPEG of a photo that is at most ThumbnailSize pixels on its longest side, turned
+// the way the camera says the photo should be shown. It also returns the size of the photo.
+func thumbnail(data []byte) ([]byte, image.Point, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, image.Point{}, errors.Join(ErrInvalid, err)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, image.Point{}, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, image.Point{}, errors.Join(ErrInvalid, err)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, image.Point{}, ErrInvalid
+	}
+
+	scale := min(1, float64(ThumbnailSize)/float64(max(width, height)))
+	tw, th := max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))
+
+	thumb := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := range th {
+		for x := range tw {
+			thumb.Set(x, y, average(img, image.Rect(
+				bounds.Min.X+x*width/tw, bounds.Min.Y+y*height/th,
+				bounds.Min.X+(x+1)*width/tw, bounds.Min.Y+(y+1)*height/th,
+			)))
+		}
+	}
+
+	orientation := exifOrientation(data)
+	rotated := orient(thumb, orientation)
+
+	buf := bytes.Buffer{}
+	err = jpeg.Encode(&buf, rotated, &jpeg.Options{Quality: 80})
+	if err != nil {
+		return nil, image.Point{}, err
+	}
+
+	size := image.Pt(width, height)
+	if orientation >= 5 {
+		size = image.Pt(height, width)
+	}
+
+	return buf.Bytes(), size, nil
+}
+
+// average returns the mean colour of an area of an image, laid over white since a JPEG cannot be
+// transparent.
+func average(img image.Image, area image.Rectangle) color.RGBA {
+	stepX, stepY := max(1, area.Dx()/samples), max(1, area.Dy()/samples)
+
+	var r, g, b, n uint64
+	for y := area.Min.Y; y < max(area.Max.Y, area.Min.Y+1); y += stepY {
+		for x := area.Min.X; x < max(area.Max.X, area.Min.X+1); x += stepX {
+			cr, cg, cb, ca := img.At(x, y).RGBA()
+			white := 0xffff - uint64(ca)
+			r += uint64(cr) + white
+			g += uint64(cg) + white
+			b += uint64(cb) + white
+			n++
+		}
+	}
+
+	return color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: 0xff}
+}
+
+// orient turns and flips an image according to an EXIF orientation, 1 to 8.
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	result := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range h {
+		for x := range w {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			result.SetRGBA(dx, dy, img.RGBAAt(x, y))
+		}
+	}
+
+	return result
+}
+
+// exifOrientation reads the orientation that a camera wrote into a JPEG, or 1 if there is none.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xda || length < 2 || i+2+length > len(data) {
+			// the image data starts, and no exif has been found
+			return 1
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		i += 2 + length
+	}
+
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := range entries {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
@@ -0,0 +1,67 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+)
+
+var (
+	ErrInvalid         = errors.New("invalid attachment")
+	ErrTooLarge        = errors.New("the file is too large")
+	ErrUnsupportedType = errors.New("unsupported file type")
+	// ErrNotAuthor is returned when an attachment is deleted by someone else than the one who
+	// uploaded it.
+	ErrNotAuthor = errors.New("only the uploader may delete an attachment")
+)
+
+type Management interface {
+	GetAttachments(ctx context.Context, kind, subjectID string) ([]Attachment, error)
+	AddAttachment(ctx context.Context, kind, subjectID, name string, data []byte) (Attachment, error)
+	GetAttachmentFile(ctx context.Context, kind, subjectID, id string, thumbnail bool) (Attachment, storage.Blob, error)
+	DeleteAttachment(ctx context.Context, kind, subjectID, id string) error
+}
+
+// Kinds are what files can be attached to.
+const (
+	KindThing  = "thing"
+	KindDevice = "device"
+)
+
+var Kinds = []string{KindThing, KindDevice}
+
+// ContentTypes are the kinds of files that may be attached. Only photos are accepted, in the
+// formats that a thumbnail can be made of.
+var ContentTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+const (
+	// MaxSize is the largest file that may be attached, in bytes.
+	MaxSize = 10 << 20
+	// MaxPixels is the largest photo that may be attached, so that a small file cannot expand
+	// into a huge image when the thumbnail is made.
+	MaxPixels = 50_000_000
+	// ThumbnailSize is the longest side of a thumbnail, in pixels.
+	ThumbnailSize = 320
+)
+
+// Attachment is a photo attached to a thing or a sensor, such as of how a sensor was mounted. The
+// file and its thumbnail are kept in a blob store, under the ID of the attachment.
+type Attachment struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	SubjectID   string    `json:"subjectId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Author      string    `json:"author"`
+	AuthorID    string    `json:"authorId,omitzero"`
+	Uploaded    time.Time `json:"uploaded"`
+}
+
+func (a Attachment) thumbnailKey() string {
+	return a.ID + "-thumbnail"
+}
@@ -0,0 +1,107 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV writes the run as one row per stop in visiting order.
+func (r Run) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	err := w.Write([]string{"order", "id", "name", "latitude", "longitude", "percent", "tenant", "tags"})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, stop := range r.Stops {
+		err = w.Write([]string{
+			strconv.Itoa(i + 1),
+			stop.ThingID,
+			stop.Name,
+			strconv.FormatFloat(stop.Point.Latitude, 'f', 6, 64),
+			strconv.FormatFloat(stop.Point.Longitude, 'f', 6, 64),
+			strconv.FormatFloat(stop.Percent, 'f', 0, 64),
+			stop.Tenant,
+			strings.Join(stop.Tags, ","),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+type gpxDocument struct {
+	XMLName xml.Name   `xml:"gpx"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Version string     `xml:"version,attr"`
+	Creator string     `xml:"creator,attr"`
+	Time    string     `xml:"metadata>time"`
+	Points  []gpxPoint `xml:"wpt"`
+	Route   gpxRoute   `xml:"rte"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxPoint struct {
+	Latitude  string `xml:"lat,attr"`
+	Longitude string `xml:"lon,attr"`
+	Name      string `xml:"name"`
+	Desc      string `xml:"desc,omitempty"`
+}
+
+// GPX writes the run as a GPX 1.1 route that starts and ends at the depot, with a waypoint per stop.
+func (r Run) GPX() ([]byte, error) {
+	createdAt := r.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	doc := gpxDocument{
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: "diwise-web",
+		Time:    createdAt.UTC().Format(time.RFC3339),
+		Route:   gpxRoute{Name: "collection run " + createdAt.Format("2006-01-02")},
+	}
+
+	depot := newGPXPoint(r.Criteria.Depot, "depot", "")
+	doc.Route.Points = append(doc.Route.Points, depot)
+
+	for i, stop := range r.Stops {
+		p := newGPXPoint(stop.Point, fmt.Sprintf("%d. %s", i+1, stop.Name), fmt.Sprintf("%s %.0f%%", stop.ThingID, stop.Percent))
+		doc.Points = append(doc.Points, p)
+		doc.Route.Points = append(doc.Route.Points, p)
+	}
+
+	doc.Route.Points = append(doc.Route.Points, depot)
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+func newGPXPoint(p Point, name, desc string) gpxPoint {
+	return gpxPoint{
+		Latitude:  strconv.FormatFloat(p.Latitude, 'f', 6, 64),
+		Longitude: strconv.FormatFloat(p.Longitude, 'f', 6, 64),
+		Name:      name,
+		Desc:      desc,
+	}
+}
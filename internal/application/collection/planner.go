@@ -0,0 +1,103 @@
+package collection
+
+import "github.com/diwise/diwise-web/internal/application/geo"
+
+// Plan orders stops into a closed tour from and back to the depot. The tour is built with the
+// nearest neighbour heuristic and then improved with 2-opt until no swap shortens it. The returned
+// distance is the length of the whole tour in meters.
+func Plan(depot Point, stops []Stop) ([]Stop, float64) {
+	if len(stops) == 0 {
+		return []Stop{}, 0
+	}
+
+	// index 0 is the depot, stops are 1..n
+	points := make([]Point, 0, len(stops)+1)
+	points = append(points, depot)
+	for _, s := range stops {
+		points = append(points, s.Point)
+	}
+
+	n := len(points)
+	dist := make([][]float64, n)
+	for i := range n {
+		dist[i] = make([]float64, n)
+		for j := range i {
+			dist[i][j] = geo.Distance(geo.Position(points[i]), geo.Position(points[j]))
+			dist[j][i] = dist[i][j]
+		}
+	}
+
+	tour := nearestNeighbour(dist)
+	twoOpt(tour, dist)
+
+	ordered := make([]Stop, 0, len(stops))
+	for _, i := range tour[1:n] {
+		ordered = append(ordered, stops[i-1])
+	}
+
+	return ordered, tourLength(tour, dist)
+}
+
+// nearestNeighbour returns a tour that starts at 0, visits every index once and ends at 0.
+func nearestNeighbour(dist [][]float64) []int {
+	n := len(dist)
+	visited := make([]bool, n)
+	tour := make([]int, 0, n+1)
+
+	current := 0
+	visited[0] = true
+	tour = append(tour, 0)
+
+	for len(tour) < n {
+		next := -1
+		for j := range n {
+			if visited[j] {
+				continue
+			}
+			if next == -1 || dist[current][j] < dist[current][next] {
+				next = j
+			}
+		}
+		visited[next] = true
+		tour = append(tour, next)
+		current = next
+	}
+
+	return append(tour, 0)
+}
+
+// twoOpt reverses segments of the tour as long as doing so makes it shorter. The depot stays in place.
+func twoOpt(tour []int, dist [][]float64) {
+	const epsilon = 1e-9
+
+	improved := true
+	for improved {
+		improved = false
+		for i := 1; i < len(tour)-2; i++ {
+			for k := i + 1; k < len(tour)-1; k++ {
+				a, b := tour[i-1], tour[i]
+				c, d := tour[k], tour[k+1]
+
+				delta := dist[a][c] + dist[b][d] - dist[a][b] - dist[c][d]
+				if delta < -epsilon {
+					reverse(tour[i : k+1])
+					improved = true
+				}
+			}
+		}
+	}
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func tourLength(tour []int, dist [][]float64) float64 {
+	total := 0.0
+	for i := 1; i < len(tour); i++ {
+		total += dist[tour[i-1]][tour[i]]
+	}
+	return total
+}
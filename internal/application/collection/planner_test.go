@@ -0,0 +1,58 @@
+package collection
+
+import (
+	"math"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/matryer/is"
+)
+
+func TestPlanVisitsEveryStopOnce(t *testing.T) {
+	is := is.New(t)
+
+	depot := Point{62.0, 17.0}
+	stops := []Stop{
+		{ThingID: "c", Point: Point{62.03, 17.0}},
+		{ThingID: "a", Point: Point{62.01, 17.0}},
+		{ThingID: "d", Point: Point{62.04, 17.0}},
+		{ThingID: "b", Point: Point{62.02, 17.0}},
+	}
+
+	ordered, distance := Plan(depot, stops)
+	is.Equal(4, len(ordered))
+	is.Equal("a", ordered[0].ThingID)
+	is.Equal("d", ordered[3].ThingID)
+
+	// the shortest tour goes straight out and back along the meridian
+	expected := 2 * geo.Distance(geo.Position(depot), geo.Position{Latitude: 62.04, Longitude: 17.0})
+	is.True(math.Abs(distance-expected) < 1)
+}
+
+func TestTwoOptRemovesCrossingEdges(t *testing.T) {
+	is := is.New(t)
+
+	// a square where nearest neighbour from the depot corner produces a crossing tour
+	dist := [][]float64{
+		{0, 1, 1.5, 1.1},
+		{1, 0, 1.1, 1.5},
+		{1.5, 1.1, 0, 1},
+		{1.1, 1.5, 1, 0},
+	}
+	tour := []int{0, 1, 3, 2, 0}
+	before := tourLength(tour, dist)
+
+	twoOpt(tour, dist)
+
+	is.True(tourLength(tour, dist) < before)
+	is.Equal(0, tour[0])
+	is.Equal(0, tour[len(tour)-1])
+}
+
+func TestPlanWithoutStops(t *testing.T) {
+	is := is.New(t)
+
+	ordered, distance := Plan(Point{62, 17}, nil)
+	is.Equal(0, len(ordered))
+	is.Equal(0.0, distance)
+}
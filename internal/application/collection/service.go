@@ -0,0 +1,92 @@
+package collection
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/collection")
+
+const wasteContainerType = "Container-WasteContainer"
+
+type Service struct {
+	things things.Finder
+}
+
+func NewService(things things.Finder) *Service {
+	return &Service{things: things}
+}
+
+// PlanCollectionRun finds the waste containers that are filled above the threshold and orders
+// them into a run from and back to the depot.
+func (s *Service) PlanCollectionRun(ctx context.Context, criteria Criteria) (Run, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "plan-collection-run")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	if criteria.Depot.IsZero() {
+		err = errors.New("a collection run needs a depot")
+		return Run{}, err
+	}
+
+	args := map[string][]string{"type": {wasteContainerType}}
+	if len(criteria.Tags) > 0 {
+		args["tags"] = criteria.Tags
+	}
+
+	containers, err := things.GetAllThings(ctx, s.things, args)
+	if err != nil {
+		return Run{}, err
+	}
+
+	run := Run{Criteria: criteria, CreatedAt: time.Now().UTC()}
+	stops := []Stop{}
+
+	for _, t := range containers {
+		stop, ok := toStop(t, criteria)
+		if !ok {
+			continue
+		}
+		if stop.Point.IsZero() {
+			run.Unplaced = append(run.Unplaced, stop)
+			continue
+		}
+		stops = append(stops, stop)
+	}
+
+	// a stable input order gives the same run for the same containers
+	slices.SortFunc(stops, func(a, b Stop) int { return cmp.Compare(a.ThingID, b.ThingID) })
+
+	run.Stops, run.Distance = Plan(criteria.Depot, stops)
+	return run, nil
+}
+
+func toStop(t things.Thing, criteria Criteria) (Stop, bool) {
+	if t.TypeValues.Percent == nil || *t.TypeValues.Percent < criteria.Threshold {
+		return Stop{}, false
+	}
+	if criteria.Tenant != "" && t.Tenant != criteria.Tenant {
+		return Stop{}, false
+	}
+	for _, tag := range criteria.Tags {
+		if !slices.Contains(t.Tags, tag) {
+			return Stop{}, false
+		}
+	}
+
+	return Stop{
+		ThingID: t.ID,
+		Name:    cmp.Or(t.Name, t.ID),
+		Tenant:  t.Tenant,
+		Tags:    t.Tags,
+		Percent: *t.TypeValues.Percent,
+		Point:   Point{Latitude: t.Location.Latitude, Longitude: t.Location.Longitude},
+	}, true
+}
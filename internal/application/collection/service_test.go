@@ -0,0 +1,69 @@
+package collection
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestPlanCollectionRunSelectsContainersAboveThreshold(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(thingstest.Things{All: []things.Thing{
+		container("full", 85, "default", 62.01, 17.0),
+		container("half", 40, "default", 62.02, 17.0),
+		container("other-tenant", 95, "other", 62.03, 17.0),
+		container("no-position", 90, "default", 0, 0),
+	}})
+
+	run, err := svc.PlanCollectionRun(context.Background(), Criteria{Threshold: 80, Tenant: "default", Depot: Point{62, 17}})
+	is.NoErr(err)
+
+	is.Equal(1, len(run.Stops))
+	is.Equal("full", run.Stops[0].ThingID)
+	is.Equal(1, len(run.Unplaced))
+	is.Equal("no-position", run.Unplaced[0].ThingID)
+}
+
+func TestPlanCollectionRunRequiresDepot(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewService(thingstest.Things{}).PlanCollectionRun(context.Background(), Criteria{Threshold: 80})
+	is.True(err != nil)
+}
+
+func TestRunExports(t *testing.T) {
+	is := is.New(t)
+
+	run := Run{
+		Criteria: Criteria{Depot: Point{62, 17}},
+		Stops:    []Stop{{ThingID: "c1", Name: "Container 1", Percent: 91, Point: Point{62.01, 17.02}, Tags: []string{"north", "glass"}}},
+	}
+
+	b, err := run.CSV()
+	is.NoErr(err)
+	is.Equal("order;id;name;latitude;longitude;percent;tenant;tags\n1;c1;Container 1;62.010000;17.020000;91;;north,glass\n", string(b))
+
+	b, err = run.GPX()
+	is.NoErr(err)
+	gpx := string(b)
+	is.True(strings.Contains(gpx, `<wpt lat="62.010000" lon="17.020000">`))
+	is.Equal(3, strings.Count(gpx, "<rtept "))
+}
+
+func container(id string, percent float64, tenant string, lat, lon float64) things.Thing {
+	return things.Thing{
+		ID:         id,
+		Type:       "Container",
+		SubType:    "WasteContainer",
+		Name:       id,
+		Tenant:     tenant,
+		Location:   client.Location{Latitude: lat, Longitude: lon},
+		TypeValues: things.TypeValues{Percent: &percent},
+	}
+}
@@ -0,0 +1,49 @@
+package collection
+
+import (
+	"context"
+	"time"
+)
+
+type Management interface {
+	PlanCollectionRun(ctx context.Context, criteria Criteria) (Run, error)
+}
+
+// Point is a WGS84 position.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (p Point) IsZero() bool {
+	return p.Latitude == 0 && p.Longitude == 0
+}
+
+// Criteria selects the waste containers that should be emptied and where the run starts and ends.
+type Criteria struct {
+	Threshold float64
+	Tenant    string
+	Tags      []string
+	Depot     Point
+}
+
+// Stop is a waste container to visit during a collection run.
+type Stop struct {
+	ThingID string
+	Name    string
+	Tenant  string
+	Tags    []string
+	Percent float64
+	Point   Point
+}
+
+// Run is an ordered collection run that starts and ends at the depot.
+type Run struct {
+	Criteria  Criteria
+	Stops     []Stop
+	Distance  float64
+	CreatedAt time.Time
+
+	// Unplaced holds containers above the threshold that cannot be routed because they lack a position.
+	Unplaced []Stop
+}
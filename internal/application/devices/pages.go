@@ -0,0 +1,33 @@
+package devices
+
+import "context"
+
+// Finder lists devices a page at a time.
+type Finder interface {
+	GetDevices(ctx context.Context, offset, limit int, args map[string][]string) (DeviceResult, error)
+}
+
+const (
+	allDevicesPageSize = 500
+	allDevicesMaxPages = 100
+)
+
+// GetAllDevices pages through f until every device matching args has been fetched.
+func GetAllDevices(ctx context.Context, f Finder, args map[string][]string) ([]Device, error) {
+	devices := []Device{}
+
+	for page := range allDevicesMaxPages {
+		result, err := f.GetDevices(ctx, page*allDevicesPageSize, allDevicesPageSize, args)
+		if err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, result.Devices...)
+
+		if result.Count == 0 || len(result.Devices) == 0 || len(devices) >= result.TotalRecords {
+			break
+		}
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,20 @@
+package geo
+
+import "math"
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+// Distance returns the great circle distance between a and b in meters, by the haversine formula.
+func Distance(a, b Position) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,16 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDistanceBetweenKnownPositions(t *testing.T) {
+	is := is.New(t)
+
+	// Sundsvall to Stockholm is roughly 340 km as the crow flies
+	d := Distance(Position{62.3908, 17.3069}, Position{59.3293, 18.0686})
+	is.True(math.Abs(d-343_000) < 10_000)
+}
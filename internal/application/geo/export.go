@@ -0,0 +1,201 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExportProjections returns the grids that exports are given extra coordinates in: SWEREF 99 TM,
+// which most GIS tools in Sweden read, and the preferred system of the user if that is another grid.
+func ExportProjections(preferred string) []Projection {
+	projections := []Projection{Sweref99TM}
+	if p, ok := Lookup(preferred); ok && p.ID != Sweref99TM.ID {
+		projections = append(projections, p)
+	}
+	return projections
+}
+
+// AddGridCoordinates adds the northing and easting in each projection to an export of the given
+// content type. Exports that are neither CSV nor GeoJSON are returned as they are.
+func AddGridCoordinates(b []byte, contentType string, projections []Projection) ([]byte, error) {
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return AddGridColumns(b, projections)
+	case strings.Contains(contentType, "geo+json"):
+		return AddGridProperties(b, projections)
+	default:
+		return b, nil
+	}
+}
+
+// AddGridColumns appends a northing and an easting column per projection to a CSV with latitude and
+// longitude columns. The delimiter of the CSV is kept. A CSV without such columns is returned as it
+// is, and rows without a position get empty columns.
+func AddGridColumns(b []byte, projections []Projection) ([]byte, error) {
+	header, _, _ := bytes.Cut(b, []byte("\n"))
+	comma := ','
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		comma = ';'
+	}
+
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return b, nil
+	}
+
+	lat := column(rows[0], "latitude", "lat")
+	lon := column(rows[0], "longitude", "lon", "lng")
+	if lat < 0 || lon < 0 {
+		return b, nil
+	}
+
+	for _, p := range projections {
+		rows[0] = append(rows[0], p.Name+" N", p.Name+" E")
+	}
+
+	for i, row := range rows[1:] {
+		position, ok := positionOf(row, lat, lon)
+		for _, p := range projections {
+			if !ok {
+				row = append(row, "", "")
+				continue
+			}
+			northing, easting := p.FromPosition(position)
+			row = append(row, strconv.FormatFloat(northing, 'f', 3, 64), strconv.FormatFloat(easting, 'f', 3, 64))
+		}
+		rows[i+1] = row
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = comma
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AddGridProperties adds the northing and easting per projection to the properties of the points in
+// a GeoJSON feature or feature collection, keyed by the identifier of the projection, such as
+// sweref99tm_n and sweref99tm_e. Only the properties of the points are decoded, so that the other
+// values, such as large integers, are kept as they are.
+func AddGridProperties(b []byte, projections []Projection) ([]byte, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(b, &document); err != nil {
+		return nil, err
+	}
+
+	if _, ok := document["features"]; !ok {
+		return addFeatureGridProperties(b, projections)
+	}
+
+	var features []json.RawMessage
+	if err := json.Unmarshal(document["features"], &features); err != nil {
+		return nil, err
+	}
+
+	for i, f := range features {
+		feature, err := addFeatureGridProperties(f, projections)
+		if err != nil {
+			return nil, err
+		}
+		features[i] = feature
+	}
+
+	collection, err := json.Marshal(features)
+	if err != nil {
+		return nil, err
+	}
+	document["features"] = collection
+
+	return json.Marshal(document)
+}
+
+// addFeatureGridProperties returns a feature with the grid properties added, or the feature as it
+// is if it is not a point with a position.
+func addFeatureGridProperties(b json.RawMessage, projections []Projection) (json.RawMessage, error) {
+	var feature map[string]json.RawMessage
+	if err := json.Unmarshal(b, &feature); err != nil {
+		return nil, err
+	}
+
+	var geometry struct {
+		Type        string `json:"type"`
+		Coordinates []any  `json:"coordinates"`
+	}
+	if err := json.Unmarshal(feature["geometry"], &geometry); err != nil || geometry.Type != "Point" || len(geometry.Coordinates) < 2 {
+		return b, nil
+	}
+	lon, lonOK := geometry.Coordinates[0].(float64)
+	lat, latOK := geometry.Coordinates[1].(float64)
+	if !lonOK || !latOK || (lat == 0 && lon == 0) {
+		return b, nil
+	}
+
+	properties := map[string]json.RawMessage{}
+	if raw, ok := feature["properties"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &properties); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range projections {
+		northing, easting := p.FromPosition(Position{Latitude: lat, Longitude: lon})
+		properties[p.ID+"_n"] = json.RawMessage(strconv.FormatFloat(round(northing), 'f', -1, 64))
+		properties[p.ID+"_e"] = json.RawMessage(strconv.FormatFloat(round(easting), 'f', -1, 64))
+	}
+
+	raw, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	feature["properties"] = raw
+
+	return json.Marshal(feature)
+}
+
+func column(header []string, names ...string) int {
+	return slices.IndexFunc(header, func(h string) bool {
+		return slices.Contains(names, strings.ToLower(strings.TrimSpace(h)))
+	})
+}
+
+func positionOf(row []string, lat, lon int) (Position, bool) {
+	if lat >= len(row) || lon >= len(row) {
+		return Position{}, false
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(row[lat]), 64)
+	if err != nil {
+		return Position{}, false
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(row[lon]), 64)
+	if err != nil {
+		return Position{}, false
+	}
+
+	p := Position{Latitude: latitude, Longitude: longitude}
+	if p == (Position{}) || (BoundingBox{}).Check(p) != nil {
+		return Position{}, false
+	}
+
+	return p, true
+}
+
+// round keeps millimetres, which is more than any of the grids are accurate to
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
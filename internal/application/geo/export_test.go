@@ -0,0 +1,61 @@
+package geo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAddGridColumnsKeepsTheDelimiter(t *testing.T) {
+	is := is.New(t)
+
+	b, err := AddGridColumns([]byte("id;name;latitude;longitude\nc1;Stora torget;62.3908;17.3069\nc2;Nowhere;;\n"), []Projection{Sweref99TM})
+
+	is.NoErr(err)
+	is.Equal("id;name;latitude;longitude;SWEREF 99 TM N;SWEREF 99 TM E\n"+
+		"c1;Stora torget;62.3908;17.3069;6919844.804;619260.250\n"+
+		"c2;Nowhere;;;;\n", string(b))
+}
+
+func TestAddGridColumnsLeavesOtherExportsAlone(t *testing.T) {
+	is := is.New(t)
+
+	csv := []byte("id,value\na,1\n")
+	b, err := AddGridColumns(csv, []Projection{Sweref99TM})
+
+	is.NoErr(err)
+	is.Equal(csv, b)
+}
+
+func TestAddGridPropertiesToPoints(t *testing.T) {
+	is := is.New(t)
+
+	b, err := AddGridProperties([]byte(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[17.3069,62.3908]},"properties":{"name":"Stora torget"}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[0,0]},"properties":{}}
+	]}`), ExportProjections("rt90_25gonv"))
+	is.NoErr(err)
+
+	var collection struct {
+		Features []struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	is.NoErr(json.Unmarshal(b, &collection))
+
+	is.Equal("Stora torget", collection.Features[0].Properties["name"])
+	is.Equal(6919844.804, collection.Features[0].Properties["sweref99tm_n"])
+	is.Equal(1577680.258, collection.Features[0].Properties["rt90_25gonv_e"])
+	is.Equal(0, len(collection.Features[1].Properties))
+}
+
+func TestAddGridPropertiesKeepsLargeIntegers(t *testing.T) {
+	is := is.New(t)
+
+	b, err := AddGridProperties([]byte(`{"type":"Feature","geometry":{"type":"Point","coordinates":[17.3069,62.3908]},"properties":{"serial":9007199254740993}}`), ExportProjections(""))
+	is.NoErr(err)
+	is.True(strings.Contains(string(b), `"serial":9007199254740993`))
+	is.True(strings.Contains(string(b), `"sweref99tm_n":6919844.804`))
+}
@@ -0,0 +1,289 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	decimalComma = regexp.MustCompile(`(\d),(\d)`)
+	number       = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)`)
+)
+
+// marks are the units that may follow the degrees, minutes and seconds of a coordinate
+var marks = []rune{'°', '\'', '"'}
+
+// axis is one half of a pasted position, such as the latitude or the easting
+type axis struct {
+	values     []float64
+	marks      []rune
+	negative   bool
+	hemisphere rune
+}
+
+// Parse reads a position that has been pasted from another system. It accepts decimal degrees such
+// as "62.3908, 17.3069", degrees, minutes and seconds such as 62°23'26.9"N 17°18'24.8"E, and
+// SWEREF 99 TM such as "N 6919000 E 617000". Without hemispheres the latitude is expected first, as
+// in most map services, while a northing is told from the easting by its size.
+func Parse(s string) (Position, error) {
+	return ParseIn(s, Sweref99TM)
+}
+
+// ParseIn reads a position like Parse, but takes northings and eastings to be in the given grid.
+func ParseIn(s string, projection Projection) (Position, error) {
+	axes, err := split(normalize(s))
+	if err != nil {
+		return Position{}, err
+	}
+
+	if len(axes) != 2 {
+		return Position{}, fmt.Errorf("%w: expected two coordinates in %q", ErrInvalid, s)
+	}
+
+	var p Position
+	if axes[0].projected() && axes[1].projected() {
+		p, err = grid(axes[0], axes[1], projection)
+	} else {
+		p, err = geographic(axes[0], axes[1])
+	}
+	if err != nil {
+		return Position{}, fmt.Errorf("%w in %q", err, s)
+	}
+
+	return p, BoundingBox{}.Check(p)
+}
+
+// ParseBoundingBox reads a bounding box written as minimum longitude, minimum latitude, maximum
+// longitude and maximum latitude separated by commas, the order used by GeoJSON. An empty string is
+// the zero bounding box.
+func ParseBoundingBox(s string) (BoundingBox, error) {
+	if strings.TrimSpace(s) == "" {
+		return BoundingBox{}, nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, fmt.Errorf("%w: a bounding box needs four values, got %q", ErrInvalid, s)
+	}
+
+	values := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, fmt.Errorf("%w: %q is not a number", ErrInvalid, part)
+		}
+		values = append(values, v)
+	}
+
+	b := BoundingBox{MinLongitude: values[0], MinLatitude: values[1], MaxLongitude: values[2], MaxLatitude: values[3]}
+	for _, corner := range []Position{{b.MinLatitude, b.MinLongitude}, {b.MaxLatitude, b.MaxLongitude}} {
+		if err := (BoundingBox{}).Check(corner); err != nil {
+			return BoundingBox{}, err
+		}
+	}
+	if b.MinLatitude >= b.MaxLatitude || b.MinLongitude >= b.MaxLongitude {
+		return BoundingBox{}, fmt.Errorf("%w: the minimum of %q must be below the maximum", ErrInvalid, s)
+	}
+
+	return b, nil
+}
+
+// normalize turns the many ways of writing marks and decimals into one. Commas are taken as
+// decimal separators unless there is exactly one, which then separates the two coordinates.
+func normalize(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.NewReplacer("''", `"`, "′′", `"`, "′", "'", "″", `"`, "º", "°", "’", "'", "”", `"`, ":", " ", "=", " ").Replace(s)
+
+	if !strings.Contains(s, ".") && strings.Count(s, ",") != 1 {
+		s = decimalComma.ReplaceAllString(s, "$1.$2")
+	}
+
+	return s
+}
+
+// split reads the axes of a position. An axis ends at a comma or semicolon, at a hemisphere after its
+// values, or where the degrees of the next axis begin. A run of values without any of those is split
+// in two halves.
+func split(s string) ([]axis, error) {
+	axes := []axis{}
+	current := axis{}
+
+	closeAxis := func() {
+		if len(current.values) > 0 || current.hemisphere != 0 {
+			axes = append(axes, current)
+		}
+		current = axis{}
+	}
+
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += width
+		case r == ',' || r == ';':
+			closeAxis()
+			i += width
+		case strings.ContainsRune("NSEWOÖV", r):
+			switch {
+			case len(current.values) == 0 && current.hemisphere != 0:
+				return nil, fmt.Errorf("%w: two hemispheres in a row", ErrInvalid)
+			case len(current.values) == 0:
+				current.hemisphere = r
+			case current.hemisphere == 0:
+				current.hemisphere = r
+				closeAxis()
+			default:
+				closeAxis()
+				current.hemisphere = r
+			}
+			i += width
+		default:
+			match := number.FindString(s[i:])
+			if match == "" {
+				return nil, fmt.Errorf("%w: unexpected %q", ErrInvalid, string(r))
+			}
+			i += len(match)
+
+			mark := rune(0)
+			if i < len(s) {
+				if m, w := utf8.DecodeRuneInString(s[i:]); strings.ContainsRune(string(marks), m) {
+					mark = m
+					i += w
+				}
+			}
+
+			negative := strings.HasPrefix(match, "-")
+			if n := len(current.values); n > 0 && (n == len(marks) || mark == '°' || negative || current.marks[n-1] == '"') {
+				closeAxis()
+			}
+
+			value, err := strconv.ParseFloat(match, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not a number", ErrInvalid, match)
+			}
+			if negative {
+				current.negative = true
+				value = -value // the sign is kept by the axis
+			}
+
+			current.values = append(current.values, value)
+			current.marks = append(current.marks, mark)
+		}
+	}
+	closeAxis()
+
+	if len(axes) == 1 && axes[0].hemisphere == 0 && !axes[0].marked() {
+		if n := len(axes[0].values); n%2 == 0 && n <= 2*len(marks) {
+			whole := axes[0]
+			axes = []axis{
+				{values: whole.values[:n/2], marks: whole.marks[:n/2], negative: whole.negative},
+				{values: whole.values[n/2:], marks: whole.marks[n/2:]},
+			}
+		}
+	}
+
+	return axes, nil
+}
+
+func (a axis) marked() bool {
+	for _, m := range a.marks {
+		if m != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// projected reports if the axis is a distance in a grid rather than an angle
+func (a axis) projected() bool {
+	return len(a.values) == 1 && a.marks[0] == 0 && math.Abs(a.values[0]) > 360
+}
+
+// degrees returns the angle of the axis in decimal degrees
+func (a axis) degrees() (float64, error) {
+	if len(a.values) == 0 || len(a.values) > len(marks) {
+		return 0, fmt.Errorf("%w: an angle has degrees, minutes and seconds", ErrInvalid)
+	}
+
+	total := 0.0
+	for i, v := range a.values {
+		if a.marks[i] != 0 && a.marks[i] != marks[i] {
+			return 0, fmt.Errorf("%w: %q in the wrong place", ErrInvalid, string(a.marks[i]))
+		}
+		if i > 0 && (v >= 60 || a.values[i-1] != math.Trunc(a.values[i-1])) {
+			return 0, fmt.Errorf("%w: minutes and seconds must be below 60 and follow whole units", ErrInvalid)
+		}
+		total += v / math.Pow(60, float64(i))
+	}
+
+	switch {
+	case a.negative && (a.hemisphere == 'S' || a.hemisphere == 'W' || a.hemisphere == 'V'):
+		return 0, fmt.Errorf("%w: both a minus sign and a hemisphere", ErrInvalid)
+	case a.negative:
+		return -total, nil
+	case a.hemisphere == 'S' || a.hemisphere == 'W' || a.hemisphere == 'V':
+		return -total, nil
+	default:
+		return total, nil
+	}
+}
+
+func (a axis) isLatitude() bool {
+	return a.hemisphere == 'N' || a.hemisphere == 'S'
+}
+
+func (a axis) isLongitude() bool {
+	return a.hemisphere != 0 && !a.isLatitude()
+}
+
+func geographic(first, second axis) (Position, error) {
+	if first.projected() || second.projected() {
+		return Position{}, fmt.Errorf("%w: a mix of degrees and metres", ErrInvalid)
+	}
+
+	latitude, longitude := first, second
+	if first.isLongitude() || second.isLatitude() {
+		latitude, longitude = second, first
+	}
+	if latitude.isLongitude() || longitude.isLatitude() {
+		return Position{}, fmt.Errorf("%w: two coordinates in the same direction", ErrInvalid)
+	}
+
+	lat, err := latitude.degrees()
+	if err != nil {
+		return Position{}, err
+	}
+	lon, err := longitude.degrees()
+	if err != nil {
+		return Position{}, err
+	}
+
+	return Position{Latitude: lat, Longitude: lon}, nil
+}
+
+func grid(first, second axis, projection Projection) (Position, error) {
+	for _, a := range []axis{first, second} {
+		if a.negative || (a.hemisphere != 0 && a.hemisphere != 'N' && a.hemisphere != 'E' && a.hemisphere != 'O' && a.hemisphere != 'Ö') {
+			return Position{}, fmt.Errorf("%w: a grid position has a northing and an easting", ErrInvalid)
+		}
+	}
+
+	northing, easting := first, second
+	switch {
+	case first.isLongitude() || second.isLatitude():
+		northing, easting = second, first
+	case first.hemisphere == 0 && second.hemisphere == 0 && second.values[0] > first.values[0]:
+		northing, easting = second, first
+	}
+	if northing.isLongitude() || easting.isLatitude() {
+		return Position{}, fmt.Errorf("%w: two coordinates in the same direction", ErrInvalid)
+	}
+
+	return projection.ToPosition(northing.values[0], easting.values[0]), nil
+}
@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseAcceptsDecimalDegrees(t *testing.T) {
+	is := is.New(t)
+
+	for _, s := range []string{"62.3908, 17.3069", "62,3908, 17,3069", "62.3908 17.3069", "62.3908;17.3069", "17.3069E 62.3908N", "N 62.3908 Ö 17.3069"} {
+		p, err := Parse(s)
+		is.NoErr(err)
+		is.True(near(p, Position{Latitude: 62.3908, Longitude: 17.3069}, 1e-9))
+	}
+}
+
+func TestParseAcceptsDegreesMinutesAndSeconds(t *testing.T) {
+	is := is.New(t)
+
+	p, err := Parse(`62°23'26.9"N 17°18'24.8"E`)
+	is.NoErr(err)
+	is.True(near(p, Position{Latitude: 62.390806, Longitude: 17.306889}, 1e-6))
+
+	p, err = Parse("N 62° 23.448′ E 17° 18.414′")
+	is.NoErr(err)
+	is.True(near(p, Position{Latitude: 62.3908, Longitude: 17.3069}, 1e-9))
+
+	p, err = Parse("33 51 36 S, 151 12 0 E")
+	is.NoErr(err)
+	is.True(near(p, Position{Latitude: -33.86, Longitude: 151.2}, 1e-9))
+}
+
+func TestParseAcceptsSweref99TMInEitherOrder(t *testing.T) {
+	is := is.New(t)
+
+	for _, s := range []string{"N 6919000 E 617000", "6919000, 617000", "617000 6919000", "E 617000 N 6919000"} {
+		p, err := Parse(s)
+		is.NoErr(err)
+		is.True(near(p, Position{Latitude: 62.383940, Longitude: 17.262648}, 1e-6))
+	}
+}
+
+func TestParseRejectsWhatIsNotAPosition(t *testing.T) {
+	is := is.New(t)
+
+	for _, s := range []string{"", "abc", "62.39", "N 62 N 17", "6919000 17.3", `62°75'N 17°E`, "95, 17", "-62 S, 17"} {
+		_, err := Parse(s)
+		is.True(errors.Is(err, ErrInvalid))
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	is := is.New(t)
+
+	b, err := ParseBoundingBox("16.5, 62.1, 17.9, 62.75")
+	is.NoErr(err)
+	is.Equal(BoundingBox{MinLongitude: 16.5, MinLatitude: 62.1, MaxLongitude: 17.9, MaxLatitude: 62.75}, b)
+
+	is.NoErr(b.Check(Position{Latitude: 62.3908, Longitude: 17.3069}))
+	is.True(errors.Is(b.Check(Position{Latitude: 0, Longitude: 0}), ErrOutside))
+	is.True(errors.Is(b.Check(Position{Latitude: 91, Longitude: 17}), ErrInvalid))
+
+	b, err = ParseBoundingBox("")
+	is.NoErr(err)
+	is.True(b.Contains(Position{Latitude: -45, Longitude: 170}))
+
+	for _, s := range []string{"16.5,62.1,17.9", "17.9,62.1,16.5,62.75", "16.5,62.1,17.9,north"} {
+		_, err = ParseBoundingBox(s)
+		is.True(errors.Is(err, ErrInvalid))
+	}
+}
+
+func near(p, q Position, tolerance float64) bool {
+	return math.Abs(p.Latitude-q.Latitude) < tolerance && math.Abs(p.Longitude-q.Longitude) < tolerance
+}
+
+func TestParseInReadsGridPositionsInTheGivenProjection(t *testing.T) {
+	is := is.New(t)
+
+	p, err := ParseIn("6920756.946 1577680.258", RT90)
+	is.NoErr(err)
+	is.True(near(p, Position{Latitude: 62.3908, Longitude: 17.3069}, 1e-8))
+}
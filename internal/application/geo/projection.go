@@ -0,0 +1,186 @@
+package geo
+
+import (
+	"math"
+	"slices"
+)
+
+// WGS84 is the identifier of the reference system of the things service and the map, in which
+// positions are given as latitude and longitude rather than in a grid.
+const WGS84 = "wgs84"
+
+// Projection is a transverse Mercator grid used by Swedish authorities, converted with the
+// Gauss-Krüger formulas published by Lantmäteriet. Every grid is defined on the GRS 80 ellipsoid,
+// which for practical purposes coincides with WGS 84. RT90 uses the parameters that Lantmäteriet
+// publishes for converting to it directly from SWEREF 99, with an accuracy of about a metre.
+type Projection struct {
+	ID   string
+	Name string
+
+	semiMajorAxis   float64
+	flattening      float64
+	centralMeridian float64
+	scale           float64
+	falseNorthing   float64
+	falseEasting    float64
+}
+
+const (
+	grs80SemiMajorAxis = 6378137.0
+	grs80Flattening    = 1.0 / 298.257222101
+)
+
+// Sweref99TM is the national grid of Sweden.
+var Sweref99TM = Projection{
+	ID:              "sweref99tm",
+	Name:            "SWEREF 99 TM",
+	semiMajorAxis:   grs80SemiMajorAxis,
+	flattening:      grs80Flattening,
+	centralMeridian: 15.0,
+	scale:           0.9996,
+	falseEasting:    500000.0,
+}
+
+// RT90 is the grid that was used in Sweden before SWEREF 99, still found in older data.
+var RT90 = Projection{
+	ID:              "rt90_25gonv",
+	Name:            "RT90 2.5 gon V",
+	semiMajorAxis:   grs80SemiMajorAxis,
+	flattening:      grs80Flattening,
+	centralMeridian: 15.0 + 48.0/60 + 22.624306/3600,
+	scale:           1.00000561024,
+	falseNorthing:   -667.711,
+	falseEasting:    1500064.274,
+}
+
+// Projections are the grids that positions can be shown in and read from.
+var Projections = []Projection{
+	Sweref99TM,
+	zone("1200", 12.0),
+	zone("1330", 13.5),
+	zone("1415", 14.25),
+	zone("1500", 15.0),
+	zone("1545", 15.75),
+	zone("1630", 16.5),
+	zone("1715", 17.25),
+	zone("1800", 18.0),
+	zone("1845", 18.75),
+	zone("2015", 20.25),
+	zone("2145", 21.75),
+	zone("2315", 23.25),
+	RT90,
+}
+
+// zone returns one of the local SWEREF 99 grids that municipalities use for their maps.
+func zone(name string, centralMeridian float64) Projection {
+	return Projection{
+		ID:              "sweref99_" + name,
+		Name:            "SWEREF 99 " + name[:2] + " " + name[2:],
+		semiMajorAxis:   grs80SemiMajorAxis,
+		flattening:      grs80Flattening,
+		centralMeridian: centralMeridian,
+		scale:           1.0,
+		falseEasting:    150000.0,
+	}
+}
+
+// Systems returns the identifiers of every system that positions can be shown in, WGS 84 first.
+func Systems() []string {
+	systems := []string{WGS84}
+	for _, p := range Projections {
+		systems = append(systems, p.ID)
+	}
+	return systems
+}
+
+// Lookup returns the projection with the given identifier.
+func Lookup(id string) (Projection, bool) {
+	i := slices.IndexFunc(Projections, func(p Projection) bool { return p.ID == id })
+	if i < 0 {
+		return Projection{}, false
+	}
+	return Projections[i], true
+}
+
+// ToPosition converts a northing and easting in the grid to a position.
+func (tm Projection) ToPosition(northing, easting float64) Position {
+	f := tm.flattening
+	e2 := f * (2 - f)
+	n := f / (2 - f)
+	aRoof := tm.semiMajorAxis / (1 + n) * (1 + n*n/4 + n*n*n*n/64)
+
+	delta1 := n/2 - 2*n*n/3 + 37*n*n*n/96 - n*n*n*n/360
+	delta2 := n*n/48 + n*n*n/15 - 437*n*n*n*n/1440
+	delta3 := 17*n*n*n/480 - 37*n*n*n*n/840
+	delta4 := 4397 * n * n * n * n / 161280
+
+	aStar := e2 + e2*e2 + e2*e2*e2 + e2*e2*e2*e2
+	bStar := -(7*e2*e2 + 17*e2*e2*e2 + 30*e2*e2*e2*e2) / 6
+	cStar := (224*e2*e2*e2 + 889*e2*e2*e2*e2) / 120
+	dStar := -(4279 * e2 * e2 * e2 * e2) / 1260
+
+	xi := (northing - tm.falseNorthing) / (tm.scale * aRoof)
+	eta := (easting - tm.falseEasting) / (tm.scale * aRoof)
+
+	xiPrim := xi -
+		delta1*math.Sin(2*xi)*math.Cosh(2*eta) -
+		delta2*math.Sin(4*xi)*math.Cosh(4*eta) -
+		delta3*math.Sin(6*xi)*math.Cosh(6*eta) -
+		delta4*math.Sin(8*xi)*math.Cosh(8*eta)
+	etaPrim := eta -
+		delta1*math.Cos(2*xi)*math.Sinh(2*eta) -
+		delta2*math.Cos(4*xi)*math.Sinh(4*eta) -
+		delta3*math.Cos(6*xi)*math.Sinh(6*eta) -
+		delta4*math.Cos(8*xi)*math.Sinh(8*eta)
+
+	phiStar := math.Asin(math.Sin(xiPrim) / math.Cosh(etaPrim))
+	deltaLambda := math.Atan(math.Sinh(etaPrim) / math.Cos(xiPrim))
+
+	sin2 := math.Sin(phiStar) * math.Sin(phiStar)
+	phi := phiStar + math.Sin(phiStar)*math.Cos(phiStar)*(aStar+bStar*sin2+cStar*sin2*sin2+dStar*sin2*sin2*sin2)
+
+	return Position{
+		Latitude:  phi * 180 / math.Pi,
+		Longitude: tm.centralMeridian + deltaLambda*180/math.Pi,
+	}
+}
+
+// FromPosition converts a position to a northing and easting in the grid.
+func (tm Projection) FromPosition(p Position) (northing, easting float64) {
+	f := tm.flattening
+	e2 := f * (2 - f)
+	n := f / (2 - f)
+	aRoof := tm.semiMajorAxis / (1 + n) * (1 + n*n/4 + n*n*n*n/64)
+
+	a := e2
+	b := (5*e2*e2 - e2*e2*e2) / 6
+	c := (104*e2*e2*e2 - 45*e2*e2*e2*e2) / 120
+	d := (1237 * e2 * e2 * e2 * e2) / 1260
+
+	beta1 := n/2 - 2*n*n/3 + 5*n*n*n/16 + 41*n*n*n*n/180
+	beta2 := 13*n*n/48 - 3*n*n*n/5 + 557*n*n*n*n/1440
+	beta3 := 61*n*n*n/240 - 103*n*n*n*n/140
+	beta4 := 49561 * n * n * n * n / 161280
+
+	phi := p.Latitude * math.Pi / 180
+	deltaLambda := (p.Longitude - tm.centralMeridian) * math.Pi / 180
+
+	sin2 := math.Sin(phi) * math.Sin(phi)
+	phiStar := phi - math.Sin(phi)*math.Cos(phi)*(a+b*sin2+c*sin2*sin2+d*sin2*sin2*sin2)
+
+	xiPrim := math.Atan(math.Tan(phiStar) / math.Cos(deltaLambda))
+	etaPrim := math.Atanh(math.Cos(phiStar) * math.Sin(deltaLambda))
+
+	northing = tm.scale*aRoof*(xiPrim+
+		beta1*math.Sin(2*xiPrim)*math.Cosh(2*etaPrim)+
+		beta2*math.Sin(4*xiPrim)*math.Cosh(4*etaPrim)+
+		beta3*math.Sin(6*xiPrim)*math.Cosh(6*etaPrim)+
+		beta4*math.Sin(8*xiPrim)*math.Cosh(8*etaPrim)) + tm.falseNorthing
+	easting = tm.scale*aRoof*(etaPrim+
+		beta1*math.Cos(2*xiPrim)*math.Sinh(2*etaPrim)+
+		beta2*math.Cos(4*xiPrim)*math.Sinh(4*etaPrim)+
+		beta3*math.Cos(6*xiPrim)*math.Sinh(6*etaPrim)+
+		beta4*math.Cos(8*xiPrim)*math.Sinh(8*etaPrim)) + tm.falseEasting
+
+	return northing, easting
+}
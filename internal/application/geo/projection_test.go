@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSweref99TMFollowsTheCentralMeridian(t *testing.T) {
+	is := is.New(t)
+
+	// the meridian arc from the equator to 60° N on GRS 80 is 6 654 072.8 m, scaled by 0.9996
+	p := Sweref99TM.ToPosition(6651411.2, 500000)
+
+	is.True(near(p, Position{Latitude: 60, Longitude: 15}, 1e-6))
+	is.True(near(Sweref99TM.ToPosition(0, 500000), Position{Longitude: 15}, 1e-9))
+}
+
+func TestProjectionsConvertBothWays(t *testing.T) {
+	is := is.New(t)
+
+	p := Position{Latitude: 62.3908, Longitude: 17.3069}
+	for _, projection := range Projections {
+		northing, easting := projection.FromPosition(p)
+		is.True(near(projection.ToPosition(northing, easting), p, 1e-9))
+	}
+
+	northing, easting := Sweref99TM.FromPosition(p)
+	is.True(math.Abs(northing-6919844.804) < 0.01)
+	is.True(math.Abs(easting-619260.250) < 0.01)
+}
+
+func TestRT90IsShiftedFromSweref99(t *testing.T) {
+	is := is.New(t)
+
+	northing, easting := RT90.FromPosition(Position{Latitude: 60, Longitude: RT90.centralMeridian})
+
+	is.True(math.Abs(northing-(6654072.8*1.00000561024-667.711)) < 0.1)
+	is.True(math.Abs(easting-1500064.274) < 0.001)
+}
+
+func TestLookupFindsLocalZones(t *testing.T) {
+	is := is.New(t)
+
+	zone, ok := Lookup("sweref99_1630")
+	is.True(ok)
+	is.Equal("SWEREF 99 16 30", zone.Name)
+
+	_, ok = Lookup(WGS84)
+	is.True(!ok)
+	is.Equal(WGS84, Systems()[0])
+}
@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalid = errors.New("invalid coordinates")
+	ErrOutside = errors.New("coordinates are outside of the area")
+)
+
+// Position is a point in WGS 84, the reference system of the things service and the map.
+type Position struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%.6f, %.6f", p.Latitude, p.Longitude)
+}
+
+// BoundingBox is the area that things may be placed in, usually the municipality. The zero value
+// contains every position on earth.
+type BoundingBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+func (b BoundingBox) IsZero() bool {
+	return b == BoundingBox{}
+}
+
+func (b BoundingBox) Contains(p Position) bool {
+	if b.IsZero() {
+		return true
+	}
+	return p.Latitude >= b.MinLatitude && p.Latitude <= b.MaxLatitude &&
+		p.Longitude >= b.MinLongitude && p.Longitude <= b.MaxLongitude
+}
+
+// Check returns ErrInvalid if p is not a position on earth and ErrOutside if it lies outside of b.
+func (b BoundingBox) Check(p Position) error {
+	if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+		return fmt.Errorf("%w: %s is not a position on earth", ErrInvalid, p)
+	}
+	if !b.Contains(p) {
+		return fmt.Errorf("%w: %s", ErrOutside, p)
+	}
+	return nil
+}
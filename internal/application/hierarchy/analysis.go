@@ -0,0 +1,79 @@
+package hierarchy
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// Ancestors returns the things that a thing is part of, the nearest first.
+func Ancestors(parents map[string]string, thingID string) []string {
+	ancestors := []string{}
+	for id := parents[thingID]; id != "" && len(ancestors) < maxDepth; id = parents[id] {
+		if id == thingID || slices.Contains(ancestors, id) {
+			break
+		}
+		ancestors = append(ancestors, id)
+	}
+	return ancestors
+}
+
+// Descendants returns the things that are part of a thing at any depth, the nearest first.
+func Descendants(parents map[string]string, thingID string) []string {
+	children := childrenOf(parents)
+
+	descendants := []string{}
+	queue := []string{thingID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if child == thingID || slices.Contains(descendants, child) {
+				continue
+			}
+			descendants = append(descendants, child)
+			queue = append(queue, child)
+		}
+	}
+	return descendants
+}
+
+func childrenOf(parents map[string]string) map[string][]string {
+	children := map[string][]string{}
+	for child, parent := range parents {
+		children[parent] = append(children[parent], child)
+	}
+	for _, ids := range children {
+		slices.Sort(ids)
+	}
+	return children
+}
+
+// tree returns the things that are part of a thing ordered by type and name. A thing that could not
+// be fetched is left out together with the things that are part of it.
+func tree(thingID string, children map[string][]string, all map[string]things.Thing) []Node {
+	return buildTree(thingID, children, all, map[string]bool{thingID: true})
+}
+
+func buildTree(thingID string, children map[string][]string, all map[string]things.Thing, visited map[string]bool) []Node {
+	nodes := []Node{}
+	for _, id := range children[thingID] {
+		t, ok := all[id]
+		if !ok || visited[id] {
+			continue
+		}
+		visited[id] = true
+		nodes = append(nodes, Node{Thing: t, Children: buildTree(id, children, all, visited)})
+	}
+
+	slices.SortFunc(nodes, func(a, b Node) int {
+		return cmp.Or(
+			cmp.Compare(a.Thing.Type, b.Thing.Type),
+			cmp.Compare(strings.ToLower(a.Thing.Name), strings.ToLower(b.Thing.Name)),
+		)
+	})
+
+	return nodes
+}
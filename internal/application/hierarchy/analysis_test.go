@@ -0,0 +1,52 @@
+package hierarchy
+
+import (
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+func TestAncestorsStopsAtALoop(t *testing.T) {
+	is := is.New(t)
+
+	parents := map[string]string{"desk": "room", "room": "building"}
+	is.Equal([]string{"room", "building"}, Ancestors(parents, "desk"))
+
+	parents["building"] = "desk"
+	is.Equal([]string{"room", "building"}, Ancestors(parents, "desk"))
+}
+
+func TestDescendantsAreNearestFirst(t *testing.T) {
+	is := is.New(t)
+
+	parents := map[string]string{"desk-1": "room-1", "desk-2": "room-1", "room-1": "building", "room-2": "building", "other": "elsewhere"}
+	is.Equal([]string{"room-1", "room-2", "desk-1", "desk-2"}, Descendants(parents, "building"))
+	is.Equal([]string{}, Descendants(parents, "desk-1"))
+}
+
+func TestRollupSumsEveryThingThatIsPartOfTheThing(t *testing.T) {
+	is := is.New(t)
+
+	h := Hierarchy{
+		Children: []Node{
+			{
+				Thing: things.Thing{ID: "room-1", TypeValues: things.TypeValues{Power: new(2.0), Energy: new(10.0), Presence: new(true)}},
+				Children: []Node{
+					{Thing: things.Thing{ID: "desk-1", TypeValues: things.TypeValues{Presence: new(false)}}},
+					{Thing: things.Thing{ID: "desk-2", TypeValues: things.TypeValues{Presence: new(true)}}},
+				},
+			},
+			{Thing: things.Thing{ID: "room-2", TypeValues: things.TypeValues{Power: new(1.5)}}},
+		},
+	}
+
+	r := h.Rollup()
+	is.Equal(4, r.Things)
+	is.Equal(3.5, *r.Power)
+	is.Equal(10.0, *r.Energy)
+	is.Equal(2, r.Present)
+	is.Equal(3, r.Occupiable)
+
+	is.True(Hierarchy{}.Rollup().Power == nil)
+}
@@ -0,0 +1,227 @@
+package hierarchy
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/hierarchy")
+
+const (
+	relationsCollection = "hierarchy"
+
+	// maxDepth stops the walk up to the outermost thing should the relations ever form a loop
+	maxDepth = 16
+)
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+	store  storage.Store
+}
+
+func NewService(things thingSource, store storage.Store) *Service {
+	return &Service{things: things, store: store}
+}
+
+type relation struct {
+	ThingID  string `json:"thingID"`
+	ParentID string `json:"parentID"`
+}
+
+// GetHierarchy returns a thing together with the things it is part of and the tree of things that
+// are part of it. Related things that can no longer be fetched are left out.
+func (s *Service) GetHierarchy(ctx context.Context, thingID string) (Hierarchy, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-hierarchy")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	parents, err := s.relations(ctx)
+	if err != nil {
+		return Hierarchy{}, err
+	}
+
+	thing, err := s.things.GetThing(ctx, thingID, nil)
+	if err != nil {
+		return Hierarchy{}, err
+	}
+	thing.Parent = parents[thingID]
+
+	h := Hierarchy{Thing: thing}
+
+	// the walk up stops at the first thing that cannot be fetched
+	ids := Ancestors(parents, thingID)
+	ancestors := s.fetch(ctx, ids, parents)
+	for _, id := range ids {
+		t, ok := ancestors[id]
+		if !ok {
+			break
+		}
+		h.Ancestors = append([]things.Thing{t}, h.Ancestors...)
+	}
+
+	children := childrenOf(parents)
+	descendants := s.fetch(ctx, Descendants(parents, thingID), parents)
+	h.Children = tree(thingID, children, descendants)
+
+	return h, nil
+}
+
+// GetParentCandidates returns the things that a thing can be made part of. The things that are
+// already part of the thing are left out, so that the relations never form a loop.
+func (s *Service) GetParentCandidates(ctx context.Context, thingID string) ([]things.Thing, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-parent-candidates")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	thing, err := s.things.GetThing(ctx, thingID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	types, ok := ParentTypes[strings.ToLower(thing.Type)]
+	if !ok {
+		return []things.Thing{}, nil
+	}
+
+	parents, err := s.relations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": types})
+	if err != nil {
+		return nil, err
+	}
+
+	descendants := Descendants(parents, thingID)
+	candidates = slices.DeleteFunc(candidates, func(t things.Thing) bool {
+		return t.ID == thingID || t.Tenant != thing.Tenant || slices.Contains(descendants, t.ID) ||
+			!slices.ContainsFunc(types, func(p string) bool { return strings.EqualFold(p, t.Type) })
+	})
+	slices.SortFunc(candidates, func(a, b things.Thing) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)))
+	})
+
+	return candidates, nil
+}
+
+// SetParent makes a thing part of another thing, or of no thing if parentID is empty.
+func (s *Service) SetParent(ctx context.Context, thingID, parentID string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "set-parent")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	if parentID == "" {
+		err = s.store.Delete(ctx, relationsCollection, thingID)
+		if errors.Is(err, storage.ErrNotFound) {
+			err = nil
+		}
+		return err
+	}
+
+	if parentID == thingID {
+		err = errors.Join(ErrInvalid, errors.New("a thing cannot be part of itself"))
+		return err
+	}
+
+	parents, err := s.relations(ctx)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(Ancestors(parents, parentID), thingID) {
+		err = errors.Join(ErrInvalid, errors.New("a thing cannot be part of a thing that is part of it"))
+		return err
+	}
+
+	thing, err := s.things.GetThing(ctx, thingID, nil)
+	if err != nil {
+		return err
+	}
+	parent, err := s.things.GetThing(ctx, parentID, nil)
+	if err != nil {
+		return err
+	}
+
+	types := ParentTypes[strings.ToLower(thing.Type)]
+	if !slices.ContainsFunc(types, func(p string) bool { return strings.EqualFold(p, parent.Type) }) {
+		err = errors.Join(ErrInvalid, errors.New("a "+strings.ToLower(thing.Type)+" cannot be part of a "+strings.ToLower(parent.Type)))
+		return err
+	}
+	if thing.Tenant != parent.Tenant {
+		err = errors.Join(ErrInvalid, errors.New("a thing cannot be part of a thing of another tenant"))
+		return err
+	}
+
+	document, err := json.Marshal(relation{ThingID: thingID, ParentID: parentID})
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Put(ctx, relationsCollection, thingID, document)
+	return err
+}
+
+// relations returns the parent of every thing that is part of another thing.
+func (s *Service) relations(ctx context.Context) (map[string]string, error) {
+	documents, err := s.store.List(ctx, relationsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := map[string]string{}
+	for _, document := range documents {
+		var r relation
+		if err := json.Unmarshal(document, &r); err != nil {
+			return nil, err
+		}
+		parents[r.ThingID] = r.ParentID
+	}
+
+	return parents, nil
+}
+
+// fetch returns the latest state of the things with the given ids, leaving out those that could not
+// be fetched.
+func (s *Service) fetch(ctx context.Context, ids []string, parents map[string]string) map[string]things.Thing {
+	log := logging.GetFromContext(ctx)
+
+	stubs := make([]things.Thing, 0, len(ids))
+	for _, id := range ids {
+		stubs = append(stubs, things.Thing{ID: id})
+	}
+
+	fetched := make([]*things.Thing, len(stubs))
+	things.ForEach(stubs, func(i int, t things.Thing) {
+		thing, err := s.things.GetThing(ctx, t.ID, nil)
+		if err != nil {
+			log.Debug("could not fetch related thing", "thing_id", t.ID, "err", err.Error())
+			return
+		}
+		thing.Parent = parents[t.ID]
+		fetched[i] = &thing
+	})
+
+	result := map[string]things.Thing{}
+	for i, t := range fetched {
+		if t != nil {
+			result[stubs[i].ID] = *t
+		}
+	}
+
+	return result
+}
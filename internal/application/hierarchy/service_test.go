@@ -0,0 +1,80 @@
+package hierarchy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestSetParentRejectsLoopsAndUnsupportedTypes(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	is.NoErr(svc.SetParent(ctx, "room-1", "building"))
+	is.NoErr(svc.SetParent(ctx, "desk-1", "room-1"))
+
+	is.True(errors.Is(svc.SetParent(ctx, "room-1", "room-1"), ErrInvalid))
+	is.True(errors.Is(svc.SetParent(ctx, "room-1", "desk-1"), ErrInvalid))
+	is.True(errors.Is(svc.SetParent(ctx, "building", "room-1"), ErrInvalid))
+	is.True(errors.Is(svc.SetParent(ctx, "room-2", "other-building"), ErrInvalid))
+
+	is.NoErr(svc.SetParent(ctx, "desk-1", ""))
+	is.NoErr(svc.SetParent(ctx, "desk-1", ""))
+}
+
+func TestGetHierarchyReturnsAncestorsAndTree(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	svc := newTestService(t)
+
+	is.NoErr(svc.SetParent(ctx, "room-1", "building"))
+	is.NoErr(svc.SetParent(ctx, "room-2", "building"))
+	is.NoErr(svc.SetParent(ctx, "desk-1", "room-1"))
+
+	h, err := svc.GetHierarchy(ctx, "building")
+	is.NoErr(err)
+	is.Equal(0, len(h.Ancestors))
+	is.Equal(2, len(h.Children))
+	is.Equal("room-1", h.Children[0].Thing.ID)
+	is.Equal("building", h.Children[0].Thing.Parent)
+	is.Equal("desk-1", h.Children[0].Children[0].Thing.ID)
+	is.Equal(1, h.Rollup().Present)
+
+	h, err = svc.GetHierarchy(ctx, "desk-1")
+	is.NoErr(err)
+	is.Equal("room-1", h.Thing.Parent)
+	is.Equal("building", h.Ancestors[0].ID)
+	is.Equal("room-1", h.Ancestors[1].ID)
+
+	candidates, err := svc.GetParentCandidates(ctx, "room-1")
+	is.NoErr(err)
+	is.Equal(1, len(candidates))
+	is.Equal("building", candidates[0].ID)
+}
+
+func newTestService(t *testing.T) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := []things.Thing{
+		{ID: "building", Type: "Building", Name: "Town hall", Tenant: "default"},
+		{ID: "other-building", Type: "Building", Name: "Library", Tenant: "other"},
+		{ID: "room-1", Type: "Room", Name: "A", Tenant: "default"},
+		{ID: "room-2", Type: "Room", Name: "B", Tenant: "default"},
+		{ID: "desk-1", Type: "Desk", Name: "Desk", Tenant: "default", TypeValues: things.TypeValues{Presence: new(true)}},
+	}
+	byID := map[string]things.Thing{}
+	for _, t := range all {
+		byID[t.ID] = t
+	}
+
+	return NewService(thingstest.Things{All: all, ByID: byID}, store)
+}
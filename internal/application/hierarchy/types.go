@@ -0,0 +1,102 @@
+package hierarchy
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+var ErrInvalid = errors.New("invalid relation")
+
+type Management interface {
+	GetHierarchy(ctx context.Context, thingID string) (Hierarchy, error)
+	GetParentCandidates(ctx context.Context, thingID string) ([]things.Thing, error)
+	SetParent(ctx context.Context, thingID, parentID string) error
+}
+
+// ParentTypes are the types of things that a thing of a type can be part of, such as the building
+// of a room or the room of a desk.
+var ParentTypes = map[string][]string{
+	"room": {"Building"},
+	"desk": {"Room", "Building"},
+}
+
+// CanHaveParent tells whether a thing of a type can be part of another thing.
+func CanHaveParent(thingType string) bool {
+	_, ok := ParentTypes[strings.ToLower(thingType)]
+	return ok
+}
+
+// CanBeParent tells whether a thing of a type can have things that are part of it.
+func CanBeParent(thingType string) bool {
+	for _, parents := range ParentTypes {
+		if slices.ContainsFunc(parents, func(p string) bool { return strings.EqualFold(p, thingType) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hierarchy is a thing together with the things it is part of and the things that are part of it.
+type Hierarchy struct {
+	Thing things.Thing
+	// Ancestors are the things that the thing is part of, the outermost first.
+	Ancestors []things.Thing
+	Children  []Node
+}
+
+type Node struct {
+	Thing    things.Thing
+	Children []Node
+}
+
+// Rollup sums the latest values of every thing that is part of the thing, at any depth.
+func (h Hierarchy) Rollup() Rollup {
+	var r Rollup
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, n := range nodes {
+			r.add(n.Thing.TypeValues)
+			r.Things++
+			walk(n.Children)
+		}
+	}
+	walk(h.Children)
+	return r
+}
+
+// Rollup is the combined state of the things that are part of a thing. Energy and power are nil if
+// none of the things has a value.
+type Rollup struct {
+	Things int
+	Energy *float64
+	Power  *float64
+	// Present is the number of things that have presence, out of the Occupiable things that report it.
+	Present    int
+	Occupiable int
+}
+
+func (r *Rollup) add(v things.TypeValues) {
+	if v.Energy != nil {
+		r.Energy = new(value(r.Energy) + *v.Energy)
+	}
+	if v.Power != nil {
+		r.Power = new(value(r.Power) + *v.Power)
+	}
+	if v.Presence != nil {
+		r.Occupiable++
+		if *v.Presence {
+			r.Present++
+		}
+	}
+}
+
+func value(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
@@ -0,0 +1,185 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/history")
+
+// historyCollection keeps the history of each thing as one document.
+const historyCollection = "history"
+
+type thingSource interface {
+	things.Getter
+	NewThing(ctx context.Context, t things.Thing) error
+	UpdateThing(ctx context.Context, thingID string, fields map[string]any) error
+	ConnectSensor(ctx context.Context, thingID string, refDevices []string) error
+}
+
+// Service records how things change when they are created, updated or get other sensors through
+// diwise-web. Changes made to things in other ways are seen the next time a thing is changed here.
+type Service struct {
+	things thingSource
+	store  storage.Store
+	now    func() time.Time
+}
+
+func NewService(things thingSource, store storage.Store) *Service {
+	return &Service{things: things, store: store, now: time.Now}
+}
+
+// GetHistory returns the recorded versions of a thing, the latest first.
+func (s *Service) GetHistory(ctx context.Context, thingID string) ([]Entry, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-history")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	entries, err := s.entries(ctx, thingID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(entries)
+
+	return entries, nil
+}
+
+// NewThing creates a thing and records it as created.
+func (s *Service) NewThing(ctx context.Context, t things.Thing) error {
+	err := s.things.NewThing(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	s.record(ctx, t.ID, KindCreated, SnapshotOf(t))
+
+	return nil
+}
+
+// UpdateThing updates a thing and records what changed.
+func (s *Service) UpdateThing(ctx context.Context, thingID string, fields map[string]any) error {
+	s.recordBaseline(ctx, thingID)
+
+	err := s.things.UpdateThing(ctx, thingID, fields)
+	if err != nil {
+		return err
+	}
+
+	s.recordCurrent(ctx, thingID)
+
+	return nil
+}
+
+// ConnectSensor sets the sensors of a thing and records which were connected and disconnected.
+func (s *Service) ConnectSensor(ctx context.Context, thingID string, refDevices []string) error {
+	s.recordBaseline(ctx, thingID)
+
+	err := s.things.ConnectSensor(ctx, thingID, refDevices)
+	if err != nil {
+		return err
+	}
+
+	s.recordCurrent(ctx, thingID)
+
+	return nil
+}
+
+// recordBaseline records how a thing looks before it is changed, if it has no history yet.
+func (s *Service) recordBaseline(ctx context.Context, thingID string) {
+	entries, err := s.entries(ctx, thingID)
+	if err != nil || len(entries) > 0 {
+		return
+	}
+
+	t, err := s.things.GetThing(ctx, thingID, nil)
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not fetch thing before change", "thing_id", thingID, "err", err.Error())
+		return
+	}
+
+	s.record(ctx, thingID, KindFirstRecorded, SnapshotOf(t))
+}
+
+func (s *Service) recordCurrent(ctx context.Context, thingID string) {
+	t, err := s.things.GetThing(ctx, thingID, nil)
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not fetch thing after change", "thing_id", thingID, "err", err.Error())
+		return
+	}
+
+	s.record(ctx, thingID, KindChanged, SnapshotOf(t))
+}
+
+// record adds a version of a thing to its history, unless nothing has changed. The history is kept
+// aside from the thing itself, so a failure to record is logged rather than failing the change.
+func (s *Service) record(ctx context.Context, thingID, kind string, snapshot Snapshot) {
+	var err error
+	ctx, span := tracer.Start(ctx, "record-history")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	entries, err := s.entries(ctx, thingID)
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not read history", "thing_id", thingID, "err", err.Error())
+		return
+	}
+
+	entry := Entry{
+		Time:     s.now().UTC().Truncate(time.Second),
+		Kind:     kind,
+		Author:   authz.Name(ctx),
+		AuthorID: authz.Subject(ctx),
+		Snapshot: snapshot,
+	}
+
+	if len(entries) > 0 {
+		entry.Changes = Diff(entries[len(entries)-1].Snapshot, snapshot)
+		if len(entry.Changes) == 0 {
+			return
+		}
+		if kind != KindChanged {
+			entry.Kind = KindChanged
+		}
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+
+	document, err := json.Marshal(entries)
+	if err == nil {
+		err = s.store.Put(ctx, historyCollection, thingID, document)
+	}
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not record history", "thing_id", thingID, "err", err.Error())
+	}
+}
+
+// entries returns the history of a thing, the oldest first.
+func (s *Service) entries(ctx context.Context, thingID string) ([]Entry, error) {
+	document, err := s.store.Get(ctx, historyCollection, thingID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	if err := json.Unmarshal(document, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
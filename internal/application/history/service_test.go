@@ -0,0 +1,149 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestHistoryRecordsHowAThingChanged(t *testing.T) {
+	is := is.New(t)
+	ctx := context.WithValue(context.Background(), authz.SubjectClaim, "alice")
+	svc, source, clock := newTestService(t)
+
+	is.NoErr(svc.NewThing(ctx, things.Thing{ID: "bin-1", Type: "Container-WasteContainer", Name: "Bin", Tenant: "default", Tags: []string{"north"}}))
+
+	clock.Add(time.Hour)
+	is.NoErr(svc.ConnectSensor(ctx, "bin-1", []string{"sensor-1"}))
+
+	clock.Add(time.Hour)
+	is.NoErr(svc.UpdateThing(ctx, "bin-1", map[string]any{"tags": []string{"south"}, "location": client.Location{Latitude: 62.39, Longitude: 17.30}}))
+
+	clock.Add(time.Hour)
+	is.NoErr(svc.UpdateThing(ctx, "bin-1", map[string]any{"tags": []string{"south"}})) // nothing changes
+
+	entries, err := svc.GetHistory(ctx, "bin-1")
+	is.NoErr(err)
+	is.Equal(3, len(entries))
+
+	is.Equal(KindChanged, entries[0].Kind)
+	is.Equal([]Change{
+		{Field: FieldTags, Added: []string{"south"}, Removed: []string{"north"}},
+		{Field: FieldLocation, To: "62.390000, 17.300000"},
+	}, entries[0].Changes)
+
+	change, ok := entries[1].SensorChange()
+	is.True(ok)
+	is.Equal([]string{"sensor-1"}, change.Added)
+	is.Equal("alice", entries[1].Author)
+
+	is.Equal(KindCreated, entries[2].Kind)
+	is.Equal(3, source.gets["bin-1"]) // fetched after each change, but not before since the history exists
+}
+
+func TestHistoryStartsWithTheThingAsItWasBeforeTheFirstChange(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	svc, source, _ := newTestService(t)
+
+	source.things["pump-1"] = things.Thing{ID: "pump-1", Type: "PumpingStation", Name: "Pump"}
+	is.NoErr(svc.UpdateThing(ctx, "pump-1", map[string]any{"name": "Pump 1"}))
+
+	entries, err := svc.GetHistory(ctx, "pump-1")
+	is.NoErr(err)
+	is.Equal(2, len(entries))
+	is.Equal(KindFirstRecorded, entries[1].Kind)
+	is.Equal("Pump", entries[1].Snapshot.Name)
+	is.Equal([]Change{{Field: FieldName, From: "Pump", To: "Pump 1"}}, entries[0].Changes)
+}
+
+func TestAsOfAndSensorChanges(t *testing.T) {
+	is := is.New(t)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC) }
+
+	entries := []Entry{
+		{Time: day(3), Kind: KindChanged, Snapshot: Snapshot{Name: "c"}, Changes: []Change{{Field: FieldSensors, Added: []string{"s2"}}}},
+		{Time: day(2), Kind: KindChanged, Snapshot: Snapshot{Name: "b"}, Changes: []Change{{Field: FieldName, From: "a", To: "b"}}},
+		{Time: day(1), Kind: KindCreated, Snapshot: Snapshot{Name: "a"}},
+	}
+
+	entry, ok := AsOf(entries, day(2).Add(time.Hour))
+	is.True(ok)
+	is.Equal("b", entry.Snapshot.Name)
+
+	_, ok = AsOf(entries, day(1).Add(-time.Hour))
+	is.True(!ok)
+
+	changes := SensorChanges(entries, day(1), day(4))
+	is.Equal(1, len(changes))
+	is.Equal("c", changes[0].Snapshot.Name)
+}
+
+type fakeThings struct {
+	things map[string]things.Thing
+	gets   map[string]int
+}
+
+func (f *fakeThings) GetThing(_ context.Context, id string, _ map[string][]string) (things.Thing, error) {
+	f.gets[id]++
+	t, ok := f.things[id]
+	if !ok {
+		return things.Thing{}, storage.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeThings) NewThing(_ context.Context, t things.Thing) error {
+	f.things[t.ID] = t
+	return nil
+}
+
+func (f *fakeThings) UpdateThing(_ context.Context, id string, fields map[string]any) error {
+	t := f.things[id]
+	if name, ok := fields["name"].(string); ok {
+		t.Name = name
+	}
+	if tags, ok := fields["tags"].([]string); ok {
+		t.Tags = tags
+	}
+	if location, ok := fields["location"].(client.Location); ok {
+		t.Location = location
+	}
+	f.things[id] = t
+	return nil
+}
+
+func (f *fakeThings) ConnectSensor(_ context.Context, id string, refDevices []string) error {
+	t := f.things[id]
+	t.RefDevices = nil
+	for _, ref := range refDevices {
+		t.RefDevices = append(t.RefDevices, things.RefDevice{DeviceID: ref})
+	}
+	f.things[id] = t
+	return nil
+}
+
+type clock struct{ now time.Time }
+
+func (c *clock) Add(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestService(t *testing.T) (*Service, *fakeThings, *clock) {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := &fakeThings{things: map[string]things.Thing{}, gets: map[string]int{}}
+	c := &clock{now: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)}
+
+	svc := NewService(source, store)
+	svc.now = func() time.Time { return c.now }
+
+	return svc, source, c
+}
@@ -0,0 +1,195 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+type Management interface {
+	GetHistory(ctx context.Context, thingID string) ([]Entry, error)
+}
+
+// Kinds of entries in the history of a thing.
+const (
+	// KindCreated is recorded when a thing is created in diwise-web.
+	KindCreated = "created"
+	// KindFirstRecorded is recorded when a thing that was created before its history was kept is
+	// changed for the first time, so that the changes have something to be compared with.
+	KindFirstRecorded = "firstrecorded"
+	KindChanged       = "changed"
+)
+
+// Fields of a thing whose changes are recorded.
+const (
+	FieldName            = "name"
+	FieldAlternativeName = "alternativename"
+	FieldDescription     = "description"
+	FieldType            = "type"
+	FieldTenant          = "tenant"
+	FieldTags            = "tags"
+	FieldSensors         = "sensors"
+	FieldLocation        = "location"
+)
+
+// MaxEntries is how many entries are kept per thing. The oldest changes are forgotten first, while
+// the snapshot of each entry still tells how the thing looked at the time.
+const MaxEntries = 500
+
+// Snapshot is what a thing looked like at a moment.
+type Snapshot struct {
+	Name            string   `json:"name"`
+	AlternativeName string   `json:"alternativeName,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	Type            string   `json:"type"`
+	Tenant          string   `json:"tenant"`
+	Tags            []string `json:"tags,omitempty"`
+	Sensors         []string `json:"sensors,omitempty"`
+	Latitude        float64  `json:"latitude"`
+	Longitude       float64  `json:"longitude"`
+}
+
+// Change tells how a field of a thing changed. Lists, such as the tags and the connected sensors,
+// tell what was added and removed, while other fields tell what the value was before and after.
+type Change struct {
+	Field   string   `json:"field"`
+	From    string   `json:"from,omitempty"`
+	To      string   `json:"to,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Entry is a recorded version of a thing, with what changed since the version before it.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"`
+	Author   string    `json:"author"`
+	AuthorID string    `json:"authorId,omitzero"`
+	Snapshot Snapshot  `json:"snapshot"`
+	Changes  []Change  `json:"changes,omitempty"`
+}
+
+// SensorChange returns the change of the connected sensors in an entry, if there is one.
+func (e Entry) SensorChange() (Change, bool) {
+	i := slices.IndexFunc(e.Changes, func(c Change) bool { return c.Field == FieldSensors })
+	if i < 0 {
+		return Change{}, false
+	}
+	return e.Changes[i], true
+}
+
+// SnapshotOf takes a snapshot of a thing. The lists are sorted, so that a snapshot only differs
+// from another when the thing has changed.
+func SnapshotOf(t things.Thing) Snapshot {
+	thingType := t.Type
+	if t.SubType != "" {
+		thingType += "-" + t.SubType
+	}
+
+	sensors := make([]string, 0, len(t.RefDevices))
+	for _, ref := range t.RefDevices {
+		sensors = append(sensors, ref.DeviceID)
+	}
+
+	return Snapshot{
+		Name:            t.Name,
+		AlternativeName: t.AlternativeName,
+		Description:     t.Description,
+		Type:            thingType,
+		Tenant:          t.Tenant,
+		Tags:            sortedSet(t.Tags),
+		Sensors:         sortedSet(sensors),
+		Latitude:        t.Location.Latitude,
+		Longitude:       t.Location.Longitude,
+	}
+}
+
+// Diff returns how a thing changed from one snapshot to another.
+func Diff(from, to Snapshot) []Change {
+	changes := []Change{}
+
+	scalar := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, Change{Field: field, From: a, To: b})
+		}
+	}
+	list := func(field string, a, b []string) {
+		added, removed := difference(b, a), difference(a, b)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, Change{Field: field, Added: added, Removed: removed})
+		}
+	}
+
+	scalar(FieldName, from.Name, to.Name)
+	scalar(FieldAlternativeName, from.AlternativeName, to.AlternativeName)
+	scalar(FieldDescription, from.Description, to.Description)
+	scalar(FieldType, from.Type, to.Type)
+	scalar(FieldTenant, from.Tenant, to.Tenant)
+	list(FieldTags, from.Tags, to.Tags)
+	list(FieldSensors, from.Sensors, to.Sensors)
+	scalar(FieldLocation, from.Location(), to.Location())
+
+	return changes
+}
+
+// Location formats the position of a snapshot, or is empty if the thing had no position.
+func (s Snapshot) Location() string {
+	if s.Latitude == 0 && s.Longitude == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.6f, %.6f", s.Latitude, s.Longitude)
+}
+
+// AsOf returns the version of a thing that was current at a moment, if its history goes back that
+// far. The entries may be in any order.
+func AsOf(entries []Entry, at time.Time) (Entry, bool) {
+	var found Entry
+	ok := false
+	for _, e := range entries {
+		if e.Time.After(at) {
+			continue
+		}
+		if !ok || e.Time.After(found.Time) {
+			found, ok = e, true
+		}
+	}
+	return found, ok
+}
+
+// SensorChanges returns the entries from and including from up to but not including to in which
+// sensors were connected or disconnected, in the order given.
+func SensorChanges(entries []Entry, from, to time.Time) []Entry {
+	result := []Entry{}
+	for _, e := range entries {
+		if _, ok := e.SensorChange(); ok && !e.Time.Before(from) && e.Time.Before(to) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func sortedSet(values []string) []string {
+	set := map[string]struct{}{}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			set[v] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(set))
+}
+
+// difference returns the values of a that are not in b.
+func difference(a, b []string) []string {
+	result := []string{}
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package measurements
+
+import (
+	"strings"
+	"time"
+)
+
+type Resolution string
+
+const (
+	ResolutionAuto  Resolution = "auto"
+	ResolutionRaw   Resolution = "raw"
+	ResolutionHour  Resolution = "hour"
+	ResolutionDay   Resolution = "day"
+	ResolutionWeek  Resolution = "week"
+	ResolutionMonth Resolution = "month"
+)
+
+type Aggregation string
+
+const (
+	AggregationAvg Aggregation = "avg"
+	AggregationMin Aggregation = "min"
+	AggregationMax Aggregation = "max"
+	AggregationSum Aggregation = "sum"
+)
+
+// ParseResolution maps a query value to a Resolution, falling back to ResolutionAuto for unknown values.
+func ParseResolution(value string) Resolution {
+	switch r := Resolution(strings.ToLower(strings.TrimSpace(value))); r {
+	case ResolutionRaw, ResolutionHour, ResolutionDay, ResolutionWeek, ResolutionMonth:
+		return r
+	default:
+		return ResolutionAuto
+	}
+}
+
+// ParseAggregation maps a query value to an Aggregation, falling back to AggregationAvg for unknown values.
+func ParseAggregation(value string) Aggregation {
+	switch a := Aggregation(strings.ToLower(strings.TrimSpace(value))); a {
+	case AggregationMin, AggregationMax, AggregationSum:
+		return a
+	default:
+		return AggregationAvg
+	}
+}
+
+// ResolutionForSpan picks a resolution that keeps the number of buckets for the time span reasonable.
+// Spans of a day or less are shown as raw values.
+func ResolutionForSpan(start, end time.Time) Resolution {
+	span := end.Sub(start)
+
+	switch {
+	case span <= 24*time.Hour:
+		return ResolutionRaw
+	case span <= 7*24*time.Hour:
+		return ResolutionHour
+	case span <= 92*24*time.Hour:
+		return ResolutionDay
+	case span <= 2*365*24*time.Hour:
+		return ResolutionWeek
+	default:
+		return ResolutionMonth
+	}
+}
+
+// Resolve returns the effective resolution, replacing ResolutionAuto with one derived from the time span.
+func (r Resolution) Resolve(start, end time.Time) Resolution {
+	if r == ResolutionAuto || r == "" {
+		return ResolutionForSpan(start, end)
+	}
+	return r
+}
+
+// Aggregated reports whether the resolution requires the backend to aggregate values.
+func (r Resolution) Aggregated() bool {
+	return r != ResolutionRaw && r != ResolutionAuto && r != ""
+}
+
+// TimeUnit is the unit of the time axis of a chart of values at the resolution.
+func (r Resolution) TimeUnit() string {
+	switch r {
+	case ResolutionDay, ResolutionWeek, ResolutionMonth:
+		return string(r)
+	default:
+		return "hour"
+	}
+}
+
+// Number returns the numeric value of an aggregated or raw measurement value.
+func (v Value) Number() (float64, bool) {
+	switch {
+	case v.Value != nil:
+		return *v.Value, true
+	case v.BoolValue != nil:
+		if *v.BoolValue {
+			return 1, true
+		}
+		return 0, true
+	case v.Count != 0:
+		return float64(v.Count), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,37 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestResolutionForSpan(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	is.Equal(ResolutionRaw, ResolutionForSpan(start, start.Add(12*time.Hour)))
+	is.Equal(ResolutionHour, ResolutionForSpan(start, start.AddDate(0, 0, 3)))
+	is.Equal(ResolutionDay, ResolutionForSpan(start, start.AddDate(0, 1, 0)))
+	is.Equal(ResolutionWeek, ResolutionForSpan(start, start.AddDate(1, 0, 0)))
+	is.Equal(ResolutionMonth, ResolutionForSpan(start, start.AddDate(3, 0, 0)))
+}
+
+func TestResolveKeepsExplicitResolution(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	is.Equal(ResolutionDay, ParseResolution("day").Resolve(start, start.Add(time.Hour)))
+	is.Equal(ResolutionMonth, ParseResolution("").Resolve(start, start.AddDate(5, 0, 0)))
+	is.True(!ParseResolution("raw").Aggregated())
+}
+
+func TestParseAggregationFallsBackToAvg(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(AggregationMax, ParseAggregation(" MAX "))
+	is.Equal(AggregationAvg, ParseAggregation("median"))
+}
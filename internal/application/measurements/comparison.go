@@ -0,0 +1,92 @@
+package measurements
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Comparison is the period that the values of a time window are compared with.
+type Comparison string
+
+const (
+	ComparisonNone     Comparison = ""
+	ComparisonPrevious Comparison = "previous"
+	ComparisonWeek     Comparison = "week"
+	ComparisonMonth    Comparison = "month"
+	ComparisonYear     Comparison = "year"
+)
+
+// ParseComparison maps a query value to a Comparison, falling back to ComparisonNone for unknown values.
+func ParseComparison(value string) Comparison {
+	switch c := Comparison(strings.ToLower(strings.TrimSpace(value))); c {
+	case ComparisonPrevious, ComparisonWeek, ComparisonMonth, ComparisonYear:
+		return c
+	default:
+		return ComparisonNone
+	}
+}
+
+// Shift returns the window that start and end are compared with. The previous period is the
+// window of the same length just before, counted in whole days when the window spans days so that
+// a day from midnight to 23:59 is compared with the day before.
+func (c Comparison) Shift(start, end time.Time) (time.Time, time.Time) {
+	shift := func(t time.Time) time.Time {
+		switch c {
+		case ComparisonWeek:
+			return t.AddDate(0, 0, -7)
+		case ComparisonMonth:
+			return t.AddDate(0, -1, 0)
+		case ComparisonYear:
+			return t.AddDate(-1, 0, 0)
+		case ComparisonPrevious:
+			span := end.Sub(start)
+			if span < 12*time.Hour {
+				return t.Add(-span)
+			}
+			return t.AddDate(0, 0, -int(math.Round(span.Hours()/24)))
+		default:
+			return t
+		}
+	}
+
+	return shift(start), shift(end)
+}
+
+// Stats summarise the numeric values of a time window.
+type Stats struct {
+	Count int
+	Mean  float64
+	Min   float64
+	Max   float64
+	Sum   float64
+}
+
+// StatsOf summarises values. It returns false when there are no values.
+func StatsOf(values []float64) (Stats, bool) {
+	if len(values) == 0 {
+		return Stats{}, false
+	}
+
+	s := Stats{Count: len(values), Min: values[0], Max: values[0]}
+	for _, v := range values {
+		s.Sum += v
+		s.Min = min(s.Min, v)
+		s.Max = max(s.Max, v)
+	}
+	s.Mean = s.Sum / float64(s.Count)
+
+	return s, true
+}
+
+// Change returns the difference from previous to current and, when previous is not zero, the
+// difference in percent of previous.
+func Change(current, previous float64) (float64, *float64) {
+	diff := current - previous
+	if previous == 0 {
+		return diff, nil
+	}
+
+	percent := diff / math.Abs(previous) * 100
+	return diff, &percent
+}
@@ -0,0 +1,46 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestComparisonShiftsTheWindow(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC)
+
+	from, to := ParseComparison("previous").Shift(start, end)
+	is.Equal(time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC), from)
+	is.Equal(time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC), to)
+
+	from, _ = ParseComparison(" YEAR ").Shift(start, end)
+	is.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), from)
+
+	from, to = ComparisonPrevious.Shift(start, start.Add(3*time.Hour))
+	is.Equal(start.Add(-3*time.Hour), from)
+	is.Equal(start, to)
+
+	is.Equal(ComparisonNone, ParseComparison("decade"))
+}
+
+func TestStatsAndChange(t *testing.T) {
+	is := is.New(t)
+
+	stats, ok := StatsOf([]float64{2, 4, 9})
+	is.True(ok)
+	is.Equal(Stats{Count: 3, Mean: 5, Min: 2, Max: 9, Sum: 15}, stats)
+
+	_, ok = StatsOf(nil)
+	is.True(!ok)
+
+	diff, percent := Change(5, 4)
+	is.Equal(1.0, diff)
+	is.Equal(25.0, *percent)
+
+	_, percent = Change(5, 0)
+	is.True(percent == nil)
+}
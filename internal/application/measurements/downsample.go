@@ -0,0 +1,82 @@
+package measurements
+
+import "math"
+
+// Downsample selects at most threshold points from the series using the Largest-Triangle-Three-Buckets
+// algorithm and returns the indices of the points to keep, in order. The first and last points are
+// always kept. All indices are returned when the series already fits within the threshold.
+func Downsample(x, y []float64, threshold int) []int {
+	n := min(len(x), len(y))
+
+	if threshold >= n || threshold < 3 {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	indices := make([]int, 0, threshold)
+	indices = append(indices, 0)
+
+	bucketSize := float64(n-2) / float64(threshold-2)
+	a := 0
+
+	for i := range threshold - 2 {
+		// average point of the next bucket, used as the third corner of the triangle
+		nextStart := int(math.Floor(float64(i+1)*bucketSize)) + 1
+		nextEnd := min(max(int(math.Floor(float64(i+2)*bucketSize))+1, nextStart+1), n)
+
+		avgX, avgY := 0.0, 0.0
+		for j := nextStart; j < nextEnd; j++ {
+			avgX += x[j]
+			avgY += y[j]
+		}
+		count := float64(nextEnd - nextStart)
+		avgX /= count
+		avgY /= count
+
+		start := int(math.Floor(float64(i)*bucketSize)) + 1
+		end := int(math.Floor(float64(i+1)*bucketSize)) + 1
+
+		maxArea := -1.0
+		selected := start
+		for j := start; j < end; j++ {
+			area := math.Abs((x[a]-avgX)*(y[j]-y[a]) - (x[a]-x[j])*(avgY-y[a]))
+			if area > maxArea {
+				maxArea = area
+				selected = j
+			}
+		}
+
+		indices = append(indices, selected)
+		a = selected
+	}
+
+	return append(indices, n-1)
+}
+
+// DownsampleValues reduces values to at most budget points while preserving the shape of the series.
+// Series with values that are not numbers are left untouched, since dropping points would hide
+// state changes.
+func DownsampleValues(values []Value, budget int) []Value {
+	if len(values) <= budget {
+		return values
+	}
+
+	x := make([]float64, 0, len(values))
+	y := make([]float64, 0, len(values))
+	for _, v := range values {
+		if v.Value == nil {
+			return values
+		}
+		x = append(x, float64(v.Timestamp.Unix()))
+		y = append(y, *v.Value)
+	}
+
+	downsampled := make([]Value, 0, budget)
+	for _, i := range Downsample(x, y, budget) {
+		downsampled = append(downsampled, values[i])
+	}
+	return downsampled
+}
@@ -0,0 +1,60 @@
+package measurements
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestDownsampleKeepsSeriesWithinThreshold(t *testing.T) {
+	is := is.New(t)
+
+	x := []float64{0, 1, 2, 3}
+	y := []float64{1, 2, 3, 4}
+
+	is.Equal([]int{0, 1, 2, 3}, Downsample(x, y, 10))
+}
+
+func TestDownsampleKeepsEndpointsAndPeaks(t *testing.T) {
+	is := is.New(t)
+
+	x := make([]float64, 1000)
+	y := make([]float64, 1000)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = math.Sin(float64(i) / 50)
+	}
+	y[500] = 25
+
+	indices := Downsample(x, y, 100)
+
+	is.Equal(100, len(indices))
+	is.Equal(0, indices[0])
+	is.Equal(999, indices[len(indices)-1])
+	is.True(slices.Contains(indices, 500))
+
+	for i := 1; i < len(indices); i++ {
+		is.True(indices[i] > indices[i-1])
+	}
+}
+
+func TestDownsampleValuesLeavesBooleanSeriesAlone(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	values := make([]Value, 0, 500)
+	for i := range 500 {
+		values = append(values, Value{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: new(float64(i % 7))})
+	}
+
+	downsampled := DownsampleValues(values, 100)
+	is.Equal(100, len(downsampled))
+	is.Equal(values[0], downsampled[0])
+	is.Equal(values[499], downsampled[99])
+
+	values[10] = Value{Timestamp: values[10].Timestamp, BoolValue: new(true)}
+	is.Equal(500, len(DownsampleValues(values, 100)))
+}
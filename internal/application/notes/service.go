@@ -0,0 +1,157 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/notes")
+
+// notesCollection keeps the notes of each thing and sensor as one document, so that a details page
+// reads a single document rather than every note.
+const notesCollection = "notes"
+
+type Service struct {
+	store storage.Store
+	locks storage.Locks
+}
+
+func NewService(store storage.Store) *Service {
+	return &Service{store: store}
+}
+
+// GetNotes returns the notes of a thing or a sensor, the latest first.
+func (s *Service) GetNotes(ctx context.Context, kind, subjectID string) ([]Note, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-notes")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	notes, err := s.notes(ctx, kind, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(notes, func(a, b Note) int { return b.Time.Compare(a.Time) })
+
+	return notes, nil
+}
+
+// AddNote stores a note written by the current user, who must be known so that they can delete it
+// again. A note without a time is taken to be about work done now.
+func (s *Service) AddNote(ctx context.Context, note Note) (Note, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "add-note")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	subject, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return Note{}, err
+	}
+
+	note.ID = uuid.NewString()
+	note.SubjectID = strings.TrimSpace(note.SubjectID)
+	note.Text = strings.TrimSpace(note.Text)
+	note.Author = authz.Name(ctx)
+	note.AuthorID = subject
+	if note.Time.IsZero() {
+		note.Time = time.Now()
+	}
+	note.Time = note.Time.UTC().Truncate(time.Second)
+
+	err = note.Validate()
+	if err != nil {
+		return Note{}, err
+	}
+
+	unlock := s.locks.Lock(key(note.Kind, note.SubjectID))
+	defer unlock()
+
+	notes, err := s.notes(ctx, note.Kind, note.SubjectID)
+	if err != nil {
+		return Note{}, err
+	}
+
+	err = s.save(ctx, note.Kind, note.SubjectID, append(notes, note))
+	if err != nil {
+		return Note{}, err
+	}
+
+	return note, nil
+}
+
+// DeleteNote removes a note. Only the author of a note may remove it.
+func (s *Service) DeleteNote(ctx context.Context, kind, subjectID, id string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "delete-note")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	subject, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlock := s.locks.Lock(key(kind, subjectID))
+	defer unlock()
+
+	notes, err := s.notes(ctx, kind, subjectID)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(notes, func(n Note) bool { return n.ID == id })
+	if i < 0 {
+		err = storage.ErrNotFound
+		return err
+	}
+	if notes[i].AuthorID != subject {
+		err = ErrNotAuthor
+		return err
+	}
+
+	err = s.save(ctx, kind, subjectID, slices.Delete(notes, i, i+1))
+	return err
+}
+
+func (s *Service) notes(ctx context.Context, kind, subjectID string) ([]Note, error) {
+	document, err := s.store.Get(ctx, notesCollection, key(kind, subjectID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return []Note{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []Note{}
+	if err := json.Unmarshal(document, &notes); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+func (s *Service) save(ctx context.Context, kind, subjectID string, notes []Note) error {
+	if len(notes) == 0 {
+		return s.store.Delete(ctx, notesCollection, key(kind, subjectID))
+	}
+
+	document, err := json.Marshal(notes)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(ctx, notesCollection, key(kind, subjectID), document)
+}
+
+func key(kind, subjectID string) string {
+	return kind + "/" + subjectID
+}
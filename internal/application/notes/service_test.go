@@ -0,0 +1,156 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestNotesAreKeptPerThingAndSensor(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	alice := withUser("alice", "Alice Andersson")
+
+	emptied, err := svc.AddNote(alice, Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryEmptied, Text: " Emptied manually ", Time: time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC)})
+	is.NoErr(err)
+	is.Equal("Emptied manually", emptied.Text)
+	is.Equal("Alice Andersson", emptied.Author)
+	is.Equal("alice", emptied.AuthorID)
+
+	_, err = svc.AddNote(alice, Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryRepair, Text: "Lid replaced", Time: time.Date(2024, 5, 12, 8, 0, 0, 0, time.UTC)})
+	is.NoErr(err)
+
+	mounted, err := svc.AddNote(alice, Note{Kind: KindDevice, SubjectID: "bin-1", Category: CategoryMounting, Text: "Sensor re-mounted at 30° angle"})
+	is.NoErr(err)
+	is.True(time.Since(mounted.Time) < time.Minute)
+
+	thingNotes, err := svc.GetNotes(alice, KindThing, "bin-1")
+	is.NoErr(err)
+	is.Equal(2, len(thingNotes))
+	is.Equal("Lid replaced", thingNotes[0].Text) // the latest first
+
+	deviceNotes, err := svc.GetNotes(alice, KindDevice, "bin-1")
+	is.NoErr(err)
+	is.Equal(1, len(deviceNotes))
+
+	none, err := svc.GetNotes(alice, KindThing, "bin-2")
+	is.NoErr(err)
+	is.Equal(0, len(none))
+}
+
+func TestAddNoteRejectsInvalidNotes(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	ctx := withUser("alice", "")
+
+	_, err := svc.AddNote(ctx, Note{Kind: "building", SubjectID: "b", Category: CategoryNote, Text: "x"})
+	is.True(errors.Is(err, ErrInvalid))
+
+	_, err = svc.AddNote(ctx, Note{Kind: KindThing, SubjectID: "bin-1", Category: "graffiti", Text: "x"})
+	is.True(errors.Is(err, ErrInvalid))
+
+	_, err = svc.AddNote(ctx, Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryNote, Text: "   "})
+	is.True(errors.Is(err, ErrInvalid))
+
+	note, err := svc.AddNote(ctx, Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryNote, Text: "x"})
+	is.NoErr(err)
+	is.Equal("alice", note.Author) // the subject when the token has no name
+}
+
+func TestOnlyTheAuthorMayDeleteANote(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	alice, bob := withUser("alice", "Alice"), withUser("bob", "Bob")
+
+	note, err := svc.AddNote(alice, Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryNote, Text: "Smells"})
+	is.NoErr(err)
+
+	err = svc.DeleteNote(bob, KindThing, "bin-1", note.ID)
+	is.True(errors.Is(err, ErrNotAuthor))
+
+	is.NoErr(svc.DeleteNote(alice, KindThing, "bin-1", note.ID))
+	notes, _ := svc.GetNotes(alice, KindThing, "bin-1")
+	is.Equal(0, len(notes))
+
+	err = svc.DeleteNote(alice, KindThing, "bin-1", note.ID)
+	is.True(errors.Is(err, storage.ErrNotFound))
+}
+
+func TestAddAndDeleteNoteNeedAUser(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	_, err := svc.AddNote(context.Background(), Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryNote, Text: "x"})
+	is.True(errors.Is(err, authz.ErrNoSubject))
+
+	notes, err := svc.GetNotes(context.Background(), KindThing, "bin-1")
+	is.NoErr(err)
+	is.Equal(0, len(notes))
+
+	note, err := svc.AddNote(withUser("alice", "Alice"), Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryNote, Text: "x"})
+	is.NoErr(err)
+
+	err = svc.DeleteNote(context.Background(), KindThing, "bin-1", note.ID)
+	is.True(errors.Is(err, authz.ErrNoSubject))
+}
+
+func TestNotesAddedAtTheSameTimeAreAllKept(t *testing.T) {
+	is := is.New(t)
+	store, err := storage.NewFileStore(t.TempDir())
+	is.NoErr(err)
+	svc := NewService(slowStore{store})
+	alice := withUser("alice", "Alice")
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			_, err := svc.AddNote(alice, Note{Kind: KindThing, SubjectID: "bin-1", Category: CategoryNote, Text: "x"})
+			is.NoErr(err)
+		})
+	}
+	wg.Wait()
+
+	notes, err := svc.GetNotes(alice, KindThing, "bin-1")
+	is.NoErr(err)
+	is.Equal(20, len(notes))
+}
+
+func TestBetween(t *testing.T) {
+	is := is.New(t)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+
+	notes := []Note{{ID: "a", Time: day(1)}, {ID: "b", Time: day(2)}, {ID: "c", Time: day(3)}}
+
+	between := Between(notes, day(2), day(3))
+	is.Equal(1, len(between))
+	is.Equal("b", between[0].ID)
+}
+
+func newTestService(t *testing.T) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(store)
+}
+
+// slowStore takes a while to read a document, as a remote database would.
+type slowStore struct {
+	storage.Store
+}
+
+func (s slowStore) Get(ctx context.Context, collection, key string) ([]byte, error) {
+	time.Sleep(5 * time.Millisecond)
+	return s.Store.Get(ctx, collection, key)
+}
+
+func withUser(subject, name string) context.Context {
+	ctx := context.WithValue(context.Background(), authz.SubjectClaim, subject)
+	return context.WithValue(ctx, authz.NameClaim, name)
+}
@@ -0,0 +1,89 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid note")
+
+// ErrNotAuthor is returned when a note is deleted by someone else than the one who wrote it.
+var ErrNotAuthor = errors.New("only the author may delete a note")
+
+type Management interface {
+	GetNotes(ctx context.Context, kind, subjectID string) ([]Note, error)
+	AddNote(ctx context.Context, note Note) (Note, error)
+	DeleteNote(ctx context.Context, kind, subjectID, id string) error
+}
+
+// Kinds are what notes can be written about.
+const (
+	KindThing  = "thing"
+	KindDevice = "device"
+)
+
+var Kinds = []string{KindThing, KindDevice}
+
+// Categories tell what kind of work or observation a note records.
+const (
+	CategoryNote       = "note"
+	CategoryEmptied    = "emptied"
+	CategoryRepair     = "repair"
+	CategoryMounting   = "mounting"
+	CategoryInspection = "inspection"
+)
+
+var Categories = []string{CategoryNote, CategoryEmptied, CategoryRepair, CategoryMounting, CategoryInspection}
+
+// MaxTextLength is the longest text a note may have, in characters.
+const MaxTextLength = 2000
+
+// Note is an entry in the log of a thing or a sensor, such as "emptied manually" or "sensor
+// re-mounted at 30° angle". Time is when the work was done, which may be earlier than when the note
+// was written.
+type Note struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	SubjectID string    `json:"subjectId"`
+	Author    string    `json:"author"`
+	AuthorID  string    `json:"authorId,omitzero"`
+	Time      time.Time `json:"time"`
+	Category  string    `json:"category"`
+	Text      string    `json:"text"`
+}
+
+func (n Note) Validate() error {
+	if !slices.Contains(Kinds, n.Kind) {
+		return errors.Join(ErrInvalid, errors.New("unsupported kind"))
+	}
+	if strings.TrimSpace(n.SubjectID) == "" {
+		return errors.Join(ErrInvalid, errors.New("a note needs a thing or a sensor"))
+	}
+	if !slices.Contains(Categories, n.Category) {
+		return errors.Join(ErrInvalid, errors.New("unsupported category"))
+	}
+	if strings.TrimSpace(n.Text) == "" {
+		return errors.Join(ErrInvalid, errors.New("a note needs a text"))
+	}
+	if len([]rune(n.Text)) > MaxTextLength {
+		return errors.Join(ErrInvalid, errors.New("the text is too long"))
+	}
+	if n.Time.IsZero() {
+		return errors.Join(ErrInvalid, errors.New("a note needs a time"))
+	}
+	return nil
+}
+
+// Between returns the notes from and including from up to but not including to, in the order given.
+func Between(notes []Note, from, to time.Time) []Note {
+	result := []Note{}
+	for _, n := range notes {
+		if !n.Time.Before(from) && n.Time.Before(to) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package occupancy
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// OccupiedHours picks the start of the hours with detected presence out of a series of hourly counts.
+func OccupiedHours(values []things.Measurement) []time.Time {
+	hours := []time.Time{}
+	for _, m := range values {
+		if m.Count != nil && *m.Count > 0 || m.BoolValue != nil && *m.BoolValue {
+			hours = append(hours, m.Timestamp.Truncate(time.Hour))
+		}
+	}
+	return hours
+}
+
+// Analyze measures the occupancy during the office hours between from and to, which follow the time
+// zone of from.
+func Analyze(occupied []time.Time, from, to time.Time) Space {
+	s := Space{From: from, To: to}
+
+	isOccupied := map[int64]bool{}
+	for _, hour := range occupied {
+		isOccupied[hour.Unix()] = true
+	}
+
+	for day := things.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		y, m, d := day.Date()
+		for hour := OfficeStartHour; hour < OfficeEndHour; hour++ {
+			start := time.Date(y, m, d, hour, 0, 0, 0, day.Location())
+			if start.Before(from) || !start.Before(to) {
+				continue
+			}
+
+			s.Total++
+			s.ByHour[hour].Total++
+			if isOccupied[start.Unix()] {
+				s.Occupied++
+				s.ByHour[hour].Occupied++
+			}
+		}
+	}
+
+	return s
+}
+
+// GroupByTag sums the spaces per tag, sorted by name. A space with several tags is counted in each of
+// them. Failed spaces are left out.
+func GroupByTag(spaces []Space) []Group {
+	groups := map[string]*Group{}
+	for _, s := range spaces {
+		if s.Failed {
+			continue
+		}
+
+		tags := s.Tags
+		if len(tags) == 0 {
+			tags = []string{""}
+		}
+
+		for _, tag := range slices.Compact(slices.Sorted(slices.Values(tags))) {
+			g, ok := groups[tag]
+			if !ok {
+				g = &Group{Name: tag}
+				groups[tag] = g
+			}
+
+			g.Spaces++
+			g.Occupied += s.Occupied
+			g.Total += s.Total
+			for hour := range g.ByHour {
+				g.ByHour[hour].Occupied += s.ByHour[hour].Occupied
+				g.ByHour[hour].Total += s.ByHour[hour].Total
+			}
+		}
+	}
+
+	result := make([]Group, 0, len(groups))
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		result = append(result, *groups[name])
+	}
+	return result
+}
+
+func peakHours(byHour [24]HourShare) []int {
+	highest := 0.0
+	for _, h := range byHour {
+		highest = max(highest, h.Percent())
+	}
+
+	peaks := []int{}
+	if highest == 0 {
+		return peaks
+	}
+	for hour, h := range byHour {
+		if h.Percent() == highest {
+			peaks = append(peaks, hour)
+		}
+	}
+	return peaks
+}
@@ -0,0 +1,66 @@
+package occupancy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+// monday is the start of a week
+var monday = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+func TestAnalyzeCountsOfficeHoursOnWeekdays(t *testing.T) {
+	is := is.New(t)
+
+	occupied := []time.Time{
+		monday.Add(9 * time.Hour),
+		monday.Add(10 * time.Hour),
+		monday.AddDate(0, 0, 1).Add(9 * time.Hour),
+		// before office hours
+		monday.Add(6 * time.Hour),
+		// saturday
+		monday.AddDate(0, 0, 5).Add(9 * time.Hour),
+	}
+
+	s := Analyze(occupied, monday, monday.AddDate(0, 0, 7))
+
+	is.Equal(5*(OfficeEndHour-OfficeStartHour), s.Total)
+	is.Equal(3, s.Occupied)
+	is.Equal(HourShare{Occupied: 2, Total: 5}, s.ByHour[9])
+	is.Equal(HourShare{}, s.ByHour[6])
+	is.Equal([]int{9}, s.PeakHours())
+}
+
+func TestOccupiedHoursSkipsHoursWithoutPresence(t *testing.T) {
+	is := is.New(t)
+
+	none, some := 0.0, 3.0
+	hours := OccupiedHours([]things.Measurement{
+		{Timestamp: monday.Add(8 * time.Hour), Count: &none},
+		{Timestamp: monday.Add(9*time.Hour + 15*time.Minute), Count: &some},
+	})
+
+	is.Equal([]time.Time{monday.Add(9 * time.Hour)}, hours)
+}
+
+func TestGroupByTagCountsSpacesInEachOfTheirTags(t *testing.T) {
+	is := is.New(t)
+
+	spaces := []Space{
+		{Tags: []string{"floor 2", "east"}, HourShare: HourShare{Occupied: 2, Total: 10}},
+		{Tags: []string{"floor 2"}, HourShare: HourShare{Occupied: 6, Total: 10}},
+		{HourShare: HourShare{Occupied: 1, Total: 10}},
+		{Tags: []string{"floor 2"}, Failed: true},
+	}
+
+	groups := GroupByTag(spaces)
+
+	is.Equal(3, len(groups))
+	is.Equal("", groups[0].Name)
+	is.Equal("east", groups[1].Name)
+	is.Equal("floor 2", groups[2].Name)
+	is.Equal(2, groups[2].Spaces)
+	is.Equal(40.0, groups[2].Percent())
+}
@@ -0,0 +1,120 @@
+package occupancy
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/occupancy")
+
+// presenceURN is the digital input state of the presence sensor in a room or at a desk.
+const presenceURN = "3302/5500"
+
+var spaceTypes = []string{"Room", "Desk"}
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+}
+
+func NewService(things thingSource) *Service {
+	return &Service{things: things}
+}
+
+// GetOccupancy measures how much a room or a desk has been used during office hours between from and to.
+func (s *Service) GetOccupancy(ctx context.Context, thingID string, from, to time.Time) (Space, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-occupancy")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	space, err := s.space(ctx, thingID, from, to)
+	return space, err
+}
+
+// GetOccupancyReport measures every room and desk between from and to and groups them by tag. A
+// space without presence history is failed and sorted last, as it says nothing about how it is used.
+func (s *Service) GetOccupancyReport(ctx context.Context, from, to time.Time) (Report, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-occupancy-report")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	spaces := []things.Thing{}
+	for _, thingType := range spaceTypes {
+		var result []things.Thing
+		result, err = things.GetAllThings(ctx, s.things, map[string][]string{"type": {thingType}})
+		if err != nil {
+			return Report{}, err
+		}
+		spaces = append(spaces, result...)
+	}
+
+	log := logging.GetFromContext(ctx)
+
+	result, err := things.Collect(spaces, func(t things.Thing) (Space, error) {
+		return s.space(ctx, t.ID, from, to)
+	}, func(t things.Thing, err error) Space {
+		log.Debug("could not fetch presence history", "thing_id", t.ID, "err", err.Error())
+		return Space{From: from, To: to, Failed: true}
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	for i, t := range spaces {
+		result[i].ThingID = t.ID
+		result[i].Name = cmp.Or(t.Name, t.ID)
+		result[i].Type = t.Type
+		result[i].Tenant = t.Tenant
+		result[i].Tags = t.Tags
+	}
+
+	// the least used spaces are the ones to look at when right-sizing, failed ones say nothing
+	slices.SortStableFunc(result, func(a, b Space) int {
+		if a.Failed != b.Failed {
+			if a.Failed {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.Percent(), b.Percent()), cmp.Compare(a.Name, b.Name))
+	})
+
+	return Report{From: from, To: to, Spaces: result, Groups: GroupByTag(result)}, nil
+}
+
+func (s *Service) space(ctx context.Context, thingID string, from, to time.Time) (Space, error) {
+	// the backend counts the hours with presence, the same way as the measurement chart of the thing
+	query := url.Values{}
+	query.Add("timerel", "between")
+	query.Add("timeat", from.UTC().Format(time.RFC3339))
+	query.Add("endTimeAt", to.UTC().Format(time.RFC3339))
+	query.Add("n", presenceURN)
+	query.Add("timeunit", "hour")
+	query.Add("vb", "true")
+
+	thing, err := s.things.GetThing(ctx, thingID, query)
+	if err != nil {
+		return Space{}, err
+	}
+
+	space := Analyze(OccupiedHours(slices.Concat(thing.Values...)), from, to)
+	space.ThingID = thingID
+	space.Name = cmp.Or(thing.Name, thingID)
+	space.Type = thing.Type
+	space.Tenant = thing.Tenant
+	space.Tags = thing.Tags
+
+	return space, nil
+}
@@ -0,0 +1,48 @@
+package occupancy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestGetOccupancyReportPutsLeastUsedSpacesFirst(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(thingstest.Things{
+		All: []things.Thing{
+			{ID: "busy", Name: "Busy", Type: "Room"},
+			{ID: "broken", Name: "Broken", Type: "Room"},
+			{ID: "quiet", Name: "Quiet", Type: "Desk"},
+		},
+		ByID: thingstest.History(map[string][]things.Measurement{
+			"busy":  presence(5),
+			"quiet": presence(1),
+		}),
+	})
+
+	report, err := svc.GetOccupancyReport(context.Background(), monday, monday.AddDate(0, 0, 1))
+	is.NoErr(err)
+	is.Equal(3, len(report.Spaces))
+	is.Equal("quiet", report.Spaces[0].ThingID)
+	is.Equal("Desk", report.Spaces[0].Type)
+	is.Equal("busy", report.Spaces[1].ThingID)
+	is.Equal(5, report.Spaces[1].Occupied)
+	is.True(report.Spaces[2].Failed)
+	is.Equal(1, len(report.Groups))
+	is.Equal(2, report.Groups[0].Spaces)
+}
+
+// presence reports presence during the first office hours of monday.
+func presence(hours int) []things.Measurement {
+	values := []things.Measurement{}
+	for i := range hours {
+		count := 1.0
+		values = append(values, things.Measurement{Timestamp: monday.Add(time.Duration(OfficeStartHour+i) * time.Hour), Count: &count})
+	}
+	return values
+}
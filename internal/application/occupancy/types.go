@@ -0,0 +1,84 @@
+package occupancy
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultDays is how many days, including today, the occupancy covers unless another period is
+	// asked for. Four whole weeks give every weekday the same weight.
+	DefaultDays = 28
+
+	// OfficeStartHour and OfficeEndHour are the hours, Monday to Friday, that utilisation is measured over.
+	OfficeStartHour = 8
+	OfficeEndHour   = 17
+)
+
+type Management interface {
+	GetOccupancy(ctx context.Context, thingID string, from, to time.Time) (Space, error)
+	GetOccupancyReport(ctx context.Context, from, to time.Time) (Report, error)
+}
+
+// HourShare is how many times an hour of the day was occupied out of how many times it was within
+// office hours during a period.
+type HourShare struct {
+	Occupied int
+	Total    int
+}
+
+// Percent is the share of the hour that was occupied, in percent.
+func (h HourShare) Percent() float64 {
+	if h.Total == 0 {
+		return 0
+	}
+	return float64(h.Occupied) / float64(h.Total) * 100
+}
+
+// Space is the occupancy of a room or a desk during office hours between From and To. An office
+// hour counts as occupied when presence was detected at any time during it.
+type Space struct {
+	ThingID string
+	Name    string
+	Type    string
+	Tenant  string
+	Tags    []string
+	From    time.Time
+	To      time.Time
+
+	HourShare
+	// ByHour holds the occupancy for each hour of the day. Only office hours are ever counted.
+	ByHour [24]HourShare
+
+	// Failed is set when the presence history of the space could not be fetched.
+	Failed bool
+}
+
+// PeakHours lists the office hours of the day that were occupied most often, or none if the space
+// was never occupied.
+func (s Space) PeakHours() []int {
+	return peakHours(s.ByHour)
+}
+
+// Group sums the occupancy of the spaces that share a tag. Spaces without tags are grouped under an
+// empty name.
+type Group struct {
+	Name   string
+	Spaces int
+
+	HourShare
+	ByHour [24]HourShare
+}
+
+// PeakHours lists the office hours of the day when the spaces of the group were occupied most often.
+func (g Group) PeakHours() []int {
+	return peakHours(g.ByHour)
+}
+
+// Report is the occupancy of every room and desk between From and To, with the least used spaces first.
+type Report struct {
+	From   time.Time
+	To     time.Time
+	Spaces []Space
+	Groups []Group
+}
@@ -0,0 +1,64 @@
+package overflows
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+)
+
+// EventsCSV writes one row per overflow event, with times in loc and durations in minutes.
+func (r Report) EventsCSV(loc *time.Location) ([]byte, error) {
+	rows := [][]string{{"id", "name", "start", "end", "duration_minutes", "ongoing"}}
+	for _, e := range r.Events {
+		rows = append(rows, []string{
+			e.ThingID,
+			e.Name,
+			e.Start.In(loc).Format(time.RFC3339),
+			e.End.In(loc).Format(time.RFC3339),
+			minutes(e.Duration()),
+			strconv.FormatBool(e.Ongoing),
+		})
+	}
+	return writeCSV(rows)
+}
+
+// SummaryCSV writes one row per outlet with the number of overflows and the total overflow time
+// during the report period. To is exclusive, so the last day of the period is the day before it.
+// Outlets whose history could not be fetched have the status failed and no figures, so that a
+// missing outlet is not mistaken for one without overflows.
+func (r Report) SummaryCSV(loc *time.Location) ([]byte, error) {
+	rows := [][]string{{"id", "name", "tenant", "from", "to", "status", "count", "total_minutes", "longest_minutes"}}
+	for _, s := range r.Summaries {
+		row := []string{
+			s.ThingID,
+			s.Name,
+			s.Tenant,
+			r.From.In(loc).Format(time.DateOnly),
+			r.To.Add(-time.Nanosecond).In(loc).Format(time.DateOnly),
+		}
+		if s.Failed {
+			row = append(row, "failed", "", "", "")
+		} else {
+			row = append(row, "ok", strconv.Itoa(s.Count), minutes(s.Total), minutes(s.Longest))
+		}
+		rows = append(rows, row)
+	}
+	return writeCSV(rows)
+}
+
+func minutes(d time.Duration) string {
+	return strconv.FormatFloat(d.Minutes(), 'f', 1, 64)
+}
+
+func writeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
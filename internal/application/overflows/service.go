@@ -0,0 +1,126 @@
+package overflows
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/overflows")
+
+const combinedSewerOverflowType = "Sewer-CombinedSewerOverflow"
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+}
+
+func NewService(things thingSource) *Service {
+	return &Service{things: things}
+}
+
+// GetOverflowEvents derives the overflow events of a single outlet from the history of its
+// overflow state between from and to.
+func (s *Service) GetOverflowEvents(ctx context.Context, thingID string, from, to time.Time) ([]Event, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-overflow-events")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	thing, err := things.GetOnOffHistory(ctx, s.things, thingID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEvents(thingID, cmp.Or(thing.Name, thingID), thing.Values, from, to), nil
+}
+
+// GetOverflowReport collects the overflow events of all combined sewer overflow outlets between
+// from and to. The summaries are ordered by total overflow time, and an outlet without a history
+// has a failed summary and no events.
+func (s *Service) GetOverflowReport(ctx context.Context, from, to time.Time) (Report, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-overflow-report")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	outlets, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": {combinedSewerOverflowType}})
+	if err != nil {
+		return Report{}, err
+	}
+
+	log := logging.GetFromContext(ctx)
+
+	type outletResult struct {
+		summary Summary
+		events  []Event
+	}
+
+	results, err := things.Collect(outlets, func(outlet things.Thing) (outletResult, error) {
+		thing, err := things.GetOnOffHistory(ctx, s.things, outlet.ID, from, to)
+		if err != nil {
+			return outletResult{}, err
+		}
+
+		events := toEvents(outlet.ID, outletName(outlet), thing.Values, from, to)
+		return outletResult{summary: summarize(outletSummary(outlet), events), events: events}, nil
+	}, func(outlet things.Thing, err error) outletResult {
+		log.Debug("could not fetch overflow history", "thing_id", outlet.ID, "err", err.Error())
+		summary := outletSummary(outlet)
+		summary.Failed = true
+		return outletResult{summary: summary}
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{From: from, To: to, Summaries: make([]Summary, len(results))}
+	for i, r := range results {
+		report.Summaries[i] = r.summary
+		report.Events = append(report.Events, r.events...)
+	}
+	slices.SortStableFunc(report.Events, func(a, b Event) int { return a.Start.Compare(b.Start) })
+	slices.SortStableFunc(report.Summaries, func(a, b Summary) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Name, b.Name))
+	})
+
+	return report, nil
+}
+
+func outletName(outlet things.Thing) string {
+	return cmp.Or(outlet.Name, outlet.ID)
+}
+
+func outletSummary(outlet things.Thing) Summary {
+	return Summary{ThingID: outlet.ID, Name: outletName(outlet), Tenant: outlet.Tenant}
+}
+
+func toEvents(thingID, name string, history [][]things.Measurement, from, to time.Time) []Event {
+	periods := things.OnPeriods(slices.Concat(history...), from, to)
+	events := make([]Event, 0, len(periods))
+	for _, p := range periods {
+		events = append(events, Event{ThingID: thingID, Name: name, Period: p})
+	}
+	return events
+}
+
+func summarize(s Summary, events []Event) Summary {
+	s.Count = len(events)
+	for _, e := range events {
+		d := e.Duration()
+		s.Total += d
+		s.Longest = max(s.Longest, d)
+		if s.LastStart == nil || e.Start.After(*s.LastStart) {
+			s.LastStart = &e.Start
+		}
+	}
+	return s
+}
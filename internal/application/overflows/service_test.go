@@ -0,0 +1,68 @@
+package overflows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+var reportStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestGetOverflowReport(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(thingstest.Things{
+		All: []things.Thing{outlet("cso-1", "Outlet 1"), outlet("cso-2", "Outlet 2"), outlet("cso-3", "")},
+		ByID: thingstest.History(map[string][]things.Measurement{
+			"cso-1": {state(1, true), state(2, false), state(5, true), state(8, false)},
+			"cso-2": {state(3, true), state(4, false)},
+		}),
+	})
+
+	report, err := svc.GetOverflowReport(context.Background(), reportStart, reportStart.Add(24*time.Hour))
+	is.NoErr(err)
+
+	is.Equal(3, report.Count())
+	is.Equal(5*time.Hour, report.Total())
+	is.Equal("cso-1", report.Events[0].ThingID)
+	is.Equal("cso-2", report.Events[1].ThingID)
+
+	is.Equal(3, len(report.Summaries))
+	is.Equal("cso-1", report.Summaries[0].ThingID)
+	is.Equal(2, report.Summaries[0].Count)
+	is.Equal(3*time.Hour, report.Summaries[0].Longest)
+	is.Equal(reportStart.Add(5*time.Hour), *report.Summaries[0].LastStart)
+	is.True(report.Summaries[2].Failed)
+	is.Equal("cso-3", report.Summaries[2].Name)
+}
+
+func TestSummaryCSV(t *testing.T) {
+	is := is.New(t)
+
+	report := Report{
+		From: reportStart,
+		To:   reportStart.AddDate(1, 0, 0),
+		Summaries: []Summary{
+			{ThingID: "cso-1", Name: "Outlet 1", Count: 2, Total: 90 * time.Minute, Longest: time.Hour},
+			{ThingID: "cso-2", Name: "Outlet 2", Failed: true},
+		},
+	}
+
+	b, err := report.SummaryCSV(time.UTC)
+	is.NoErr(err)
+	is.Equal("id;name;tenant;from;to;status;count;total_minutes;longest_minutes\n"+
+		"cso-1;Outlet 1;;2025-01-01;2025-12-31;ok;2;90.0;60.0\n"+
+		"cso-2;Outlet 2;;2025-01-01;2025-12-31;failed;;;\n", string(b))
+}
+
+func state(hour int, on bool) things.Measurement {
+	return things.Measurement{Timestamp: reportStart.Add(time.Duration(hour) * time.Hour), BoolValue: &on}
+}
+
+func outlet(id, name string) things.Thing {
+	return things.Thing{ID: id, Name: name, Type: "Sewer", SubType: "CombinedSewerOverflow"}
+}
@@ -0,0 +1,55 @@
+package overflows
+
+import (
+	"context"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+type Management interface {
+	GetOverflowEvents(ctx context.Context, thingID string, from, to time.Time) ([]Event, error)
+	GetOverflowReport(ctx context.Context, from, to time.Time) (Report, error)
+}
+
+// Event is a single overflow at a combined sewer overflow outlet.
+type Event struct {
+	ThingID string
+	Name    string
+	things.Period
+}
+
+// Summary aggregates the overflow events of one outlet during the report period.
+type Summary struct {
+	ThingID   string
+	Name      string
+	Tenant    string
+	Count     int
+	Total     time.Duration
+	Longest   time.Duration
+	LastStart *time.Time
+	// Failed is set when the history of the outlet could not be fetched.
+	Failed bool
+}
+
+// Report holds the overflow events of every combined sewer overflow outlet between From and To.
+type Report struct {
+	From      time.Time
+	To        time.Time
+	Summaries []Summary
+	Events    []Event
+}
+
+// Count is the total number of overflow events in the report.
+func (r Report) Count() int {
+	return len(r.Events)
+}
+
+// Total is the accumulated overflow time of all outlets in the report.
+func (r Report) Total() time.Duration {
+	var total time.Duration
+	for _, s := range r.Summaries {
+		total += s.Total
+	}
+	return total
+}
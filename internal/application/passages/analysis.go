@@ -0,0 +1,76 @@
+package passages
+
+import (
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// Count is the number of passages during the hour that starts at Timestamp.
+type Count struct {
+	Timestamp time.Time
+	Passages  int
+}
+
+// Counts picks the hourly number of passages out of a series of measurements.
+func Counts(values []things.Measurement) []Count {
+	counts := make([]Count, 0, len(values))
+	for _, m := range values {
+		var passages float64
+		switch {
+		case m.Count != nil:
+			passages = *m.Count
+		case m.Value != nil:
+			passages = *m.Value
+		default:
+			continue
+		}
+		counts = append(counts, Count{Timestamp: m.Timestamp, Passages: int(passages)})
+	}
+	return counts
+}
+
+// Analyze sums the hourly counts between from and to into hours, days and weeks, and spreads them
+// over the weekdays and hours of the heatmap. Days, weeks and the heatmap follow the time zone of from.
+func Analyze(counts []Count, from, to time.Time) Statistics {
+	s := Statistics{From: from, To: to}
+
+	inPeriod := make([]Count, 0, len(counts))
+	for _, c := range slices.SortedFunc(slices.Values(counts), func(a, b Count) int { return a.Timestamp.Compare(b.Timestamp) }) {
+		if c.Timestamp.Before(from) || !c.Timestamp.Before(to) {
+			continue
+		}
+		inPeriod = append(inPeriod, c)
+		s.Total += c.Passages
+
+		t := c.Timestamp.In(from.Location())
+		s.Heatmap[weekday(t)][t.Hour()] += c.Passages
+	}
+
+	s.Hours = sums(inPeriod, things.Buckets(from, to, func(t time.Time) time.Time { return t.Truncate(time.Hour).Add(time.Hour) }))
+	s.Days = sums(inPeriod, things.Buckets(from, to, things.NextDay))
+	s.Weeks = sums(inPeriod, things.Buckets(from, to, things.NextWeek))
+
+	return s
+}
+
+// sums sums the passages into each of the consecutive periods. The counts must be sorted and within
+// the periods.
+func sums(counts []Count, periods []things.Period) []Bucket {
+	result := []Bucket{}
+	i := 0
+	for _, p := range periods {
+		b := Bucket{Start: p.Start}
+		for ; i < len(counts) && counts[i].Timestamp.Before(p.End); i++ {
+			b.Passages += counts[i].Passages
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+// weekday numbers the days of the week from Monday.
+func weekday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
@@ -0,0 +1,79 @@
+package passages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+// monday is the start of a week in Stockholm time
+var monday = time.Date(2026, 3, 2, 0, 0, 0, 0, stockholm())
+
+func stockholm() *time.Location {
+	loc, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func TestAnalyzeSumsHoursDaysAndWeeks(t *testing.T) {
+	is := is.New(t)
+
+	counts := []Count{
+		{Timestamp: monday.Add(8 * time.Hour), Passages: 5},
+		{Timestamp: monday.Add(8*time.Hour + 30*time.Minute), Passages: 1},
+		{Timestamp: monday.Add(17 * time.Hour), Passages: 3},
+		{Timestamp: monday.AddDate(0, 0, 7).Add(8 * time.Hour), Passages: 2},
+		// outside of the period
+		{Timestamp: monday.Add(-time.Hour), Passages: 100},
+		{Timestamp: monday.AddDate(0, 0, 8), Passages: 100},
+	}
+
+	s := Analyze(counts, monday, monday.AddDate(0, 0, 8))
+
+	is.Equal(11, s.Total)
+	is.Equal(8*24, len(s.Hours))
+	is.Equal(6, s.Hours[8].Passages)
+	is.Equal(3, s.Hours[17].Passages)
+	is.Equal(8, len(s.Days))
+	is.Equal(9, s.Days[0].Passages)
+	is.Equal(2, s.Days[7].Passages)
+	is.Equal(2, len(s.Weeks))
+	is.Equal(9, s.Weeks[0].Passages)
+	is.Equal(2, s.Weeks[1].Passages)
+}
+
+func TestAnalyzeSpreadsPassagesOverWeekdaysAndHours(t *testing.T) {
+	is := is.New(t)
+
+	counts := []Count{
+		{Timestamp: monday.Add(8 * time.Hour), Passages: 5},
+		{Timestamp: monday.AddDate(0, 0, 7).Add(8 * time.Hour), Passages: 2},
+		// sunday evening, reported in UTC
+		{Timestamp: monday.AddDate(0, 0, 6).Add(20 * time.Hour).UTC(), Passages: 4},
+	}
+
+	s := Analyze(counts, monday, monday.AddDate(0, 0, 14))
+
+	is.Equal(7, s.Heatmap[0][8])
+	is.Equal(4, s.Heatmap[6][20])
+	is.Equal(7, s.Heatmap.Max())
+}
+
+func TestCountsPrefersTheCountOfTheHour(t *testing.T) {
+	is := is.New(t)
+
+	count, value := 4.0, 1.0
+	counts := Counts([]things.Measurement{
+		{Timestamp: monday, Count: &count, Value: &value},
+		{Timestamp: monday.Add(time.Hour), Value: &value},
+		{Timestamp: monday.Add(2 * time.Hour)},
+	})
+
+	is.Equal(2, len(counts))
+	is.Equal(4, counts[0].Passages)
+	is.Equal(1, counts[1].Passages)
+}
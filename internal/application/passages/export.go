@@ -0,0 +1,26 @@
+package passages
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+)
+
+// BucketsCSV writes one row per hour, day or week with its start in loc and the number of passages.
+func BucketsCSV(buckets []Bucket, loc *time.Location) ([]byte, error) {
+	rows := [][]string{{"start", "passages"}}
+	for _, b := range buckets {
+		rows = append(rows, []string{b.Start.In(loc).Format(time.RFC3339), strconv.Itoa(b.Passages)})
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
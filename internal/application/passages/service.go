@@ -0,0 +1,82 @@
+package passages
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/passages")
+
+// passagesURN is the digital input of the door sensor, where every true value is one passage.
+const passagesURN = "10351/50"
+
+type Service struct {
+	things things.Getter
+}
+
+func NewService(things things.Getter) *Service {
+	return &Service{things: things}
+}
+
+// GetPassageStatistics sums the passages of a thing between from and to, together with the same
+// period last year for comparison. Failing to fetch last year only leaves the comparison out.
+func (s *Service) GetPassageStatistics(ctx context.Context, thingID string, from, to time.Time) (Statistics, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-passage-statistics")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	var current, lastYear Statistics
+	var lastYearErr error
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		current, err = s.statistics(ctx, thingID, from, to)
+	})
+	wg.Go(func() {
+		lastYear, lastYearErr = s.statistics(ctx, thingID, from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0))
+	})
+	wg.Wait()
+
+	if err != nil {
+		return Statistics{}, err
+	}
+
+	if lastYearErr != nil {
+		logging.GetFromContext(ctx).Debug("could not fetch passages for last year", "thing_id", thingID, "err", lastYearErr.Error())
+	} else {
+		current.LastYear = &lastYear
+	}
+
+	return current, nil
+}
+
+func (s *Service) statistics(ctx context.Context, thingID string, from, to time.Time) (Statistics, error) {
+	// the backend counts the passages per hour, the same way as the measurement chart of the thing
+	query := url.Values{}
+	query.Add("timerel", "between")
+	query.Add("timeat", from.UTC().Format(time.RFC3339))
+	query.Add("endTimeAt", to.UTC().Format(time.RFC3339))
+	query.Add("n", passagesURN)
+	query.Add("timeunit", "hour")
+	query.Add("vb", "true")
+
+	thing, err := s.things.GetThing(ctx, thingID, query)
+	if err != nil {
+		return Statistics{}, err
+	}
+
+	stats := Analyze(Counts(slices.Concat(thing.Values...)), from, to)
+	stats.ThingID = thingID
+	stats.Name = cmp.Or(thing.Name, thingID)
+
+	return stats, nil
+}
@@ -0,0 +1,75 @@
+package passages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+func TestGetPassageStatisticsComparesWithLastYear(t *testing.T) {
+	is := is.New(t)
+
+	from := monday
+	to := monday.AddDate(0, 0, 1)
+
+	svc := NewService(testThings{counts: map[int][]float64{
+		from.Year():     {3, 2, 5},
+		from.Year() - 1: {4, 4},
+	}})
+
+	s, err := svc.GetPassageStatistics(context.Background(), "passage-1", from, to)
+	is.NoErr(err)
+	is.Equal(10, s.Total)
+	is.True(s.LastYear != nil)
+	is.Equal(8, s.LastYear.Total)
+	is.True(s.LastYear.From.Equal(from.AddDate(-1, 0, 0)))
+
+	change, ok := s.Change()
+	is.True(ok)
+	is.Equal(25.0, change)
+}
+
+func TestGetPassageStatisticsWithoutLastYear(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(testThings{counts: map[int][]float64{monday.Year(): {3}}})
+
+	s, err := svc.GetPassageStatistics(context.Background(), "passage-1", monday, monday.AddDate(0, 0, 1))
+	is.NoErr(err)
+	is.Equal(3, s.Total)
+	is.Equal(nil, s.LastYear)
+
+	_, ok := s.Change()
+	is.True(!ok)
+}
+
+// testThings returns hourly counts from the start of the requested period, keyed by its year.
+type testThings struct {
+	counts map[int][]float64
+}
+
+func (t testThings) GetThing(_ context.Context, id string, params map[string][]string) (things.Thing, error) {
+	if params["n"][0] != passagesURN || params["timeunit"][0] != "hour" {
+		return things.Thing{}, errors.New("unexpected query")
+	}
+
+	from, err := time.Parse(time.RFC3339, params["timeat"][0])
+	if err != nil {
+		return things.Thing{}, err
+	}
+
+	counts, ok := t.counts[from.Year()]
+	if !ok {
+		return things.Thing{}, errors.New("not found")
+	}
+
+	values := []things.Measurement{}
+	for i, c := range counts {
+		values = append(values, things.Measurement{Timestamp: from.Add(time.Duration(i) * time.Hour), Count: &c})
+	}
+	return things.Thing{ID: id, Values: [][]things.Measurement{values}}, nil
+}
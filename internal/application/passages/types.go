@@ -0,0 +1,60 @@
+package passages
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultDays is how many days, including today, the statistics of a passage cover unless another period is asked for.
+const DefaultDays = 7
+
+type Management interface {
+	GetPassageStatistics(ctx context.Context, thingID string, from, to time.Time) (Statistics, error)
+}
+
+// Bucket holds the number of passages during an hour, a day or a week.
+type Bucket struct {
+	Start    time.Time
+	Passages int
+}
+
+// Heatmap holds the number of passages per weekday, starting with Monday, and hour of the day.
+type Heatmap [7][24]int
+
+// Max is the highest number of passages in a single cell of the heatmap.
+func (h Heatmap) Max() int {
+	highest := 0
+	for _, day := range h {
+		for _, passages := range day {
+			highest = max(highest, passages)
+		}
+	}
+	return highest
+}
+
+// Statistics are the passages through a passage thing between From and To, summed per hour, day
+// and week from the hourly counts of its measurement history.
+type Statistics struct {
+	ThingID string
+	Name    string
+	From    time.Time
+	To      time.Time
+
+	Total   int
+	Hours   []Bucket
+	Days    []Bucket
+	Weeks   []Bucket
+	Heatmap Heatmap
+
+	// LastYear holds the same period one year earlier, or nil if its history could not be fetched.
+	LastYear *Statistics
+}
+
+// Change is the change in percent of the total compared to the same period last year. It is not
+// known when there is nothing to compare with.
+func (s Statistics) Change() (float64, bool) {
+	if s.LastYear == nil || s.LastYear.Total == 0 {
+		return 0, false
+	}
+	return float64(s.Total-s.LastYear.Total) / float64(s.LastYear.Total) * 100, true
+}
@@ -0,0 +1,104 @@
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+
+	// embed the time zone database so that preferred time zones can be loaded in minimal containers
+	_ "time/tzdata"
+)
+
+var tracer = otel.Tracer("diwise-web/app/preferences")
+
+const preferencesCollection = "preferences"
+
+type Service struct {
+	store storage.Store
+
+	// the preferences are read for every request, so the stored ones are kept by subject once loaded
+	mu    sync.RWMutex
+	cache map[string]Preferences
+}
+
+func NewService(store storage.Store) *Service {
+	return &Service{store: store, cache: map[string]Preferences{}}
+}
+
+// GetPreferences returns the preferences of the current user, or empty preferences if none have been saved
+// or the user is not known.
+func (s *Service) GetPreferences(ctx context.Context) (Preferences, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-preferences")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	subject := authz.Subject(ctx)
+	if subject == "" {
+		return Preferences{}, nil
+	}
+
+	s.mu.RLock()
+	p, ok := s.cache[subject]
+	s.mu.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	document, err := s.store.Get(ctx, preferencesCollection, subject)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		err = nil
+	case err != nil:
+		return Preferences{}, err
+	default:
+		err = json.Unmarshal(document, &p)
+		if err != nil {
+			return Preferences{}, err
+		}
+	}
+
+	s.remember(subject, p)
+	return p, nil
+}
+
+// SavePreferences replaces the preferences of the current user.
+func (s *Service) SavePreferences(ctx context.Context, p Preferences) (Preferences, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "save-preferences")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	subject, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	err = p.Validate()
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	document, err := json.Marshal(p)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	err = s.store.Put(ctx, preferencesCollection, subject, document)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	s.remember(subject, p)
+	return p, nil
+}
+
+func (s *Service) remember(subject string, p Preferences) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[subject] = p
+}
@@ -0,0 +1,118 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestPreferencesAreKeptPerUser(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	_, err := svc.SavePreferences(withSubject("alice"), Preferences{PageSize: 50, ListView: ListViewMap, TimeZone: "Europe/Stockholm"})
+	is.NoErr(err)
+
+	alice, err := svc.GetPreferences(withSubject("alice"))
+	is.NoErr(err)
+	is.Equal(50, alice.PageSize)
+	is.Equal(ListViewMap, alice.ListView)
+
+	bob, err := svc.GetPreferences(withSubject("bob"))
+	is.NoErr(err)
+	is.Equal(Preferences{}, bob)
+}
+
+func TestPreferencesAreReadFromTheStoreOnce(t *testing.T) {
+	is := is.New(t)
+	files, err := storage.NewFileStore(t.TempDir())
+	is.NoErr(err)
+	store := &countingStore{Store: files}
+	svc := NewService(store)
+
+	for range 3 {
+		_, err = svc.GetPreferences(withSubject("alice"))
+		is.NoErr(err)
+	}
+	is.Equal(1, store.gets)
+
+	// saved preferences replace the cached ones
+	_, err = svc.SavePreferences(withSubject("alice"), Preferences{PageSize: 50})
+	is.NoErr(err)
+	p, err := svc.GetPreferences(withSubject("alice"))
+	is.NoErr(err)
+	is.Equal(50, p.PageSize)
+	is.Equal(1, store.gets)
+}
+
+func TestPreferencesRequireASubject(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	_, err := svc.SavePreferences(context.Background(), Preferences{PageSize: 50})
+	is.True(errors.Is(err, authz.ErrNoSubject))
+
+	p, err := svc.GetPreferences(context.Background())
+	is.NoErr(err)
+	is.Equal(Preferences{}, p)
+}
+
+func TestSavePreferencesRejectsUnsupportedValues(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	_, err := svc.SavePreferences(withSubject("alice"), Preferences{PageSize: 25})
+	is.True(errors.Is(err, ErrInvalid))
+
+	_, err = svc.SavePreferences(withSubject("alice"), Preferences{TimeZone: "Mars/Olympus"})
+	is.True(errors.Is(err, ErrInvalid))
+
+	_, err = svc.SavePreferences(withSubject("alice"), Preferences{CoordinateSystem: "rt38"})
+	is.True(errors.Is(err, ErrInvalid))
+
+	_, err = svc.SavePreferences(withSubject("alice"), Preferences{CoordinateSystem: "sweref99_1630"})
+	is.NoErr(err)
+}
+
+func TestChartBounds(t *testing.T) {
+	is := is.New(t)
+	now := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+
+	start, end := Preferences{ChartRange: ChartRangeToday}.ChartBounds(now)
+	is.Equal(time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), start)
+	is.Equal(time.Date(2024, 5, 10, 23, 59, 0, 0, time.UTC), end)
+
+	start, end = Preferences{ChartRange: ChartRange7d}.ChartBounds(now)
+	is.Equal(time.Date(2024, 5, 3, 14, 30, 0, 0, time.UTC), start)
+	is.Equal(now, end)
+
+	start, _ = Preferences{}.ChartBounds(now)
+	is.True(start.IsZero())
+}
+
+func newTestService(t *testing.T) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(store)
+}
+
+func withSubject(subject string) context.Context {
+	return context.WithValue(context.Background(), authz.SubjectClaim, subject)
+}
+
+type countingStore struct {
+	storage.Store
+	gets int
+}
+
+func (s *countingStore) Get(ctx context.Context, collection, key string) ([]byte, error) {
+	s.gets++
+	return s.Store.Get(ctx, collection, key)
+}
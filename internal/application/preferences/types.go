@@ -0,0 +1,107 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/geo"
+)
+
+var ErrInvalid = errors.New("invalid preferences")
+
+type Management interface {
+	GetPreferences(ctx context.Context) (Preferences, error)
+	SavePreferences(ctx context.Context, p Preferences) (Preferences, error)
+}
+
+const (
+	ListViewTable = "table"
+	ListViewMap   = "map"
+)
+
+const (
+	ChartRangeToday = "today"
+	ChartRange24h   = "24h"
+	ChartRange7d    = "7d"
+	ChartRange30d   = "30d"
+)
+
+var (
+	PageSizes   = []int{5, 10, 15, 50, 100}
+	ListViews   = []string{ListViewTable, ListViewMap}
+	Languages   = []string{"sv", "en"}
+	ChartRanges = []string{ChartRangeToday, ChartRange24h, ChartRange7d, ChartRange30d}
+	TimeZones   = []string{"Europe/Stockholm", "Europe/Helsinki", "Europe/Oslo", "Europe/Copenhagen", "Europe/London", "UTC"}
+
+	CoordinateSystems = geo.Systems()
+)
+
+// Preferences are the personal defaults of a user. A zero value field means that the user has no
+// preference and that the application default applies.
+type Preferences struct {
+	PageSize   int    `json:"pageSize,omitzero"`
+	ListView   string `json:"listView,omitzero"`
+	Language   string `json:"language,omitzero"`
+	TimeZone   string `json:"timeZone,omitzero"`
+	ChartRange string `json:"chartRange,omitzero"`
+	// CoordinateSystem is the system that positions are shown in, see geo.Systems.
+	CoordinateSystem string `json:"coordinateSystem,omitzero"`
+}
+
+func (p Preferences) Validate() error {
+	if p.PageSize != 0 && !slices.Contains(PageSizes, p.PageSize) {
+		return errors.Join(ErrInvalid, errors.New("unsupported page size"))
+	}
+	if p.ListView != "" && !slices.Contains(ListViews, p.ListView) {
+		return errors.Join(ErrInvalid, errors.New("unsupported list view"))
+	}
+	if p.Language != "" && !slices.Contains(Languages, p.Language) {
+		return errors.Join(ErrInvalid, errors.New("unsupported language"))
+	}
+	if p.ChartRange != "" && !slices.Contains(ChartRanges, p.ChartRange) {
+		return errors.Join(ErrInvalid, errors.New("unsupported chart range"))
+	}
+	if p.CoordinateSystem != "" && !slices.Contains(CoordinateSystems, p.CoordinateSystem) {
+		return errors.Join(ErrInvalid, errors.New("unsupported coordinate system"))
+	}
+	if p.TimeZone != "" {
+		if _, err := time.LoadLocation(p.TimeZone); err != nil {
+			return errors.Join(ErrInvalid, err)
+		}
+	}
+	return nil
+}
+
+// Location returns the preferred time zone, or nil if the user has no preference.
+func (p Preferences) Location() *time.Location {
+	if p.TimeZone == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return nil
+	}
+
+	return loc
+}
+
+// ChartBounds returns the default start and end of measurement charts relative to now. Both times
+// are zero if the user has no preferred chart range.
+func (p Preferences) ChartBounds(now time.Time) (time.Time, time.Time) {
+	switch p.ChartRange {
+	case ChartRangeToday:
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		return start, start.Add(24*time.Hour - time.Minute)
+	case ChartRange24h:
+		return now.Add(-24 * time.Hour), now
+	case ChartRange7d:
+		return now.AddDate(0, 0, -7), now
+	case ChartRange30d:
+		return now.AddDate(0, 0, -30), now
+	default:
+		return time.Time{}, time.Time{}
+	}
+}
@@ -0,0 +1,144 @@
+package properties
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Load reads the property schemas from a JSON file. An empty path gives the default schemas.
+func Load(path string) (Schemas, error) {
+	if strings.TrimSpace(path) == "" {
+		return Defaults, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse reads property schemas such as
+//
+//	[{"types": ["WasteContainer"], "fields": [{"name": "maxd", "label": "maxcontainerheight", "unit": "m", "step": 0.01, "min": 0, "required": true}]}]
+//
+// The type of a field is a number unless it is given as integer.
+func Parse(r io.Reader) (Schemas, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	schemas := Schemas{}
+	if err := decoder.Decode(&schemas); err != nil {
+		return nil, fmt.Errorf("invalid property schemas: %w", err)
+	}
+
+	for i, schema := range schemas {
+		if len(schema.Types) == 0 {
+			return nil, fmt.Errorf("property schema %d has no types", i+1)
+		}
+
+		names := map[string]bool{}
+		for j, field := range schema.Fields {
+			if field.Name == "" || field.Label == "" {
+				return nil, fmt.Errorf("field %d of the schema for %s needs a name and a label", j+1, schema.Types[0])
+			}
+			if names[field.Name] {
+				return nil, fmt.Errorf("the schema for %s has more than one field named %s", schema.Types[0], field.Name)
+			}
+			names[field.Name] = true
+
+			switch field.Type {
+			case "":
+				schemas[i].Fields[j].Type = TypeNumber
+			case TypeNumber, TypeInteger:
+			default:
+				return nil, fmt.Errorf("field %s of the schema for %s has the unknown type %q", field.Name, schema.Types[0], field.Type)
+			}
+
+			if field.Step < 0 || (field.Min != nil && field.Max != nil && *field.Min > *field.Max) {
+				return nil, fmt.Errorf("field %s of the schema for %s has an invalid range", field.Name, schema.Types[0])
+			}
+		}
+	}
+
+	return schemas, nil
+}
+
+// Values checks the submitted values of the fields of the schema and returns them as numbers.
+// Fields that are left out of the form are left out of the result, so that forms that show none
+// of the properties can be submitted. Fields that are submitted empty are only accepted when they
+// are not required.
+func (s Schema) Values(form map[string][]string) (map[string]any, error) {
+	values := map[string]any{}
+
+	for _, field := range s.Fields {
+		submitted, ok := form[field.Name]
+		if !ok || len(submitted) == 0 {
+			continue
+		}
+
+		value, err := field.Parse(submitted[0])
+		if err != nil {
+			return nil, FieldError{Field: field, Err: err}
+		}
+		if value != nil {
+			values[field.Name] = *value
+		}
+	}
+
+	return values, nil
+}
+
+// Parse checks a submitted value against the field. An empty value gives nil unless the field is
+// required. A decimal comma is accepted.
+func (f Field) Parse(value string) (*float64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", ".")
+	if value == "" {
+		if f.Required {
+			return nil, ErrRequired
+		}
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return nil, ErrInvalid
+	}
+
+	if f.Min != nil && number < *f.Min {
+		return nil, ErrTooSmall
+	}
+	if f.Max != nil && number > *f.Max {
+		return nil, ErrTooLarge
+	}
+
+	step := f.Step
+	if f.Type == TypeInteger && step == 0 {
+		step = 1
+	}
+	if step > 0 {
+		base := 0.0
+		if f.Min != nil {
+			base = *f.Min
+		}
+		steps := (number - base) / step
+		if math.Abs(steps-math.Round(steps)) > 1e-6 {
+			return nil, ErrStep
+		}
+	}
+
+	return &number, nil
+}
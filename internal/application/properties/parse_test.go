@@ -0,0 +1,84 @@
+package properties
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSchemaForUsesTheSubtypeOrTheType(t *testing.T) {
+	is := is.New(t)
+
+	schema, ok := Defaults.For("Container", "Sandstorage")
+	is.True(ok)
+	is.Equal(3, len(schema.Fields))
+
+	schema, ok = Defaults.For("Container", "")
+	is.True(ok)
+	is.Equal(2, len(schema.Fields))
+
+	_, ok = Defaults.For("Container", "Sandfilter")
+	is.True(!ok)
+
+	_, ok = Defaults.For("PumpingStation", "")
+	is.True(!ok)
+}
+
+func TestParseSchemas(t *testing.T) {
+	is := is.New(t)
+
+	schemas, err := Parse(strings.NewReader(`[
+		{"types": ["Beehive"], "fields": [
+			{"name": "frames", "label": "frames", "type": "integer", "min": 1, "max": 20, "required": true},
+			{"name": "height", "label": "height", "unit": "m", "step": 0.05}
+		]}
+	]`))
+	is.NoErr(err)
+
+	schema, ok := schemas.For("Beehive", "")
+	is.True(ok)
+	is.Equal(TypeNumber, schema.Fields[1].Type)
+	is.Equal(0, schema.Fields[0].Decimals())
+	is.Equal(2, schema.Fields[1].Decimals())
+
+	_, err = Parse(strings.NewReader(`[{"types": ["Beehive"], "fields": [{"name": "frames", "label": "frames", "type": "text"}]}]`))
+	is.True(err != nil)
+
+	_, err = Parse(strings.NewReader(`[{"fields": []}]`))
+	is.True(err != nil)
+}
+
+func TestValuesAreValidatedAgainstTheSchema(t *testing.T) {
+	is := is.New(t)
+	schema, _ := Defaults.For("Container", "Sandstorage")
+
+	values, err := schema.Values(map[string][]string{"maxd": {"1,5"}, "angle": {"30"}, "maxl": {""}})
+	is.NoErr(err)
+	is.Equal(map[string]any{"maxd": 1.5, "angle": 30.0}, values)
+
+	_, err = schema.Values(map[string][]string{"angle": {"95"}})
+	is.True(errors.Is(err, ErrTooLarge))
+
+	values, err = schema.Values(map[string][]string{"angle": {"3.75"}})
+	is.NoErr(err)
+	is.Equal(map[string]any{"angle": 3.75}, values)
+
+	_, err = schema.Values(map[string][]string{"angle": {"12.505"}})
+	is.True(errors.Is(err, ErrStep))
+
+	_, err = schema.Values(map[string][]string{"maxd": {"high"}})
+	var fieldErr FieldError
+	is.True(errors.As(err, &fieldErr))
+	is.Equal("maxd", fieldErr.Field.Name)
+	is.True(errors.Is(err, ErrInvalid))
+
+	required := Schema{Fields: []Field{{Name: "frames", Label: "frames", Type: TypeInteger, Required: true}}}
+	_, err = required.Values(map[string][]string{"frames": {" "}})
+	is.True(errors.Is(err, ErrRequired))
+
+	values, err = required.Values(map[string][]string{})
+	is.NoErr(err)
+	is.Equal(0, len(values))
+}
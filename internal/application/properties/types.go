@@ -0,0 +1,124 @@
+package properties
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrRequired = errors.New("a value is required")
+	ErrInvalid  = errors.New("value is not a number")
+	ErrTooSmall = errors.New("value is below the minimum")
+	ErrTooLarge = errors.New("value is above the maximum")
+	ErrStep     = errors.New("value does not match the step")
+)
+
+const (
+	TypeNumber  string = "number"
+	TypeInteger string = "integer"
+)
+
+// Field is a property of the things of a type, such as the height of a container. Label and Unit
+// are keys in the localization files.
+type Field struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Unit     string   `json:"unit,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Step     float64  `json:"step,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// Decimals is the number of decimals that the values of the field are shown with.
+func (f Field) Decimals() int {
+	if f.Type == TypeInteger {
+		return 0
+	}
+
+	step := fmt.Sprintf("%g", f.Step)
+	if f.Step <= 0 || strings.Contains(step, "e") {
+		return 2
+	}
+
+	_, decimals, _ := strings.Cut(step, ".")
+	return len(decimals)
+}
+
+// FieldError tells which field a submitted value was rejected for.
+type FieldError struct {
+	Field Field
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field.Name, e.Err.Error())
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Schema describes the properties of the things of one or more types. A type matches the subtype of
+// a thing, or its type when it has no subtype, such as Container or WasteContainer.
+type Schema struct {
+	Types  []string `json:"types"`
+	Fields []Field  `json:"fields"`
+}
+
+// Schemas are the property schemas of all types of things.
+type Schemas []Schema
+
+// For returns the schema of a thing. It is picked by the subtype of the thing, or by its type when
+// it has no subtype, so a subtype without a schema has no properties.
+func (s Schemas) For(thingType, subType string) (Schema, bool) {
+	kind := strings.TrimSpace(subType)
+	if kind == "" {
+		kind = strings.TrimSpace(thingType)
+	}
+	if kind == "" {
+		return Schema{}, false
+	}
+	for _, schema := range s {
+		for _, t := range schema.Types {
+			if strings.EqualFold(t, kind) {
+				return schema, true
+			}
+		}
+	}
+	return Schema{}, false
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+// Defaults are the schemas that are used unless others are configured.
+var Defaults = Schemas{
+	{
+		Types: []string{"Container", "WasteContainer"},
+		Fields: []Field{
+			{Name: "maxd", Label: "maxcontainerheight", Unit: "m", Type: TypeNumber, Step: 0.01, Min: ptr(0)},
+			{Name: "maxl", Label: "maxfillinglevel", Unit: "m", Type: TypeNumber, Step: 0.01, Min: ptr(0)},
+		},
+	},
+	{
+		Types: []string{"Sandstorage"},
+		Fields: []Field{
+			{Name: "maxd", Label: "maxcontainerheight", Unit: "m", Type: TypeNumber, Step: 0.01, Min: ptr(0)},
+			{Name: "maxl", Label: "maxfillinglevel", Unit: "m", Type: TypeNumber, Step: 0.01, Min: ptr(0)},
+			// the angles of storages that were set before the schemas have decimals
+			{Name: "angle", Label: "angle", Unit: "degrees", Type: TypeNumber, Step: 0.01, Min: ptr(0), Max: ptr(90)},
+		},
+	},
+	{
+		Types: []string{"CombinedSewerOverflow", "Sewer"},
+		Fields: []Field{
+			{Name: "maxd", Label: "maxdistancesewer", Unit: "m", Type: TypeNumber, Step: 0.01, Min: ptr(0)},
+			{Name: "maxl", Label: "maxwaterlevel", Unit: "m", Type: TypeNumber, Step: 0.01, Min: ptr(0)},
+			{Name: "offset", Label: "offset", Unit: "m", Type: TypeNumber, Step: 0.01},
+		},
+	},
+}
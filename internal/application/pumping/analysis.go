@@ -0,0 +1,164 @@
+package pumping
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+const (
+	// BaselinePeriod is how far back before the analysed period the baseline of a station reaches.
+	BaselinePeriod = 28 * 24 * time.Hour
+
+	minBaselineDays     = 7
+	minContinuousRun    = time.Hour
+	continuousRunFactor = 5
+)
+
+// Analyze computes the statistics of a station from its pump cycles between from and to, and flags
+// the cycles and days that stand out compared with the baseline cycles before from. Days and weeks
+// follow the time zone of from.
+func Analyze(periods, baseline []things.Period, baselineFrom, from, to time.Time) Stats {
+	stats := Stats{From: from, To: to}
+
+	stats.Days = cycles(periods, things.Buckets(from, to, things.NextDay))
+	stats.Weeks = cycles(periods, things.Buckets(from, to, things.NextWeek))
+
+	finished := 0
+	var finishedRuntime time.Duration
+	for _, p := range periods {
+		d := p.Duration()
+		stats.Starts++
+		stats.Runtime += d
+		stats.LongestCycle = max(stats.LongestCycle, d)
+		if p.Ongoing {
+			stats.Running = true
+			continue
+		}
+		finished++
+		finishedRuntime += d
+	}
+	if finished > 0 {
+		stats.AverageCycle = finishedRuntime / time.Duration(finished)
+	}
+
+	stats.Anomalies = append(continuousRuns(periods, baseline), frequentStarts(stats.Days, baselineDays(baseline, baselineFrom, from))...)
+
+	return stats
+}
+
+// baselineDays counts the starts per day before from, beginning with the day of the first recorded
+// cycle so that a station without history does not get a baseline of quiet days.
+func baselineDays(baseline []things.Period, baselineFrom, from time.Time) []Bucket {
+	if len(baseline) == 0 {
+		return nil
+	}
+
+	first := slices.MinFunc(baseline, func(a, b things.Period) int { return a.Start.Compare(b.Start) }).Start
+	start := maxTime(things.StartOfDay(first.In(from.Location())), baselineFrom)
+
+	return cycles(baseline, things.Buckets(start, from, things.NextDay))
+}
+
+func continuousRuns(periods, baseline []things.Period) []Anomaly {
+	usual := averageCycle(baseline)
+	limit := max(minContinuousRun, usual*continuousRunFactor)
+
+	anomalies := []Anomaly{}
+	for _, p := range periods {
+		if d := p.Duration(); d > limit {
+			anomalies = append(anomalies, Anomaly{
+				Kind:     AnomalyContinuousRunning,
+				At:       p.Start,
+				Value:    d.Minutes(),
+				Baseline: usual.Minutes(),
+			})
+		}
+	}
+	return anomalies
+}
+
+func frequentStarts(days, baseline []Bucket) []Anomaly {
+	if len(baseline) < minBaselineDays {
+		return nil
+	}
+
+	var sum float64
+	for _, b := range baseline {
+		sum += float64(b.Starts)
+	}
+	mean := sum / float64(len(baseline))
+
+	var variance float64
+	for _, b := range baseline {
+		variance += math.Pow(float64(b.Starts)-mean, 2)
+	}
+	stddev := math.Sqrt(variance / float64(len(baseline)))
+
+	// a quiet station with a steady rhythm would otherwise be flagged for a couple of extra starts
+	limit := mean + max(3*stddev, mean/2, 2)
+
+	anomalies := []Anomaly{}
+	for _, d := range days {
+		if float64(d.Starts) > limit {
+			anomalies = append(anomalies, Anomaly{
+				Kind:     AnomalyFrequentStarts,
+				At:       d.Start,
+				Value:    float64(d.Starts),
+				Baseline: mean,
+			})
+		}
+	}
+	return anomalies
+}
+
+func averageCycle(periods []things.Period) time.Duration {
+	var total time.Duration
+	count := 0
+	for _, p := range periods {
+		if p.Ongoing {
+			continue
+		}
+		total += p.Duration()
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// cycles counts the pump cycles in each of the buckets. A start is counted in the bucket where the
+// cycle started, while the runtime of a cycle is divided over the buckets it spans.
+func cycles(periods []things.Period, buckets []things.Period) []Bucket {
+	result := []Bucket{}
+	for _, bucket := range buckets {
+		b := Bucket{Start: bucket.Start}
+		for _, p := range periods {
+			if !p.Start.Before(bucket.Start) && p.Start.Before(bucket.End) {
+				b.Starts++
+			}
+			if overlap := minTime(p.End, bucket.End).Sub(maxTime(p.Start, bucket.Start)); overlap > 0 {
+				b.Runtime += overlap
+			}
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
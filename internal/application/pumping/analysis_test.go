@@ -0,0 +1,80 @@
+package pumping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+var monday = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+func TestAnalyzeSplitsRuntimeOverDaysAndWeeks(t *testing.T) {
+	is := is.New(t)
+
+	from, to := monday, monday.AddDate(0, 0, 10)
+	periods := []things.Period{
+		cycle(monday.Add(10*time.Hour), 20*time.Minute),
+		cycle(monday.Add(23*time.Hour+30*time.Minute), time.Hour),
+		cycle(monday.AddDate(0, 0, 8), 10*time.Minute),
+	}
+
+	stats := Analyze(periods, nil, from.Add(-BaselinePeriod), from, to)
+
+	is.Equal(3, stats.Starts)
+	is.Equal(90*time.Minute, stats.Runtime)
+	is.Equal(30*time.Minute, stats.AverageCycle)
+	is.Equal(10, len(stats.Days))
+	is.Equal(2, stats.Days[0].Starts)
+	is.Equal(50*time.Minute, stats.Days[0].Runtime)
+	is.Equal(0, stats.Days[1].Starts)
+	is.Equal(30*time.Minute, stats.Days[1].Runtime)
+
+	is.Equal(2, len(stats.Weeks))
+	is.Equal(80*time.Minute, stats.Weeks[0].Runtime)
+	is.Equal(monday.AddDate(0, 0, 7), stats.Weeks[1].Start)
+	is.Equal(1, stats.Weeks[1].Starts)
+}
+
+func TestAnalyzeFlagsAnomaliesAgainstBaseline(t *testing.T) {
+	is := is.New(t)
+
+	baselineFrom, from := monday.AddDate(0, 0, -14), monday
+	baseline := []things.Period{}
+	for day := range 14 {
+		for start := range 4 {
+			baseline = append(baseline, cycle(baselineFrom.AddDate(0, 0, day).Add(time.Duration(start*6)*time.Hour), 15*time.Minute))
+		}
+	}
+
+	periods := []things.Period{cycle(from.Add(time.Hour), 3*time.Hour)}
+	for start := range 12 {
+		periods = append(periods, cycle(from.AddDate(0, 0, 1).Add(time.Duration(start)*time.Hour), 10*time.Minute))
+	}
+
+	stats := Analyze(periods, baseline, baselineFrom, from, from.AddDate(0, 0, 2))
+
+	is.Equal(2, len(stats.Anomalies))
+	is.Equal(AnomalyContinuousRunning, stats.Anomalies[0].Kind)
+	is.Equal(15.0, stats.Anomalies[0].Baseline)
+	is.Equal(AnomalyFrequentStarts, stats.Anomalies[1].Kind)
+	is.Equal(from.AddDate(0, 0, 1), stats.Anomalies[1].At)
+	is.Equal(4.0, stats.Anomalies[1].Baseline)
+}
+
+func TestAnalyzeNeedsBaselineToFlagFrequentStarts(t *testing.T) {
+	is := is.New(t)
+
+	periods := []things.Period{}
+	for start := range 20 {
+		periods = append(periods, cycle(monday.Add(time.Duration(start)*time.Hour), time.Minute))
+	}
+
+	stats := Analyze(periods, nil, monday.Add(-BaselinePeriod), monday, monday.AddDate(0, 0, 1))
+	is.Equal(0, len(stats.Anomalies))
+}
+
+func cycle(start time.Time, d time.Duration) things.Period {
+	return things.Period{Start: start, End: start.Add(d)}
+}
@@ -0,0 +1,98 @@
+package pumping
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/pumping")
+
+const pumpingStationType = "PumpingStation"
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+}
+
+func NewService(things thingSource) *Service {
+	return &Service{things: things}
+}
+
+// GetPumpingStats analyses the pump cycles of a station between from and to.
+func (s *Service) GetPumpingStats(ctx context.Context, thingID string, from, to time.Time) (Stats, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-pumping-stats")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	stats, err := s.stats(ctx, thingID, from, to)
+	return stats, err
+}
+
+// GetPumpingComparison analyses every pumping station between from and to, with the stations that
+// have the most anomalies first. A station without a history is marked as failed rather than left
+// out, so that it is not taken for a station that runs normally.
+func (s *Service) GetPumpingComparison(ctx context.Context, from, to time.Time) ([]Stats, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-pumping-comparison")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	stations, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": {pumpingStationType}})
+	if err != nil {
+		return nil, err
+	}
+
+	log := logging.GetFromContext(ctx)
+
+	result, err := things.Collect(stations, func(station things.Thing) (Stats, error) {
+		return s.stats(ctx, station.ID, from, to)
+	}, func(station things.Thing, err error) Stats {
+		log.Debug("could not fetch pumping history", "thing_id", station.ID, "err", err.Error())
+		return Stats{From: from, To: to, Failed: true}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, station := range stations {
+		result[i].ThingID = station.ID
+		result[i].Name = cmp.Or(station.Name, station.ID)
+		result[i].Tenant = station.Tenant
+	}
+
+	slices.SortStableFunc(result, func(a, b Stats) int {
+		return cmp.Or(cmp.Compare(len(b.Anomalies), len(a.Anomalies)), cmp.Compare(a.Name, b.Name))
+	})
+
+	return result, nil
+}
+
+func (s *Service) stats(ctx context.Context, thingID string, from, to time.Time) (Stats, error) {
+	baselineFrom := from.Add(-BaselinePeriod)
+
+	thing, err := things.GetOnOffHistory(ctx, s.things, thingID, baselineFrom, to)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	values := slices.Concat(thing.Values...)
+	before := slices.DeleteFunc(slices.Clone(values), func(m things.Measurement) bool { return !m.Timestamp.Before(from) })
+	since := slices.DeleteFunc(slices.Clone(values), func(m things.Measurement) bool { return m.Timestamp.Before(from) })
+
+	stats := Analyze(things.OnPeriods(since, from, to), things.OnPeriods(before, baselineFrom, from), baselineFrom, from, to)
+	stats.ThingID = thingID
+	stats.Name = cmp.Or(thing.Name, thingID)
+	stats.Tenant = thing.Tenant
+
+	return stats, nil
+}
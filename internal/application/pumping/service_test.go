@@ -0,0 +1,58 @@
+package pumping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestGetPumpingStatsSeparatesBaselineFromPeriod(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(thingstest.Things{ByID: thingstest.History(map[string][]things.Measurement{
+		"ps-1": {
+			state(monday.Add(-2*time.Hour), true),
+			state(monday.Add(-time.Hour), false),
+			state(monday.Add(time.Hour), true),
+			state(monday.Add(2*time.Hour), false),
+		},
+	})})
+
+	stats, err := svc.GetPumpingStats(context.Background(), "ps-1", monday, monday.AddDate(0, 0, 1))
+	is.NoErr(err)
+	is.Equal("ps-1", stats.ThingID)
+	is.Equal(1, stats.Starts)
+	is.Equal(time.Hour, stats.Runtime)
+}
+
+func TestGetPumpingComparisonListsStationsWithAnomaliesFirst(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(thingstest.Things{
+		All: []things.Thing{
+			{ID: "a", Name: "A", Type: pumpingStationType},
+			{ID: "b", Name: "B", Type: pumpingStationType},
+			{ID: "c", Name: "C", Type: pumpingStationType},
+		},
+		ByID: thingstest.History(map[string][]things.Measurement{
+			"a": {state(monday.Add(time.Hour), true), state(monday.Add(2*time.Hour), false)},
+			"b": {state(monday.Add(time.Hour), true), state(monday.Add(5*time.Hour), false)},
+		}),
+	})
+
+	stations, err := svc.GetPumpingComparison(context.Background(), monday, monday.AddDate(0, 0, 1))
+	is.NoErr(err)
+	is.Equal(3, len(stations))
+	is.Equal("b", stations[0].ThingID)
+	is.Equal(AnomalyContinuousRunning, stations[0].Anomalies[0].Kind)
+	is.Equal("a", stations[1].ThingID)
+	is.True(stations[2].Failed)
+}
+
+func state(at time.Time, on bool) things.Measurement {
+	return things.Measurement{Timestamp: at, BoolValue: &on}
+}
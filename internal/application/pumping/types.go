@@ -0,0 +1,70 @@
+package pumping
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultDays is how many days, including today, the statistics of a station cover unless another period is asked for.
+const DefaultDays = 30
+
+type Management interface {
+	GetPumpingStats(ctx context.Context, thingID string, from, to time.Time) (Stats, error)
+	GetPumpingComparison(ctx context.Context, from, to time.Time) ([]Stats, error)
+}
+
+// Bucket holds the pump starts and the runtime of a pumping station during a day or a week.
+type Bucket struct {
+	Start   time.Time
+	Starts  int
+	Runtime time.Duration
+}
+
+type AnomalyKind string
+
+const (
+	// AnomalyContinuousRunning flags a pump cycle that is much longer than the usual cycles of the station.
+	AnomalyContinuousRunning AnomalyKind = "continuousrunning"
+	// AnomalyFrequentStarts flags a day with unusually many pump starts for the station.
+	AnomalyFrequentStarts AnomalyKind = "frequentstarts"
+)
+
+type Anomaly struct {
+	Kind AnomalyKind
+	At   time.Time
+	// Value is the length of the cycle or the number of starts that was flagged, and Baseline the
+	// usual value for the station that it is compared with.
+	Value    float64
+	Baseline float64
+}
+
+// Stats describe how a pumping station has been running between From and To, compared with a
+// baseline of the weeks before.
+type Stats struct {
+	ThingID string
+	Name    string
+	Tenant  string
+	From    time.Time
+	To      time.Time
+
+	Starts       int
+	Runtime      time.Duration
+	AverageCycle time.Duration
+	LongestCycle time.Duration
+	Running      bool
+
+	Days      []Bucket
+	Weeks     []Bucket
+	Anomalies []Anomaly
+
+	// Failed is set when the history of the station could not be fetched.
+	Failed bool
+}
+
+// AverageStartsPerDay is the mean number of pump starts per day during the period.
+func (s Stats) AverageStartsPerDay() float64 {
+	if len(s.Days) == 0 {
+		return 0
+	}
+	return float64(s.Starts) / float64(len(s.Days))
+}
@@ -0,0 +1,196 @@
+package quality
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/measurements"
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+const (
+	// gapTolerance is how many times the interval of the sensor may pass without a value before it is
+	// a gap, so that a value that is only a little late is not reported
+	gapTolerance = 1.5
+	// gapFactor is how many times the typical time between the values may pass without a value
+	// before it is a gap, when the interval of the sensor is not known
+	gapFactor = 2
+	// minSamples is the number of values needed to tell the typical interval and spread of a series
+	minSamples = 10
+
+	stuckCount    = 12
+	stuckDuration = 12 * time.Hour
+
+	// outlierScore is the modified z-score, by the median and the median absolute deviation, that a
+	// value must be beyond to be an outlier
+	outlierScore = 5.0
+
+	// maxIssues keeps the latest issues of a series, so that a broken sensor does not fill the report
+	maxIssues = 50
+)
+
+// Analyse finds the gaps, stuck values and outliers of a series. A gap is a time without values
+// longer than one and a half interval, the longest that the sensor should be silent, and when
+// interval is zero twice the typical time between the values is used instead. The time from the last value to end
+// is a gap too if it is long enough.
+func Analyse(points []Point, interval time.Duration, end time.Time) []Issue {
+	if len(points) == 0 {
+		return nil
+	}
+
+	sorted := slices.SortedFunc(slices.Values(points), func(a, b Point) int { return a.Time.Compare(b.Time) })
+
+	issues := slices.Concat(gaps(sorted, interval, end), stuck(sorted), outliers(sorted))
+	slices.SortStableFunc(issues, func(a, b Issue) int { return a.From.Compare(b.From) })
+
+	if len(issues) > maxIssues {
+		issues = issues[len(issues)-maxIssues:]
+	}
+
+	return issues
+}
+
+func gaps(points []Point, interval time.Duration, end time.Time) []Issue {
+	limit := time.Duration(float64(interval) * gapTolerance)
+	if limit <= 0 {
+		limit = gapFactor * typicalInterval(points)
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	issues := []Issue{}
+	gap := func(from, to time.Time) {
+		if to.Sub(from) > limit {
+			issues = append(issues, Issue{Kind: KindGap, From: from, To: to})
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		gap(points[i-1].Time, points[i].Time)
+	}
+	if last := points[len(points)-1].Time; end.After(last) {
+		gap(last, end)
+	}
+
+	return issues
+}
+
+// typicalInterval is the median time between the values, or zero if there are too few values to tell.
+func typicalInterval(points []Point) time.Duration {
+	if len(points) < minSamples {
+		return 0
+	}
+
+	spacings := make([]float64, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		if d := points[i].Time.Sub(points[i-1].Time); d > 0 {
+			spacings = append(spacings, float64(d))
+		}
+	}
+	if len(spacings) == 0 {
+		return 0
+	}
+
+	return time.Duration(median(spacings))
+}
+
+func stuck(points []Point) []Issue {
+	issues := []Issue{}
+
+	start := 0
+	for i := 1; i <= len(points); i++ {
+		if i < len(points) && points[i].Value == points[start].Value {
+			continue
+		}
+
+		run := points[start:i]
+		from, to := run[0].Time, run[len(run)-1].Time
+		if len(run) >= stuckCount && to.Sub(from) >= stuckDuration {
+			value := run[0].Value
+			issues = append(issues, Issue{Kind: KindStuck, From: from, To: to, Value: &value, Count: len(run)})
+		}
+
+		start = i
+	}
+
+	return issues
+}
+
+// outliers uses the median and the median absolute deviation rather than the mean and the standard
+// deviation, so that the outliers themselves do not hide how far off they are.
+func outliers(points []Point) []Issue {
+	if len(points) < minSamples {
+		return nil
+	}
+
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	m := median(values)
+
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+
+	// the deviations are scaled to be comparable with a standard deviation, and when more than half
+	// of the values are the same the mean absolute deviation is used instead
+	spread := median(deviations) / 0.6745
+	if spread == 0 {
+		sum := 0.0
+		for _, d := range deviations {
+			sum += d
+		}
+		spread = 1.2533 * sum / float64(len(deviations))
+	}
+	if spread == 0 {
+		return nil
+	}
+
+	issues := []Issue{}
+	for i, p := range points {
+		if deviations[i]/spread > outlierScore {
+			value := p.Value
+			issues = append(issues, Issue{Kind: KindOutlier, From: p.Time, To: p.Time, Value: &value})
+		}
+	}
+
+	return issues
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Sorted(slices.Values(values))
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// MeasurementPoints returns the numeric values of a measurement series.
+func MeasurementPoints(values []measurements.Value) []Point {
+	points := make([]Point, 0, len(values))
+	for _, v := range values {
+		if v.Value != nil {
+			points = append(points, Point{Time: v.Timestamp, Value: *v.Value})
+		}
+	}
+	return points
+}
+
+// ThingPoints returns the numeric values and counts of the measurements of a thing.
+func ThingPoints(values []things.Measurement) []Point {
+	points := make([]Point, 0, len(values))
+	for _, v := range values {
+		switch {
+		case v.Value != nil:
+			points = append(points, Point{Time: v.Timestamp, Value: *v.Value})
+		case v.Count != nil:
+			points = append(points, Point{Time: v.Timestamp, Value: *v.Count})
+		}
+	}
+	return points
+}
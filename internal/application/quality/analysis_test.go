@@ -0,0 +1,115 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+var start = time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+
+// hourly returns a value every hour from start, with value(i) as the i:th value.
+func hourly(n int, value func(i int) float64) []Point {
+	points := make([]Point, n)
+	for i := range n {
+		points[i] = Point{Time: start.Add(time.Duration(i) * time.Hour), Value: value(i)}
+	}
+	return points
+}
+
+func wavy(i int) float64 {
+	return 20 + float64(i%5)
+}
+
+func TestAnalyseFindsNothingInAHealthySeries(t *testing.T) {
+	is := is.New(t)
+
+	points := hourly(48, wavy)
+	is.Equal(0, len(Analyse(points, time.Hour, points[47].Time)))
+	is.Equal(0, len(Analyse(points, 0, points[47].Time.Add(90*time.Minute))))
+}
+
+func TestAnalyseFindsGaps(t *testing.T) {
+	is := is.New(t)
+
+	points := hourly(48, wavy)
+	points = append(points[:10], points[15:]...)
+	end := points[len(points)-1].Time.Add(5 * time.Hour)
+
+	issues := Analyse(points, time.Hour, end)
+	is.Equal(2, len(issues))
+	is.Equal(Issue{Kind: KindGap, From: start.Add(9 * time.Hour), To: start.Add(15 * time.Hour)}, issues[0])
+	is.Equal(Issue{Kind: KindGap, From: start.Add(47 * time.Hour), To: end}, issues[1])
+
+	// without an interval the typical time between the values is used
+	issues = Analyse(points, 0, end)
+	is.Equal(2, len(issues))
+
+	// a sensor that may be silent for six hours has no gaps
+	is.Equal(0, len(Analyse(points, 6*time.Hour, end)))
+}
+
+func TestAnalyseToleratesValuesThatAreALittleLate(t *testing.T) {
+	is := is.New(t)
+
+	points := hourly(10, wavy)
+	last := points[9].Time
+
+	is.Equal(0, len(Analyse(points, time.Hour, last.Add(90*time.Minute))))
+
+	issues := Analyse(points, time.Hour, last.Add(91*time.Minute))
+	is.Equal(1, len(issues))
+	is.Equal(Issue{Kind: KindGap, From: last, To: last.Add(91 * time.Minute)}, issues[0])
+}
+
+func TestAnalyseFindsStuckValues(t *testing.T) {
+	is := is.New(t)
+
+	points := hourly(48, func(i int) float64 {
+		if i >= 20 && i < 40 {
+			return 7
+		}
+		return wavy(i)
+	})
+
+	issues := Analyse(points, time.Hour, points[47].Time)
+	is.Equal(1, len(issues))
+	is.Equal(KindStuck, issues[0].Kind)
+	is.Equal(start.Add(20*time.Hour), issues[0].From)
+	is.Equal(start.Add(39*time.Hour), issues[0].To)
+	is.Equal(20, issues[0].Count)
+	is.Equal(7.0, *issues[0].Value)
+
+	// twelve values within a few minutes are not stuck
+	fast := make([]Point, 20)
+	for i := range fast {
+		fast[i] = Point{Time: start.Add(time.Duration(i) * time.Minute), Value: 1}
+	}
+	is.Equal(0, len(Analyse(fast, time.Minute, fast[19].Time)))
+}
+
+func TestAnalyseFindsOutliers(t *testing.T) {
+	is := is.New(t)
+
+	points := hourly(48, wavy)
+	points[30].Value = 400
+
+	issues := Analyse(points, time.Hour, points[47].Time)
+	is.Equal(1, len(issues))
+	is.Equal(Issue{Kind: KindOutlier, From: points[30].Time, To: points[30].Time, Value: new(400.0)}, issues[0])
+
+	// a spike in an otherwise flat series is found too
+	flat := hourly(11, func(i int) float64 {
+		if i == 5 {
+			return 3
+		}
+		return 1
+	})
+	issues = Analyse(flat, time.Hour, flat[10].Time)
+	is.Equal(1, len(issues))
+	is.Equal(KindOutlier, issues[0].Kind)
+
+	// too few values to tell
+	is.Equal(0, len(Analyse(flat[:6], time.Hour, flat[5].Time)))
+}
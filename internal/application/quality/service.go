@@ -0,0 +1,230 @@
+package quality
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/measurements"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/quality")
+
+const (
+	findingsCollection = "dataquality"
+
+	// checkWindow is how far back the measurements of each device are checked
+	checkWindow = 7 * 24 * time.Hour
+	maxValues   = 5000
+)
+
+type Service struct {
+	devices      devices.Finder
+	measurements measurements.Management
+	store        storage.Store
+}
+
+func NewService(devices devices.Finder, measurements measurements.Management, store storage.Store) *Service {
+	return &Service{devices: devices, measurements: measurements, store: store}
+}
+
+// GetDataQualityReport returns the findings of the last check for the devices of tenant, or of
+// every tenant in tenants if tenant is empty. The findings are kept by diwise-web rather than by the
+// backend, so tenants are the tenants of the user that the report may show.
+func (s *Service) GetDataQualityReport(ctx context.Context, tenant string, tenants []string) (Report, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-data-quality-report")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	findings, err := s.findings(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if tenant != "" {
+		tenants = []string{tenant}
+	}
+
+	report := Report{Tenant: tenant, Findings: []Finding{}}
+	for _, f := range findings {
+		if !slices.Contains(tenants, f.Tenant) {
+			continue
+		}
+		report.Findings = append(report.Findings, f)
+		if f.CheckedAt.After(report.CheckedAt) {
+			report.CheckedAt = f.CheckedAt
+		}
+	}
+
+	slices.SortFunc(report.Findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(b.Count(""), a.Count("")),
+			cmp.Compare(strings.ToLower(cmp.Or(a.Name, a.DeviceID)), strings.ToLower(cmp.Or(b.Name, b.DeviceID))),
+		)
+	})
+
+	return report, nil
+}
+
+// GetSuspectDevices returns the IDs of the devices that the last check found issues in.
+func (s *Service) GetSuspectDevices(ctx context.Context) ([]string, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-suspect-devices")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	findings, err := s.findings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.DeviceID)
+	}
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+// CheckDataQuality analyses the latest week of measurements of every active device and replaces
+// the findings of the last check. A device that cannot be checked keeps its earlier finding.
+func (s *Service) CheckDataQuality(ctx context.Context) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "check-data-quality")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	all, err := devices.GetAllDevices(ctx, s.devices, map[string][]string{})
+	if err != nil {
+		return err
+	}
+	all = slices.DeleteFunc(all, func(d devices.Device) bool { return !d.Active })
+
+	stored, err := s.findings(ctx)
+	if err != nil {
+		return err
+	}
+
+	log := logging.GetFromContext(ctx)
+	now := time.Now().UTC()
+
+	findings := make([]Finding, len(all))
+	checked := make([]bool, len(all))
+
+	things.ForEach(all, func(i int, d devices.Device) {
+		f, err := s.check(ctx, d, now)
+		if err != nil {
+			log.Debug("could not check the data quality of device", "device_id", d.DeviceID, "err", err.Error())
+			return
+		}
+		findings[i], checked[i] = f, true
+	})
+
+	for i, f := range findings {
+		switch {
+		case !checked[i]:
+			continue
+		case len(f.Series) > 0:
+			var document []byte
+			document, err = json.Marshal(f)
+			if err != nil {
+				return err
+			}
+			if err = s.store.Put(ctx, findingsCollection, f.DeviceID, document); err != nil {
+				return err
+			}
+		default:
+			if err = s.delete(ctx, all[i].DeviceID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the findings of devices that have been removed or deactivated since are dropped
+	for _, f := range stored {
+		if slices.ContainsFunc(all, func(d devices.Device) bool { return d.DeviceID == f.DeviceID }) {
+			continue
+		}
+		if err = s.delete(ctx, f.DeviceID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// check analyses the newest numeric measurements of a device within the check window. The interval of
+// its profile, or else of the device, is in seconds.
+func (s *Service) check(ctx context.Context, d devices.Device, now time.Time) (Finding, error) {
+	latest, err := s.measurements.GetMeasurementInfo(ctx, d.DeviceID)
+	if err != nil {
+		return Finding{}, err
+	}
+
+	interval := d.Interval
+	if d.SensorProfile != nil && d.SensorProfile.Interval > 0 {
+		interval = d.SensorProfile.Interval
+	}
+
+	finding := Finding{DeviceID: d.DeviceID, Name: d.Name, Tenant: d.Tenant, Series: []Series{}, CheckedAt: now}
+	for _, v := range latest {
+		if v.ID == nil || v.Value == nil {
+			continue
+		}
+
+		data, err := s.measurements.GetMeasurementData(ctx, *v.ID,
+			client.WithLastN(true),
+			client.WithTimeRel("between", now.Add(-checkWindow), now),
+			client.WithLimit(maxValues),
+			client.WithReverse(true),
+		)
+		if err != nil {
+			return Finding{}, err
+		}
+
+		issues := Analyse(MeasurementPoints(data.Values), time.Duration(interval)*time.Second, now)
+		if len(issues) > 0 {
+			// a series with more values than are fetched is only checked from its oldest fetched value
+			partial := len(data.Values) >= maxValues
+			finding.Series = append(finding.Series, Series{ID: *v.ID, Issues: issues, Partial: partial})
+		}
+	}
+
+	return finding, nil
+}
+
+func (s *Service) findings(ctx context.Context) ([]Finding, error) {
+	documents, err := s.store.List(ctx, findingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	for _, document := range documents {
+		var f Finding
+		if err := json.Unmarshal(document, &f); err != nil {
+			return nil, err
+		}
+		findings = append(findings, f)
+	}
+
+	return findings, nil
+}
+
+func (s *Service) delete(ctx context.Context, deviceID string) error {
+	err := s.store.Delete(ctx, findingsCollection, deviceID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package quality
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/measurements"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/matryer/is"
+)
+
+func TestCheckDataQualityStoresAndClearsFindings(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	source := &testSource{
+		devices: []devices.Device{
+			{DeviceID: "dev-1", Name: "Sensor 1", Tenant: "default", Active: true, Interval: 3600},
+			{DeviceID: "dev-2", Tenant: "other", Active: true, Interval: 3600},
+			{DeviceID: "dev-3", Tenant: "default", Active: false},
+		},
+		values: map[string][]measurements.Value{},
+	}
+	// the first device stopped reporting a day ago, the second is fine
+	now := time.Now().UTC()
+	for i := range 48 {
+		at := now.Add(-time.Duration(72-i) * time.Hour)
+		source.values["dev-1/3303/5700"] = append(source.values["dev-1/3303/5700"], measurements.Value{Timestamp: at, Value: new(float64(20 + i%3))})
+	}
+	for i := range 48 {
+		at := now.Add(-time.Duration(48-i)*time.Hour + 30*time.Minute)
+		source.values["dev-2/3303/5700"] = append(source.values["dev-2/3303/5700"], measurements.Value{Timestamp: at, Value: new(float64(20 + i%3))})
+	}
+
+	svc := newTestService(t, source)
+	is.NoErr(svc.CheckDataQuality(ctx))
+
+	suspect, err := svc.GetSuspectDevices(ctx)
+	is.NoErr(err)
+	is.Equal([]string{"dev-1"}, suspect)
+
+	report, err := svc.GetDataQualityReport(ctx, "default", []string{"default", "other"})
+	is.NoErr(err)
+	is.Equal(1, len(report.Findings))
+	is.Equal("Sensor 1", report.Findings[0].Name)
+	is.Equal("dev-1/3303/5700", report.Findings[0].Series[0].ID)
+	is.Equal(1, report.Findings[0].Count(KindGap))
+	is.True(!report.CheckedAt.IsZero())
+
+	report, _ = svc.GetDataQualityReport(ctx, "other", []string{"default", "other"})
+	is.Equal(0, len(report.Findings))
+
+	// without a tenant only the findings of the tenants of the user are shown
+	report, _ = svc.GetDataQualityReport(ctx, "", []string{"default"})
+	is.Equal(1, len(report.Findings))
+	report, _ = svc.GetDataQualityReport(ctx, "", []string{"other"})
+	is.Equal(0, len(report.Findings))
+
+	// the finding is dropped once the device is removed
+	source.devices = source.devices[1:]
+	is.NoErr(svc.CheckDataQuality(ctx))
+	suspect, _ = svc.GetSuspectDevices(ctx)
+	is.Equal(0, len(suspect))
+}
+
+func TestCheckDataQualityChecksTheNewestValues(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	source := &testSource{
+		devices: []devices.Device{{DeviceID: "dev-1", Tenant: "default", Active: true, Interval: 60}},
+		values:  map[string][]measurements.Value{},
+	}
+	// there are more values in the week than are fetched, and the newest is an outlier
+	now := time.Now().UTC()
+	n := maxValues + 1000
+	for i := range n {
+		value := float64(20 + i%3)
+		if i == n-1 {
+			value = 400
+		}
+		at := now.Add(-time.Duration(n-i) * time.Minute)
+		source.values["dev-1/3303/5700"] = append(source.values["dev-1/3303/5700"], measurements.Value{Timestamp: at, Value: new(value)})
+	}
+
+	svc := newTestService(t, source)
+	is.NoErr(svc.CheckDataQuality(ctx))
+
+	report, err := svc.GetDataQualityReport(ctx, "", []string{"default"})
+	is.NoErr(err)
+	is.Equal(1, len(report.Findings))
+
+	series := report.Findings[0].Series[0]
+	is.True(series.Partial)
+	is.Equal(0, report.Findings[0].Count(KindGap))
+	is.Equal(1, report.Findings[0].Count(KindOutlier))
+}
+
+func newTestService(t *testing.T, source *testSource) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(source, source, store)
+}
+
+type testSource struct {
+	devices []devices.Device
+	values  map[string][]measurements.Value
+}
+
+func (s *testSource) GetDevices(_ context.Context, offset, limit int, _ map[string][]string) (devices.DeviceResult, error) {
+	page := s.devices[min(offset, len(s.devices)):min(offset+limit, len(s.devices))]
+	return devices.DeviceResult{Devices: page, TotalRecords: len(s.devices), Count: len(page), Offset: offset, Limit: limit}, nil
+}
+
+func (s *testSource) GetMeasurementInfo(_ context.Context, deviceID string) ([]measurements.Value, error) {
+	latest := []measurements.Value{}
+	for id, values := range s.values {
+		if strings.HasPrefix(id, deviceID+"/") {
+			latest = append(latest, measurements.Value{ID: new(id), Value: values[len(values)-1].Value})
+		}
+	}
+	return latest, nil
+}
+
+// GetMeasurementData returns the oldest values up to the limit, or the newest if lastN is set.
+func (s *testSource) GetMeasurementData(_ context.Context, id string, params ...client.InputParam) (measurements.Data, error) {
+	query := url.Values{}
+	for _, p := range params {
+		p(&query)
+	}
+
+	values := slices.Clone(s.values[id])
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && len(values) > limit {
+		if query.Get("lastN") == "true" {
+			values = values[len(values)-limit:]
+		} else {
+			values = values[:limit]
+		}
+	}
+	if query.Get("reverse") == "true" {
+		slices.Reverse(values)
+	}
+
+	return measurements.Data{Values: values}, nil
+}
@@ -0,0 +1,76 @@
+package quality
+
+import (
+	"context"
+	"time"
+)
+
+type Management interface {
+	GetDataQualityReport(ctx context.Context, tenant string, tenants []string) (Report, error)
+	GetSuspectDevices(ctx context.Context) ([]string, error)
+}
+
+// Kinds of issues that the analysis finds in a series of measurements.
+const (
+	// KindGap is a longer time than expected without any values.
+	KindGap = "gap"
+	// KindStuck is a long run of the same value, as from a sensor that has stopped measuring.
+	KindStuck = "stuck"
+	// KindOutlier is a value far from the other values of the series.
+	KindOutlier = "outlier"
+)
+
+var Kinds = []string{KindGap, KindStuck, KindOutlier}
+
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Issue is a suspect part of a series. Gaps and stuck values span From to To, outliers are a
+// single value at From. Count is the number of repeated values of a stuck value.
+type Issue struct {
+	Kind  string    `json:"kind"`
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Value *float64  `json:"value,omitzero"`
+	Count int       `json:"count,omitzero"`
+}
+
+// Series are the issues found in the values of one measurement of a device. Partial is set when the
+// series had more values in the check window than could be fetched, and only the newest were checked.
+type Series struct {
+	ID      string  `json:"id"`
+	Issues  []Issue `json:"issues"`
+	Partial bool    `json:"partial,omitzero"`
+}
+
+// Finding is what the last check found in the measurements of a device. Only devices with
+// issues have a finding.
+type Finding struct {
+	DeviceID  string    `json:"deviceID"`
+	Name      string    `json:"name,omitzero"`
+	Tenant    string    `json:"tenant"`
+	Series    []Series  `json:"series"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Count returns the number of issues of kind, or of every kind if kind is empty.
+func (f Finding) Count(kind string) int {
+	n := 0
+	for _, s := range f.Series {
+		for _, i := range s.Issues {
+			if kind == "" || i.Kind == kind {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Report lists the devices of a tenant with suspect data, the most issues first.
+type Report struct {
+	Tenant    string
+	CheckedAt time.Time
+	Findings  []Finding
+}
@@ -0,0 +1,130 @@
+package readiness
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// beachRadius is how close to a beach, in meters, a lifebuoy has to be to belong to it
+const beachRadius = 1000.0
+
+// MissingSince finds when the presence of a lifebuoy last went from present to missing. When it has
+// been missing during the whole history the first missing value is used.
+func MissingSince(values []things.Measurement) *time.Time {
+	values = slices.SortedFunc(slices.Values(values), func(a, b things.Measurement) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	var since *time.Time
+	for _, m := range values {
+		if m.BoolValue == nil {
+			continue
+		}
+		if *m.BoolValue {
+			since = nil
+		} else if since == nil {
+			since = new(m.Timestamp)
+		}
+	}
+	return since
+}
+
+// Trend is the change of the temperature between the first and the last value of a series, or nil
+// when the series is too short to tell.
+func Trend(values []things.Measurement, minSpan time.Duration) *float64 {
+	values = slices.DeleteFunc(slices.Clone(values), func(m things.Measurement) bool { return m.Value == nil })
+	if len(values) < 2 {
+		return nil
+	}
+
+	slices.SortFunc(values, func(a, b things.Measurement) int { return a.Timestamp.Compare(b.Timestamp) })
+	first, last := values[0], values[len(values)-1]
+	if last.Timestamp.Sub(first.Timestamp) < minSpan {
+		return nil
+	}
+	return new(*last.Value - *first.Value)
+}
+
+// Arrange puts every lifebuoy in the group of the closest beach within beachRadius, or else in a
+// group for its first tag. Lifebuoys without a position are only grouped by tag. The groups missing
+// the most lifebuoys come first, and the missing lifebuoys first within each group.
+func Arrange(lifebuoys []Lifebuoy, beaches []Beach) []Group {
+	groups := map[string]*Group{}
+	group := func(key, name, beachID string) *Group {
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{Name: name, BeachID: beachID}
+			groups[key] = g
+		}
+		return g
+	}
+
+	for _, l := range lifebuoys {
+		if beach, ok := closestBeach(l, beaches); ok {
+			g := group("beach:"+beach.ThingID, beach.Name, beach.ThingID)
+			g.Lifebuoys = append(g.Lifebuoys, l)
+			continue
+		}
+
+		tag := ""
+		if len(l.Tags) > 0 {
+			tag = slices.Min(l.Tags)
+		}
+		g := group("tag:"+tag, tag, "")
+		g.Lifebuoys = append(g.Lifebuoys, l)
+	}
+
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		slices.SortFunc(g.Lifebuoys, func(a, b Lifebuoy) int {
+			return cmp.Or(compareMissing(a, b), cmp.Compare(a.Name, b.Name))
+		})
+		result = append(result, *g)
+	}
+
+	slices.SortFunc(result, func(a, b Group) int {
+		// groups by tag, and the lifebuoys without tags last of all, come after the beaches
+		return cmp.Or(
+			cmp.Compare(b.Missing(), a.Missing()),
+			cmp.Compare(rank(a.BeachID == ""), rank(b.BeachID == "")),
+			cmp.Compare(rank(a.Name == ""), rank(b.Name == "")),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return result
+}
+
+func closestBeach(l Lifebuoy, beaches []Beach) (Beach, bool) {
+	if l.Latitude == 0 || l.Longitude == 0 {
+		return Beach{}, false
+	}
+
+	var closest Beach
+	shortest := beachRadius
+	found := false
+	for _, b := range beaches {
+		if b.Latitude == 0 || b.Longitude == 0 {
+			continue
+		}
+		d := geo.Distance(geo.Position{Latitude: l.Latitude, Longitude: l.Longitude}, geo.Position{Latitude: b.Latitude, Longitude: b.Longitude})
+		if d <= shortest {
+			closest, shortest, found = b, d, true
+		}
+	}
+	return closest, found
+}
+
+func compareMissing(a, b Lifebuoy) int {
+	return cmp.Compare(rank(!a.Missing()), rank(!b.Missing()))
+}
+
+// rank sorts what is true after what is false.
+func rank(last bool) int {
+	if last {
+		return 1
+	}
+	return 0
+}
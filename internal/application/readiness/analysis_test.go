@@ -0,0 +1,69 @@
+package readiness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+var now = time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+
+func TestMissingSinceFindsTheLastTimeTheLifebuoyWasTaken(t *testing.T) {
+	is := is.New(t)
+
+	present, missing := true, false
+	since := MissingSince([]things.Measurement{
+		{Timestamp: now.Add(-5 * time.Hour), BoolValue: &missing},
+		{Timestamp: now.Add(-2 * time.Hour), BoolValue: &missing},
+		{Timestamp: now.Add(-4 * time.Hour), BoolValue: &present},
+	})
+	is.True(since != nil)
+	is.Equal(now.Add(-2*time.Hour), *since)
+
+	is.Equal(nil, MissingSince([]things.Measurement{{Timestamp: now, BoolValue: &present}}))
+}
+
+func TestTrendNeedsLongEnoughSeries(t *testing.T) {
+	is := is.New(t)
+
+	cold, warm := 14.5, 16.0
+	trend := Trend([]things.Measurement{
+		{Timestamp: now, Value: &warm},
+		{Timestamp: now.Add(-20 * time.Hour), Value: &cold},
+		{Timestamp: now.Add(-10 * time.Hour)},
+	}, 12*time.Hour)
+	is.True(trend != nil)
+	is.Equal(1.5, *trend)
+
+	is.Equal(nil, Trend([]things.Measurement{{Timestamp: now, Value: &warm}, {Timestamp: now.Add(-time.Hour), Value: &cold}}, 12*time.Hour))
+}
+
+func TestArrangeGroupsLifebuoysByClosestBeachOrTag(t *testing.T) {
+	is := is.New(t)
+
+	present, missing := true, false
+	beaches := []Beach{
+		{ThingID: "north", Name: "North beach", Latitude: 62.40, Longitude: 17.30},
+		{ThingID: "south", Name: "South beach", Latitude: 62.30, Longitude: 17.30},
+	}
+	lifebuoys := []Lifebuoy{
+		// about 110 m from the north beach
+		{ThingID: "a", Name: "A", Latitude: 62.401, Longitude: 17.30, Present: &present},
+		{ThingID: "b", Name: "B", Latitude: 62.399, Longitude: 17.30, Present: &missing},
+		{ThingID: "c", Name: "C", Latitude: 62.35, Longitude: 17.30, Tags: []string{"harbour", "city"}, Present: &present},
+		{ThingID: "d", Name: "D"},
+	}
+
+	groups := Arrange(lifebuoys, beaches)
+
+	is.Equal(3, len(groups))
+	is.Equal("north", groups[0].BeachID)
+	is.Equal(1, groups[0].Missing())
+	is.Equal("b", groups[0].Lifebuoys[0].ThingID)
+	is.Equal("city", groups[1].Name)
+	is.Equal("", groups[1].BeachID)
+	is.Equal("", groups[2].Name)
+	is.Equal("d", groups[2].Lifebuoys[0].ThingID)
+}
@@ -0,0 +1,143 @@
+package readiness
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/readiness")
+
+const (
+	lifebuoyType        = "Lifebuoy"
+	pointOfInterestType = "PointOfInterest"
+	beachSubType        = "Beach"
+
+	presenceURN    = "3302/5500"
+	temperatureURN = "3303/5700"
+
+	// missingLookback is how far back the history of a missing lifebuoy is searched for when it went missing
+	missingLookback = 90 * 24 * time.Hour
+	// trendWindow is the period that the temperature trend of a beach is measured over
+	trendWindow = 24 * time.Hour
+)
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+}
+
+func NewService(things thingSource) *Service {
+	return &Service{things: things}
+}
+
+// GetReadinessBoard collects every lifebuoy and beach. The history is only fetched to tell since
+// when the missing lifebuoys have been missing and how the water temperature of the beaches has
+// changed, and the board is shown without those details if it cannot be fetched.
+func (s *Service) GetReadinessBoard(ctx context.Context) (Board, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-readiness-board")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	buoys, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": {lifebuoyType}})
+	if err != nil {
+		return Board{}, err
+	}
+
+	pois, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": {pointOfInterestType}})
+	if err != nil {
+		return Board{}, err
+	}
+	pois = slices.DeleteFunc(pois, func(t things.Thing) bool { return !strings.EqualFold(t.SubType, beachSubType) })
+
+	log := logging.GetFromContext(ctx)
+	now := time.Now()
+
+	lifebuoys := make([]Lifebuoy, len(buoys))
+	things.ForEach(buoys, func(i int, t things.Thing) {
+		l := toLifebuoy(t)
+		if l.Missing() {
+			history, err := s.history(ctx, t.ID, presenceURN, now.Add(-missingLookback), now)
+			if err != nil {
+				log.Debug("could not fetch lifebuoy history", "thing_id", t.ID, "err", err.Error())
+			} else {
+				l.MissingSince = MissingSince(history)
+			}
+		}
+		lifebuoys[i] = l
+	})
+
+	beaches := make([]Beach, len(pois))
+	things.ForEach(pois, func(i int, t things.Thing) {
+		b := toBeach(t)
+		history, err := s.history(ctx, t.ID, temperatureURN, now.Add(-trendWindow), now)
+		if err != nil {
+			log.Debug("could not fetch beach temperatures", "thing_id", t.ID, "err", err.Error())
+		} else {
+			b.Trend = Trend(history, trendWindow/2)
+		}
+		beaches[i] = b
+	})
+
+	slices.SortFunc(beaches, func(a, b Beach) int { return cmp.Compare(a.Name, b.Name) })
+
+	return Board{Groups: Arrange(lifebuoys, beaches), Beaches: beaches}, nil
+}
+
+func (s *Service) history(ctx context.Context, thingID, urn string, from, to time.Time) ([]things.Measurement, error) {
+	query := url.Values{}
+	query.Add("timerel", "between")
+	query.Add("timeat", from.UTC().Format(time.RFC3339))
+	query.Add("endTimeAt", to.UTC().Format(time.RFC3339))
+	query.Add("n", urn)
+	if urn == presenceURN {
+		// only the changes of the presence are needed to find when it went missing
+		query.Add("distinct", "vb")
+	}
+
+	thing, err := s.things.GetThing(ctx, thingID, query)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(thing.Values...), nil
+}
+
+func toLifebuoy(t things.Thing) Lifebuoy {
+	return Lifebuoy{
+		ThingID:    t.ID,
+		Name:       cmp.Or(t.Name, t.ID),
+		Tags:       t.Tags,
+		Latitude:   t.Location.Latitude,
+		Longitude:  t.Location.Longitude,
+		Present:    t.TypeValues.Presence,
+		ObservedAt: t.ObservedAt,
+	}
+}
+
+func toBeach(t things.Thing) Beach {
+	b := Beach{
+		ThingID:    t.ID,
+		Name:       cmp.Or(t.Name, t.ID),
+		Tags:       t.Tags,
+		Latitude:   t.Location.Latitude,
+		Longitude:  t.Location.Longitude,
+		ObservedAt: t.ObservedAt,
+	}
+	if temp := t.TypeValues.Temperature; temp != nil && temp.Value != nil {
+		b.Temperature = new(*temp.Value)
+		b.ObservedAt = temp.Timestamp
+	}
+	return b
+}
@@ -0,0 +1,35 @@
+package readiness
+
+import (
+	"context"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestGetReadinessBoardOnlyIncludesBeaches(t *testing.T) {
+	is := is.New(t)
+
+	missing, temp := false, 17.5
+	svc := NewService(thingstest.Things{
+		All: []things.Thing{
+			{ID: "buoy-1", Name: "Buoy", Type: lifebuoyType, Location: client.Location{Latitude: 62.4, Longitude: 17.3}, TypeValues: things.TypeValues{Presence: &missing}},
+			{ID: "beach-1", Name: "Beach", Type: pointOfInterestType, SubType: "Beach", Location: client.Location{Latitude: 62.4, Longitude: 17.3}, TypeValues: things.TypeValues{Temperature: &things.Measurement{Value: &temp}}},
+			{ID: "park-1", Name: "Park", Type: pointOfInterestType, SubType: "Park"},
+		},
+	})
+
+	board, err := svc.GetReadinessBoard(context.Background())
+	is.NoErr(err)
+	is.Equal(1, len(board.Beaches))
+	is.Equal(17.5, *board.Beaches[0].Temperature)
+	// the history cannot be fetched, which only leaves the details out
+	is.Equal(nil, board.Beaches[0].Trend)
+	is.Equal(1, len(board.Groups))
+	is.Equal("beach-1", board.Groups[0].BeachID)
+	is.Equal(1, board.Missing())
+	is.Equal(nil, board.Groups[0].Lifebuoys[0].MissingSince)
+}
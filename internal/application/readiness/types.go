@@ -0,0 +1,84 @@
+package readiness
+
+import (
+	"context"
+	"time"
+)
+
+type Management interface {
+	GetReadinessBoard(ctx context.Context) (Board, error)
+}
+
+// Lifebuoy is the current state of a lifebuoy. Present is nil when the lifebuoy has not reported
+// whether it is in place.
+type Lifebuoy struct {
+	ThingID    string
+	Name       string
+	Tags       []string
+	Latitude   float64
+	Longitude  float64
+	Present    *bool
+	ObservedAt time.Time
+	// MissingSince is when a missing lifebuoy was last seen leaving its place, if that is known.
+	MissingSince *time.Time
+}
+
+func (l Lifebuoy) Missing() bool {
+	return l.Present != nil && !*l.Present
+}
+
+// Beach is a bathing place with its current water temperature and how that has changed during the
+// last day. Temperature and Trend are nil when they are not known.
+type Beach struct {
+	ThingID     string
+	Name        string
+	Tags        []string
+	Latitude    float64
+	Longitude   float64
+	Temperature *float64
+	ObservedAt  time.Time
+	Trend       *float64
+}
+
+// Group holds the lifebuoys at a beach, or the ones sharing a tag when they are not close to any
+// beach. BeachID is empty for the groups by tag, and Name is empty for lifebuoys without tags.
+type Group struct {
+	Name      string
+	BeachID   string
+	Lifebuoys []Lifebuoy
+}
+
+// Missing counts the lifebuoys in the group that are not in place.
+func (g Group) Missing() int {
+	count := 0
+	for _, l := range g.Lifebuoys {
+		if l.Missing() {
+			count++
+		}
+	}
+	return count
+}
+
+// Board is the readiness of the lifebuoys and beaches, with the groups missing the most lifebuoys first.
+type Board struct {
+	Groups  []Group
+	Beaches []Beach
+}
+
+// Lifebuoys counts every lifebuoy on the board.
+func (b Board) Lifebuoys() int {
+	count := 0
+	for _, g := range b.Groups {
+		count += len(g.Lifebuoys)
+	}
+	return count
+}
+
+// Missing counts the lifebuoys on the board that are not in place.
+func (b Board) Missing() int {
+	count := 0
+	for _, g := range b.Groups {
+		count += g.Missing()
+	}
+	return count
+}
@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/sandstorage"
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// Defaults keep the tones of waste containers and sand storages that the lists have always had,
+// until a rule of their own is saved.
+var Defaults = []Rule{
+	{ID: "default-wastecontainer", Scope: ScopeSubType, Target: "WasteContainer", Property: "percent", Warning: new(30.0), Critical: new(50.0), Builtin: true},
+	{ID: "default-sandstorage", Scope: ScopeSubType, Target: "Sandstorage", Property: "percent", Warning: new(sandstorage.ReadyLevel), Critical: new(sandstorage.CriticalLevel), Below: true, Builtin: true},
+}
+
+// Select returns the rule for a property of a thing. The rule with the narrowest scope wins, and at
+// the same scope a rule for the tenant of the thing wins over a rule for every tenant and a saved
+// rule wins over a default.
+func Select(rules []Rule, s Subject, property string) (Rule, bool) {
+	var selected Rule
+	found := false
+
+	for _, r := range rules {
+		if r.Property != property || !r.Matches(s) {
+			continue
+		}
+		if !found || rank(r) > rank(selected) {
+			selected, found = r, true
+		}
+	}
+
+	return selected, found
+}
+
+func rank(r Rule) int {
+	n := slices.Index(Scopes, r.Scope) * 4
+	if r.Tenant != "" {
+		n += 2
+	}
+	if !r.Builtin {
+		n++
+	}
+	return n
+}
+
+// Tones returns the tone of every value of a thing that a rule applies to.
+func Tones(rules []Rule, s Subject, values map[string]float64) map[string]string {
+	tones := map[string]string{}
+	for property, value := range values {
+		if r, ok := Select(rules, s, property); ok {
+			tones[property] = r.Tone(value)
+		}
+	}
+	return tones
+}
+
+// SubjectOf returns what the rules are matched against for a thing.
+func SubjectOf(t things.Thing) Subject {
+	return Subject{ID: t.ID, Type: t.Type, SubType: t.SubType, Tenant: t.Tenant, Tags: t.Tags}
+}
+
+// Values returns the latest values of a thing that limits can be set for.
+func Values(t things.Thing) map[string]float64 {
+	data, _ := json.Marshal(t.TypeValues)
+	all := map[string]any{}
+	_ = json.Unmarshal(data, &all)
+
+	values := map[string]float64{}
+	for _, property := range Properties {
+		switch v := all[property].(type) {
+		case float64:
+			values[property] = v
+		case map[string]any:
+			// the temperature comes as a measurement
+			if f, ok := v["v"].(float64); ok {
+				values[property] = f
+			}
+		}
+	}
+	return values
+}
+
+// Evaluate returns an alert for every value that is at or beyond the critical limit of a rule that
+// raises alerts. Alerts that are still ongoing keep when they started.
+func Evaluate(rules []Rule, all []things.Thing, ongoing []Alert, now time.Time) []Alert {
+	since := map[string]time.Time{}
+	for _, a := range ongoing {
+		since[a.ID] = a.Since
+	}
+
+	alerts := []Alert{}
+	for _, t := range all {
+		subject := SubjectOf(t)
+		for property, value := range Values(t) {
+			r, ok := Select(rules, subject, property)
+			if !ok || !r.Alert || r.Tone(value) != ToneCritical {
+				continue
+			}
+
+			a := Alert{
+				ID:         alertID(t.ID, property),
+				RuleID:     r.ID,
+				ThingID:    t.ID,
+				Name:       t.Name,
+				Tenant:     t.Tenant,
+				Property:   property,
+				Value:      value,
+				Limit:      *r.Critical,
+				Below:      r.Below,
+				Since:      now,
+				ObservedAt: now,
+			}
+			if s, ok := since[a.ID]; ok {
+				a.Since = s
+			}
+			alerts = append(alerts, a)
+		}
+	}
+
+	slices.SortFunc(alerts, func(a, b Alert) int { return b.Since.Compare(a.Since) })
+
+	return alerts
+}
+
+func alertID(thingID, property string) string {
+	return thingID + "/" + property
+}
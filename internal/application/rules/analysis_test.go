@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+var now = time.Date(2026, 6, 15, 10, 0, 0, 0, time.UTC)
+
+func TestToneKeepsTheLimitsOfTheList(t *testing.T) {
+	is := is.New(t)
+
+	waste, sand := Defaults[0], Defaults[1]
+	is.Equal(ToneGood, waste.Tone(30))
+	is.Equal(ToneWarning, waste.Tone(31))
+	is.Equal(ToneCritical, waste.Tone(50))
+
+	is.Equal(ToneGood, sand.Tone(70))
+	is.Equal(ToneWarning, sand.Tone(69))
+	is.Equal(ToneCritical, sand.Tone(50))
+}
+
+func TestSelectPrefersTheNarrowestScope(t *testing.T) {
+	is := is.New(t)
+
+	rules := append([]Rule{
+		{ID: "type", Scope: ScopeType, Target: "Room", Property: "temperature", Warning: new(24.0)},
+		{ID: "tenant", Scope: ScopeType, Target: "Room", Tenant: "school", Property: "temperature", Warning: new(25.0)},
+		{ID: "tag", Scope: ScopeTag, Target: "server", Property: "temperature", Critical: new(30.0)},
+		{ID: "thing", Scope: ScopeThing, Target: "room-9", Property: "temperature", Critical: new(20.0)},
+		{ID: "mine", Scope: ScopeSubType, Target: "WasteContainer", Property: "percent", Critical: new(90.0)},
+	}, Defaults...)
+
+	room := Subject{ID: "room-1", Type: "Room", Tenant: "default"}
+	r, ok := Select(rules, room, "temperature")
+	is.True(ok)
+	is.Equal("type", r.ID)
+
+	room.Tenant = "school"
+	r, _ = Select(rules, room, "temperature")
+	is.Equal("tenant", r.ID)
+
+	room.Tags = []string{"server"}
+	r, _ = Select(rules, room, "temperature")
+	is.Equal("tag", r.ID)
+
+	room.ID = "room-9"
+	r, _ = Select(rules, room, "temperature")
+	is.Equal("thing", r.ID)
+
+	r, _ = Select(rules, Subject{Type: "Container", SubType: "WasteContainer"}, "percent")
+	is.Equal("mine", r.ID)
+
+	_, ok = Select(rules, room, "percent")
+	is.True(!ok)
+}
+
+func TestValidateRejectsLimitsInTheWrongOrder(t *testing.T) {
+	is := is.New(t)
+
+	r := Rule{Scope: ScopeType, Target: "Room", Property: "temperature", Warning: new(26.0), Critical: new(24.0)}
+	is.True(r.Validate() != nil)
+
+	r.Below = true
+	is.NoErr(r.Validate())
+
+	is.True(Rule{Scope: ScopeType, Target: "Room", Property: "humidity", Warning: new(1.0)}.Validate() != nil)
+	is.True(Rule{Scope: ScopeType, Target: "Room", Property: "temperature"}.Validate() != nil)
+}
+
+func TestEvaluateKeepsWhenOngoingAlertsStarted(t *testing.T) {
+	is := is.New(t)
+
+	warm, cool := 27.5, 21.0
+	rules := []Rule{{ID: "warm", Scope: ScopeType, Target: "Room", Property: "temperature", Critical: new(26.0), Alert: true}}
+	all := []things.Thing{
+		{ID: "room-1", Name: "Room 1", Type: "Room", TypeValues: things.TypeValues{Temperature: &things.Measurement{Value: &warm}}},
+		{ID: "room-2", Name: "Room 2", Type: "Room", TypeValues: things.TypeValues{Temperature: &things.Measurement{Value: &cool}}},
+	}
+
+	started := now.Add(-2 * time.Hour)
+	alerts := Evaluate(rules, all, []Alert{{ID: "room-1/temperature", Since: started}}, now)
+
+	is.Equal(1, len(alerts))
+	is.Equal("room-1", alerts[0].ThingID)
+	is.Equal(27.5, alerts[0].Value)
+	is.Equal(26.0, alerts[0].Limit)
+	is.Equal(started, alerts[0].Since)
+
+	rules[0].Alert = false
+	is.Equal(0, len(Evaluate(rules, all, nil, now)))
+}
@@ -0,0 +1,193 @@
+package rules
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/rules")
+
+const (
+	rulesCollection  = "rules"
+	alertsCollection = "alerts"
+)
+
+type Service struct {
+	things things.Finder
+	store  storage.Store
+}
+
+func NewService(things things.Finder, store storage.Store) *Service {
+	return &Service{things: things, store: store}
+}
+
+// GetRules returns the saved rules followed by the defaults, ordered by property and scope.
+func (s *Service) GetRules(ctx context.Context) ([]Rule, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-rules")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	documents, err := s.store.List(ctx, rulesCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Rule{}
+	for _, document := range documents {
+		var r Rule
+		if err = json.Unmarshal(document, &r); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	slices.SortFunc(result, func(a, b Rule) int {
+		return cmp.Or(
+			cmp.Compare(a.Property, b.Property),
+			cmp.Compare(slices.Index(Scopes, a.Scope), slices.Index(Scopes, b.Scope)),
+			cmp.Compare(strings.ToLower(a.Target), strings.ToLower(b.Target)),
+			cmp.Compare(a.Tenant, b.Tenant),
+		)
+	})
+
+	return append(result, Defaults...), nil
+}
+
+// SaveRule stores a new rule, or replaces an existing one if the ID is set.
+func (s *Service) SaveRule(ctx context.Context, rule Rule) (Rule, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "save-rule")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	rule.Target = strings.TrimSpace(rule.Target)
+	rule.Builtin = false
+
+	err = rule.Validate()
+	if err != nil {
+		return Rule{}, err
+	}
+
+	if rule.ID == "" {
+		rule.ID = uuid.NewString()
+	} else if _, err = s.store.Get(ctx, rulesCollection, rule.ID); err != nil {
+		return Rule{}, err
+	}
+
+	document, err := json.Marshal(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	err = s.store.Put(ctx, rulesCollection, rule.ID, document)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (s *Service) DeleteRule(ctx context.Context, id string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "delete-rule")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	err = s.store.Delete(ctx, rulesCollection, id)
+	return err
+}
+
+// GetAlerts returns the ongoing alerts, the latest first.
+func (s *Service) GetAlerts(ctx context.Context) ([]Alert, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-alerts")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	alerts, err := s.alerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(alerts, func(a, b Alert) int { return b.Since.Compare(a.Since) })
+
+	return alerts, nil
+}
+
+// EvaluateRules checks the latest values of every thing against the rules, raises alerts for the
+// values that are beyond a critical limit and removes the alerts that are no longer.
+func (s *Service) EvaluateRules(ctx context.Context) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "evaluate-rules")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	rules, err := s.GetRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	ongoing, err := s.alerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	alerts := []Alert{}
+	// the things are only fetched if there is a rule that raises alerts
+	if slices.ContainsFunc(rules, func(r Rule) bool { return r.Alert }) {
+		var all []things.Thing
+		all, err = things.GetAllThings(ctx, s.things, map[string][]string{})
+		if err != nil {
+			return err
+		}
+		alerts = Evaluate(rules, all, ongoing, time.Now().UTC())
+	}
+
+	for _, a := range alerts {
+		var document []byte
+		document, err = json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if err = s.store.Put(ctx, alertsCollection, a.ID, document); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range ongoing {
+		if slices.ContainsFunc(alerts, func(current Alert) bool { return current.ID == a.ID }) {
+			continue
+		}
+		if deleteErr := s.store.Delete(ctx, alertsCollection, a.ID); deleteErr != nil && !errors.Is(deleteErr, storage.ErrNotFound) {
+			err = deleteErr
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) alerts(ctx context.Context) ([]Alert, error) {
+	documents, err := s.store.List(ctx, alertsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []Alert{}
+	for _, document := range documents {
+		var a Alert
+		if err := json.Unmarshal(document, &a); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, nil
+}
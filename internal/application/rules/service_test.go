@@ -0,0 +1,54 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestEvaluateRulesRaisesAndClearsAlerts(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	level := 95.0
+	finder := thingstest.Things{All: []things.Thing{
+		{ID: "bin-1", Type: "Container", SubType: "WasteContainer", TypeValues: things.TypeValues{Percent: &level}},
+	}}
+	svc := newTestService(t, finder)
+
+	// the defaults do not raise alerts
+	is.NoErr(svc.EvaluateRules(ctx))
+	alerts, err := svc.GetAlerts(ctx)
+	is.NoErr(err)
+	is.Equal(0, len(alerts))
+
+	rule, err := svc.SaveRule(ctx, Rule{Scope: ScopeSubType, Target: " WasteContainer ", Property: "percent", Critical: new(90.0), Alert: true})
+	is.NoErr(err)
+	is.Equal("WasteContainer", rule.Target)
+
+	is.NoErr(svc.EvaluateRules(ctx))
+	alerts, _ = svc.GetAlerts(ctx)
+	is.Equal(1, len(alerts))
+	is.Equal("bin-1", alerts[0].ThingID)
+
+	level = 10
+	is.NoErr(svc.EvaluateRules(ctx))
+	alerts, _ = svc.GetAlerts(ctx)
+	is.Equal(0, len(alerts))
+
+	is.NoErr(svc.DeleteRule(ctx, rule.ID))
+	rules, _ := svc.GetRules(ctx)
+	is.Equal(len(Defaults), len(rules))
+}
+
+func newTestService(t *testing.T, finder things.Finder) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(finder, store)
+}
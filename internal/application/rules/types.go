@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid rule")
+
+type Management interface {
+	GetRules(ctx context.Context) ([]Rule, error)
+	SaveRule(ctx context.Context, rule Rule) (Rule, error)
+	DeleteRule(ctx context.Context, id string) error
+	GetAlerts(ctx context.Context) ([]Alert, error)
+}
+
+// Scopes tell which things a rule applies to. A rule for a single thing wins over a rule for a
+// tag, which wins over a rule for a subtype, which wins over a rule for a type.
+const (
+	ScopeType    = "type"
+	ScopeSubType = "subtype"
+	ScopeTag     = "tag"
+	ScopeThing   = "thing"
+)
+
+var Scopes = []string{ScopeType, ScopeSubType, ScopeTag, ScopeThing}
+
+const (
+	ToneGood     = "good"
+	ToneWarning  = "warning"
+	ToneCritical = "critical"
+)
+
+// Properties are the values of a thing that limits can be set for.
+var Properties = []string{"percent", "temperature", "currentLevel", "power"}
+
+// Rule sets the limits of a property of the things in its scope. Values beyond the warning limit are
+// warnings, and values at or beyond the critical limit are critical. Below turns the limits around
+// for values that are bad when they are low, such as the level of a sand storage. A rule without a
+// tenant applies to every tenant.
+type Rule struct {
+	ID       string   `json:"id"`
+	Tenant   string   `json:"tenant,omitzero"`
+	Scope    string   `json:"scope"`
+	Target   string   `json:"target"`
+	Property string   `json:"property"`
+	Warning  *float64 `json:"warning,omitzero"`
+	Critical *float64 `json:"critical,omitzero"`
+	Below    bool     `json:"below,omitzero"`
+	// Alert raises an alert for the things that are beyond the critical limit.
+	Alert bool `json:"alert,omitzero"`
+
+	// Builtin is set for the default rules that keep the tones that the lists have always had.
+	Builtin bool `json:"-"`
+}
+
+func (r Rule) Validate() error {
+	if !slices.Contains(Scopes, r.Scope) {
+		return errors.Join(ErrInvalid, errors.New("unsupported scope"))
+	}
+	if strings.TrimSpace(r.Target) == "" {
+		return errors.Join(ErrInvalid, errors.New("a rule needs a target"))
+	}
+	if !slices.Contains(Properties, r.Property) {
+		return errors.Join(ErrInvalid, errors.New("unsupported property"))
+	}
+	if r.Warning == nil && r.Critical == nil {
+		return errors.Join(ErrInvalid, errors.New("a rule needs a warning or a critical limit"))
+	}
+	if r.Warning != nil && r.Critical != nil && r.beyond(*r.Warning, *r.Critical) {
+		return errors.Join(ErrInvalid, errors.New("the warning limit must come before the critical limit"))
+	}
+	return nil
+}
+
+// Tone tells how a value compares to the limits of the rule.
+func (r Rule) Tone(value float64) string {
+	switch {
+	case r.Critical != nil && (value == *r.Critical || r.beyond(value, *r.Critical)):
+		return ToneCritical
+	case r.Warning != nil && r.beyond(value, *r.Warning):
+		return ToneWarning
+	default:
+		return ToneGood
+	}
+}
+
+func (r Rule) beyond(value, limit float64) bool {
+	if r.Below {
+		return value < limit
+	}
+	return value > limit
+}
+
+// Matches tells whether a thing is in the scope of the rule.
+func (r Rule) Matches(s Subject) bool {
+	if r.Tenant != "" && r.Tenant != s.Tenant {
+		return false
+	}
+
+	switch r.Scope {
+	case ScopeThing:
+		return r.Target == s.ID
+	case ScopeTag:
+		return slices.Contains(s.Tags, r.Target)
+	case ScopeSubType:
+		return strings.EqualFold(r.Target, s.SubType)
+	case ScopeType:
+		return strings.EqualFold(r.Target, s.Type)
+	default:
+		return false
+	}
+}
+
+// Subject is what a rule is matched against.
+type Subject struct {
+	ID      string
+	Type    string
+	SubType string
+	Tenant  string
+	Tags    []string
+}
+
+// Alert is raised by the evaluator for a thing that has a value at or beyond the critical limit of
+// a rule, and is removed once the value is back within the limit.
+type Alert struct {
+	ID       string  `json:"id"`
+	RuleID   string  `json:"ruleID"`
+	ThingID  string  `json:"thingID"`
+	Name     string  `json:"name"`
+	Tenant   string  `json:"tenant"`
+	Property string  `json:"property"`
+	Value    float64 `json:"value"`
+	Limit    float64 `json:"limit"`
+	Below    bool    `json:"below,omitzero"`
+	// Since is when the value first went beyond the limit, and ObservedAt when it was last checked.
+	Since      time.Time `json:"since"`
+	ObservedAt time.Time `json:"observedAt"`
+}
@@ -0,0 +1,110 @@
+package sandstorage
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+const (
+	// RefillThreshold is how many percentage points the fill estimate has to rise to count as a refill
+	RefillThreshold = 15.0
+	// refillMergeWindow joins rises that follow each other, such as the sensors of a storage being
+	// covered one at a time, into one refill
+	refillMergeWindow = 12 * time.Hour
+)
+
+// Aggregate combines the levels of the sensors of a storage into one fill estimate. Unknown
+// aggregations are treated as the mean.
+func Aggregate(levels []float64, aggregation string) float64 {
+	if len(levels) == 0 {
+		return 0
+	}
+
+	switch aggregation {
+	case AggregationMin:
+		return slices.Min(levels)
+	case AggregationMax:
+		return slices.Max(levels)
+	default:
+		sum := 0.0
+		for _, l := range levels {
+			sum += l
+		}
+		return sum / float64(len(levels))
+	}
+}
+
+// Latest returns the latest level of every sensor, ordered by device. The values are grouped per
+// sensor, and a group without a device reference is taken to be a single unnamed sensor.
+func Latest(groups [][]things.Measurement) []Sensor {
+	latest := map[string]Sensor{}
+	for _, group := range groups {
+		for _, m := range group {
+			if m.Value == nil {
+				continue
+			}
+			current, ok := latest[m.RefDevice]
+			if !ok || m.Timestamp.After(current.ObservedAt) {
+				latest[m.RefDevice] = Sensor{DeviceID: m.RefDevice, Percent: *m.Value, ObservedAt: m.Timestamp}
+			}
+		}
+	}
+
+	sensors := make([]Sensor, 0, len(latest))
+	for _, s := range latest {
+		sensors = append(sensors, s)
+	}
+	slices.SortFunc(sensors, func(a, b Sensor) int { return cmp.Compare(a.DeviceID, b.DeviceID) })
+
+	return sensors
+}
+
+// Refills finds the refills of a storage in the levels of its sensors. The fill estimate is
+// recalculated from the latest level of every sensor each time one of them reports, and a refill
+// is a rise of at least RefillThreshold above the lowest estimate since the previous refill.
+func Refills(values []things.Measurement, aggregation string) []Refill {
+	values = slices.DeleteFunc(slices.Clone(values), func(m things.Measurement) bool { return m.Value == nil })
+	slices.SortStableFunc(values, func(a, b things.Measurement) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	refills := []Refill{}
+	latest := map[string]float64{}
+	low := 0.0
+
+	for i, m := range values {
+		latest[m.RefDevice] = *m.Value
+		fill := Aggregate(slices.Collect(maps.Values(latest)), aggregation)
+
+		if i == 0 {
+			low = fill
+			continue
+		}
+
+		if n := len(refills); n > 0 && m.Timestamp.Sub(refills[n-1].At) <= refillMergeWindow && fill > refills[n-1].After {
+			refills[n-1].After = fill
+			low = fill
+			continue
+		}
+
+		low = min(low, fill)
+		if fill-low >= RefillThreshold {
+			refills = append(refills, Refill{At: m.Timestamp, Before: low, After: fill})
+			low = fill
+		}
+	}
+
+	return refills
+}
+
+// SeasonStart returns the start of the winter season that now belongs to. A season starts on the
+// first of September, when storages are expected to be filled up ahead of the winter.
+func SeasonStart(now time.Time) time.Time {
+	start := time.Date(now.Year(), time.September, 1, 0, 0, 0, 0, now.Location())
+	if now.Before(start) {
+		start = start.AddDate(-1, 0, 0)
+	}
+	return start
+}
@@ -0,0 +1,81 @@
+package sandstorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+var now = time.Date(2026, 10, 12, 12, 0, 0, 0, time.UTC)
+
+func level(device string, hoursAgo int, percent float64) things.Measurement {
+	return things.Measurement{RefDevice: device, Timestamp: now.Add(-time.Duration(hoursAgo) * time.Hour), Value: &percent}
+}
+
+func TestAggregateCombinesTheLevelsOfTheSensors(t *testing.T) {
+	is := is.New(t)
+
+	levels := []float64{40, 80, 60}
+	is.Equal(60.0, Aggregate(levels, AggregationMean))
+	is.Equal(40.0, Aggregate(levels, AggregationMin))
+	is.Equal(80.0, Aggregate(levels, AggregationMax))
+	is.Equal(60.0, Aggregate(levels, "median"))
+	is.Equal(0.0, Aggregate(nil, AggregationMin))
+}
+
+func TestLatestKeepsTheNewestLevelOfEverySensor(t *testing.T) {
+	is := is.New(t)
+
+	sensors := Latest([][]things.Measurement{
+		{level("b", 5, 30), level("b", 1, 35)},
+		{level("a", 2, 70), level("a", 8, 90), {RefDevice: "a", Timestamp: now}},
+	})
+
+	is.Equal(2, len(sensors))
+	is.Equal(Sensor{DeviceID: "a", Percent: 70, ObservedAt: now.Add(-2 * time.Hour)}, sensors[0])
+	is.Equal(35.0, sensors[1].Percent)
+}
+
+func TestRefillsFindsRisesOfTheCombinedEstimate(t *testing.T) {
+	is := is.New(t)
+
+	values := []things.Measurement{
+		level("a", 100, 60), level("b", 100, 50),
+		level("a", 80, 40), level("b", 80, 30),
+		// the sensors are covered a few hours apart, which is one refill
+		level("a", 60, 90), level("b", 57, 80),
+		// a small rise is noise from the sensors
+		level("a", 40, 80), level("a", 30, 88),
+		level("a", 10, 20), level("b", 10, 20),
+		level("b", 2, 70),
+	}
+
+	refills := Refills(values, AggregationMean)
+	is.Equal(2, len(refills))
+	is.Equal(now.Add(-60*time.Hour), refills[0].At)
+	is.Equal(35.0, refills[0].Before)
+	is.Equal(85.0, refills[0].After)
+	is.Equal(20.0, refills[1].Before)
+	is.Equal(45.0, refills[1].After)
+
+	// with the lowest sensor as the estimate, filling one of two sensors is not a refill
+	is.Equal(1, len(Refills(values, AggregationMin)))
+}
+
+func TestSeasonStartsInSeptember(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), SeasonStart(now))
+	is.Equal(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), SeasonStart(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestStatusFollowsTheFillEstimate(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(StatusUnknown, Storage{}.Status())
+	is.Equal(StatusCritical, Storage{Fill: new(50.0)}.Status())
+	is.Equal(StatusLow, Storage{Fill: new(69.0)}.Status())
+	is.Equal(StatusReady, Storage{Fill: new(70.0)}.Status())
+}
@@ -0,0 +1,285 @@
+package sandstorage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/sandstorage")
+
+const (
+	containerType  = "Container"
+	sandStorageSub = "Sandstorage"
+
+	// fillingURN is the filling percentage of a container
+	fillingURN = "3435/2"
+
+	settingsCollection = "sandstorage"
+
+	// latestWindow is how far back the latest level of every sensor of a storage is searched for
+	latestWindow = 30 * 24 * time.Hour
+)
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+	store  storage.Store
+}
+
+func NewService(things thingSource, store storage.Store) *Service {
+	return &Service{things: things, store: store}
+}
+
+type settings struct {
+	ThingID     string `json:"thingID"`
+	Aggregation string `json:"aggregation"`
+}
+
+// GetSandStorage returns the latest level of every sensor of a storage and the fill estimate that
+// they are combined into.
+func (s *Service) GetSandStorage(ctx context.Context, thingID string) (Storage, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-sand-storage")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	aggregation, err := s.aggregation(ctx, thingID)
+	if err != nil {
+		return Storage{}, err
+	}
+
+	now := time.Now()
+	thing, err := s.things.GetThing(ctx, thingID, levelsQuery(now.Add(-latestWindow), now))
+	if err != nil {
+		return Storage{}, err
+	}
+
+	return toStorage(thing, aggregation), nil
+}
+
+// SetFillAggregation saves how the levels of the sensors of a storage are combined. The storage is
+// fetched first, so that only a user who can see it may change it.
+func (s *Service) SetFillAggregation(ctx context.Context, thingID, aggregation string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "set-fill-aggregation")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	if !slices.Contains(Aggregations, aggregation) {
+		err = errors.Join(ErrInvalid, errors.New("unsupported aggregation"))
+		return err
+	}
+
+	thing, err := s.things.GetThing(ctx, thingID, nil)
+	if errors.Is(err, client.ErrNotFound) {
+		err = errors.Join(ErrNotFound, err)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(thing.SubType, sandStorageSub) {
+		err = ErrNotFound
+		return err
+	}
+
+	document, err := json.Marshal(settings{ThingID: thingID, Aggregation: aggregation})
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Put(ctx, settingsCollection, thingID, document)
+	return err
+}
+
+// GetRefills returns the refills of a storage between from and to, oldest first.
+func (s *Service) GetRefills(ctx context.Context, thingID string, from, to time.Time) ([]Refill, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-refills")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	aggregation, err := s.aggregation(ctx, thingID)
+	if err != nil {
+		return nil, err
+	}
+
+	thing, err := s.things.GetThing(ctx, thingID, levelsQuery(from, to))
+	if err != nil {
+		return nil, err
+	}
+
+	return Refills(slices.Concat(thing.Values...), aggregation), nil
+}
+
+// GetWinterReadiness collects the fill state and latest refill of every sand storage of a tenant,
+// or of every tenant if tenant is empty, with the storages that need to be refilled first. A storage
+// without levels keeps its fill aggregation so that the report can still say how it is measured.
+func (s *Service) GetWinterReadiness(ctx context.Context, tenant string, from, to time.Time) (Report, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-winter-readiness")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	containers, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": {containerType}})
+	if err != nil {
+		return Report{}, err
+	}
+	containers = slices.DeleteFunc(containers, func(t things.Thing) bool {
+		return !strings.EqualFold(t.SubType, sandStorageSub) || (tenant != "" && t.Tenant != tenant)
+	})
+
+	aggregations, err := s.aggregations(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	log := logging.GetFromContext(ctx)
+
+	storages, err := things.Collect(containers, func(t things.Thing) (Storage, error) {
+		aggregation := cmp.Or(aggregations[t.ID], AggregationMean)
+
+		thing, err := s.things.GetThing(ctx, t.ID, levelsQuery(from, to))
+		if err != nil {
+			return Storage{}, err
+		}
+		thing.Name, thing.Tenant = t.Name, t.Tenant
+		if len(thing.RefDevices) == 0 {
+			thing.RefDevices = t.RefDevices
+		}
+
+		result := toStorage(thing, aggregation)
+		if refills := Refills(slices.Concat(thing.Values...), aggregation); len(refills) > 0 {
+			result.LastRefill = &refills[len(refills)-1]
+		}
+		return result, nil
+	}, func(t things.Thing, err error) Storage {
+		log.Debug("could not fetch sand storage levels", "thing_id", t.ID, "err", err.Error())
+		aggregation := cmp.Or(aggregations[t.ID], AggregationMean)
+		return Storage{ThingID: t.ID, Name: cmp.Or(t.Name, t.ID), Tenant: t.Tenant, Aggregation: aggregation, Failed: true}
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	slices.SortFunc(storages, compareStorages)
+
+	return Report{Tenant: tenant, From: from, To: to, Storages: storages}, nil
+}
+
+func (s *Service) aggregation(ctx context.Context, thingID string) (string, error) {
+	document, err := s.store.Get(ctx, settingsCollection, thingID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return AggregationMean, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var settings settings
+	if err := json.Unmarshal(document, &settings); err != nil {
+		return "", err
+	}
+
+	return cmp.Or(settings.Aggregation, AggregationMean), nil
+}
+
+func (s *Service) aggregations(ctx context.Context) (map[string]string, error) {
+	documents, err := s.store.List(ctx, settingsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregations := map[string]string{}
+	for _, document := range documents {
+		var settings settings
+		if err := json.Unmarshal(document, &settings); err != nil {
+			continue
+		}
+		aggregations[settings.ThingID] = settings.Aggregation
+	}
+
+	return aggregations, nil
+}
+
+func levelsQuery(from, to time.Time) url.Values {
+	query := url.Values{}
+	query.Add("timerel", "between")
+	query.Add("timeat", from.UTC().Format(time.RFC3339))
+	query.Add("endTimeAt", to.UTC().Format(time.RFC3339))
+	query.Add("n", fillingURN)
+	// the levels are needed per sensor to combine them into the fill estimate of the storage
+	query.Add("options", "groupByRef")
+	return query
+}
+
+func toStorage(t things.Thing, aggregation string) Storage {
+	result := Storage{
+		ThingID:     t.ID,
+		Name:        cmp.Or(t.Name, t.ID),
+		Tenant:      t.Tenant,
+		Aggregation: aggregation,
+		Sensors:     Latest(t.Values),
+	}
+
+	levels := make([]float64, 0, len(result.Sensors))
+	for _, sensor := range result.Sensors {
+		levels = append(levels, sensor.Percent)
+		if sensor.ObservedAt.After(result.ObservedAt) {
+			result.ObservedAt = sensor.ObservedAt
+		}
+	}
+
+	// the sensors can only be told apart if the backend refers every level to its device
+	if !slices.ContainsFunc(result.Sensors, func(s Sensor) bool { return s.DeviceID == "" }) {
+		for _, ref := range t.RefDevices {
+			if !slices.ContainsFunc(result.Sensors, func(s Sensor) bool { return s.DeviceID == ref.DeviceID }) {
+				result.Silent = append(result.Silent, ref.DeviceID)
+			}
+		}
+	}
+
+	if len(levels) > 0 {
+		result.Fill = new(Aggregate(levels, aggregation))
+	}
+
+	return result
+}
+
+func compareStorages(a, b Storage) int {
+	if a.Failed != b.Failed {
+		if a.Failed {
+			return 1
+		}
+		return -1
+	}
+	return cmp.Or(
+		cmp.Compare(statusRank(a.Status()), statusRank(b.Status())),
+		cmp.Compare(fill(a), fill(b)),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
+
+func statusRank(status string) int {
+	return slices.Index([]string{StatusCritical, StatusLow, StatusUnknown, StatusReady}, status)
+}
+
+func fill(s Storage) float64 {
+	if s.Fill == nil {
+		return 0
+	}
+	return *s.Fill
+}
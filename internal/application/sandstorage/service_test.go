@@ -0,0 +1,83 @@
+package sandstorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestGetSandStorageUsesTheSavedAggregation(t *testing.T) {
+	is := is.New(t)
+
+	svc := newTestService(t, thingstest.Things{ByID: map[string]things.Thing{
+		"storage-1": sandStorage("storage-1", [][]things.Measurement{{level("a", 2, 40)}, {level("b", 1, 80)}}, "a", "b", "c"),
+	}})
+
+	s, err := svc.GetSandStorage(context.Background(), "storage-1")
+	is.NoErr(err)
+	is.Equal(AggregationMean, s.Aggregation)
+	is.Equal(60.0, *s.Fill)
+	is.Equal([]string{"c"}, s.Silent)
+	is.Equal(now.Add(-1*time.Hour), s.ObservedAt)
+
+	is.True(errors.Is(svc.SetFillAggregation(context.Background(), "storage-1", "median"), ErrInvalid))
+	is.NoErr(svc.SetFillAggregation(context.Background(), "storage-1", AggregationMin))
+	is.True(errors.Is(svc.SetFillAggregation(context.Background(), "hidden", AggregationMin), ErrNotFound))
+
+	s, err = svc.GetSandStorage(context.Background(), "storage-1")
+	is.NoErr(err)
+	is.Equal(40.0, *s.Fill)
+}
+
+func TestGetWinterReadinessListsTheStoragesThatNeedRefillingFirst(t *testing.T) {
+	is := is.New(t)
+
+	svc := newTestService(t, thingstest.Things{
+		All: []things.Thing{
+			{ID: "full", Name: "Full", Type: containerType, SubType: "Sandstorage", Tenant: "default"},
+			{ID: "empty", Name: "Empty", Type: containerType, SubType: "Sandstorage", Tenant: "default"},
+			{ID: "broken", Name: "Broken", Type: containerType, SubType: "Sandstorage", Tenant: "default"},
+			{ID: "other", Name: "Other", Type: containerType, SubType: "Sandstorage", Tenant: "other"},
+			{ID: "bin", Name: "Bin", Type: containerType, SubType: "WasteContainer", Tenant: "default"},
+		},
+		ByID: map[string]things.Thing{
+			"full":  sandStorage("full", [][]things.Measurement{{level("a", 30, 20), level("a", 10, 90)}}),
+			"empty": sandStorage("empty", [][]things.Measurement{{level("b", 10, 10)}}),
+			"other": sandStorage("other", [][]things.Measurement{{level("c", 10, 10)}}),
+		},
+	})
+
+	report, err := svc.GetWinterReadiness(context.Background(), "default", SeasonStart(now), now)
+	is.NoErr(err)
+	is.Equal(3, len(report.Storages))
+	is.Equal("empty", report.Storages[0].ThingID)
+	is.Equal("full", report.Storages[1].ThingID)
+	is.Equal(70.0, report.Storages[1].LastRefill.Amount())
+	is.True(report.Storages[2].Failed)
+	is.Equal(1, report.Count(StatusCritical))
+	is.Equal(1, report.Count(StatusReady))
+}
+
+func newTestService(t *testing.T, things thingstest.Things) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(things, store)
+}
+
+// sandStorage returns a sand storage with the fill levels of its sensors, and the sensors that it
+// is connected to.
+func sandStorage(id string, levels [][]things.Measurement, refs ...string) things.Thing {
+	thing := things.Thing{ID: id, Type: containerType, SubType: sandStorageSub, Values: levels}
+	for _, ref := range refs {
+		thing.RefDevices = append(thing.RefDevices, things.RefDevice{DeviceID: ref})
+	}
+	return thing
+}
@@ -0,0 +1,112 @@
+package sandstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalid  = errors.New("invalid aggregation")
+	ErrNotFound = errors.New("sand storage not found")
+)
+
+type Management interface {
+	GetSandStorage(ctx context.Context, thingID string) (Storage, error)
+	SetFillAggregation(ctx context.Context, thingID, aggregation string) error
+	GetRefills(ctx context.Context, thingID string, from, to time.Time) ([]Refill, error)
+	GetWinterReadiness(ctx context.Context, tenant string, from, to time.Time) (Report, error)
+}
+
+// Aggregations tell how the levels of the sensors of a storage are combined into one fill estimate.
+const (
+	AggregationMean = "mean"
+	AggregationMin  = "min"
+	AggregationMax  = "max"
+)
+
+var Aggregations = []string{AggregationMean, AggregationMin, AggregationMax}
+
+// The levels, in percent, that a storage should be filled to before winter and below which it has
+// to be refilled at once. They are the same levels as the tones of the sand storage list.
+const (
+	ReadyLevel    = 70.0
+	CriticalLevel = 50.0
+)
+
+const (
+	StatusReady    = "ready"
+	StatusLow      = "low"
+	StatusCritical = "critical"
+	StatusUnknown  = "unknown"
+)
+
+// DefaultRefillDays is how far back the refill history of a storage is shown by default.
+const DefaultRefillDays = 90
+
+// Sensor is the latest level reported by one of the sensors of a storage.
+type Sensor struct {
+	DeviceID   string
+	Percent    float64
+	ObservedAt time.Time
+}
+
+// Storage is the current fill state of a sand storage. Fill is nil when none of its sensors has
+// reported a level.
+type Storage struct {
+	ThingID     string
+	Name        string
+	Tenant      string
+	Aggregation string
+	Sensors     []Sensor
+	// Silent are the connected sensors that have not reported a level.
+	Silent     []string
+	Fill       *float64
+	ObservedAt time.Time
+	LastRefill *Refill
+	Failed     bool
+}
+
+func (s Storage) Status() string {
+	switch {
+	case s.Fill == nil:
+		return StatusUnknown
+	case *s.Fill <= CriticalLevel:
+		return StatusCritical
+	case *s.Fill < ReadyLevel:
+		return StatusLow
+	default:
+		return StatusReady
+	}
+}
+
+// Refill is a rise of the combined fill estimate, from the lowest level before it to the level
+// after it.
+type Refill struct {
+	At     time.Time
+	Before float64
+	After  float64
+}
+
+func (r Refill) Amount() float64 {
+	return r.After - r.Before
+}
+
+// Report is the winter readiness of the sand storages of a tenant, or of every tenant when Tenant
+// is empty. Refills are counted from From.
+type Report struct {
+	Tenant   string
+	From     time.Time
+	To       time.Time
+	Storages []Storage
+}
+
+func (r Report) Count(status string) int {
+	n := 0
+	for _, s := range r.Storages {
+		if !s.Failed && s.Status() == status {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,167 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/diwise/diwise-web/internal/application/alarms"
+	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/search")
+
+// number of recent alarms and things that are fetched and matched locally
+const (
+	recentAlarms = 100
+	thingsWindow = 500
+)
+
+type deviceFinder interface {
+	GetDevices(ctx context.Context, offset, limit int, args map[string][]string) (devices.DeviceResult, error)
+}
+
+type thingFinder interface {
+	GetThings(ctx context.Context, offset, limit int, args map[string][]string) (things.Result, error)
+}
+
+type alarmFinder interface {
+	GetAlarms(ctx context.Context, offset, limit int, args map[string][]string) (alarms.Result, error)
+}
+
+type Service struct {
+	devices deviceFinder
+	things  thingFinder
+	alarms  alarmFinder
+}
+
+func NewService(devices deviceFinder, things thingFinder, alarms alarmFinder) *Service {
+	return &Service{devices: devices, things: things, alarms: alarms}
+}
+
+// Search looks for sensors, things and recent alarms matching query. The sources are queried in
+// parallel and a failing source only empties its own group. An error is returned when all sources fail.
+func (s *Service) Search(ctx context.Context, query string, limit int) (Result, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "search")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	query = strings.TrimSpace(query)
+	result := Result{Query: query}
+	if query == "" {
+		return result, nil
+	}
+
+	var sensorsErr, thingsErr, alarmsErr error
+
+	var wg sync.WaitGroup
+	wg.Go(func() { result.Sensors, sensorsErr = s.searchSensors(ctx, query, limit) })
+	wg.Go(func() { result.Things, thingsErr = s.searchThings(ctx, query, limit) })
+	wg.Go(func() { result.Alarms, alarmsErr = s.searchAlarms(ctx, query, limit) })
+	wg.Wait()
+
+	if sensorsErr != nil && thingsErr != nil && alarmsErr != nil {
+		err = errors.Join(sensorsErr, thingsErr, alarmsErr)
+		return Result{Query: query}, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) searchSensors(ctx context.Context, query string, limit int) ([]Hit, error) {
+	result, err := s.devices.GetDevices(ctx, 0, limit, map[string][]string{"search": {query}})
+	if err != nil {
+		return nil, fmt.Errorf("could not search sensors: %w", err)
+	}
+
+	hits := make([]Hit, 0, len(result.Devices))
+	for _, d := range result.Devices {
+		hits = append(hits, Hit{
+			ID:          d.DeviceID,
+			Title:       cmp.Or(d.Name, d.DeviceID),
+			Description: d.SensorID,
+			Href:        "/sensors/" + d.DeviceID,
+		})
+	}
+
+	return hits, nil
+}
+
+func (s *Service) searchThings(ctx context.Context, query string, limit int) ([]Hit, error) {
+	result, err := s.things.GetThings(ctx, 0, thingsWindow, map[string][]string{"search": {query}})
+	if err != nil {
+		return nil, fmt.Errorf("could not search things: %w", err)
+	}
+
+	hits := []Hit{}
+	for _, t := range result.Things {
+		if !thingMatches(t, query) {
+			continue
+		}
+
+		hits = append(hits, Hit{
+			ID:          t.ID,
+			Title:       cmp.Or(t.Name, t.ID),
+			Description: strings.Join(slices.DeleteFunc([]string{t.AlternativeName, strings.Join(t.Tags, ", ")}, isEmpty), " · "),
+			Href:        "/things/" + t.ID,
+		})
+
+		if len(hits) == limit {
+			break
+		}
+	}
+
+	return hits, nil
+}
+
+func (s *Service) searchAlarms(ctx context.Context, query string, limit int) ([]Hit, error) {
+	result, err := s.alarms.GetAlarms(ctx, 0, recentAlarms, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not search alarms: %w", err)
+	}
+
+	hits := []Hit{}
+	for _, a := range result.Alarms {
+		if !contains(a.DeviceID, query) && !slices.ContainsFunc(a.Types, func(t string) bool { return contains(t, query) }) {
+			continue
+		}
+
+		hits = append(hits, Hit{
+			ID:          a.DeviceID,
+			Title:       a.DeviceID,
+			Description: strings.Join(a.Types, ", "),
+			Href:        "/sensors/" + a.DeviceID,
+			ObservedAt:  a.ObservedAt,
+		})
+
+		if len(hits) == limit {
+			break
+		}
+	}
+
+	return hits, nil
+}
+
+// thingMatches checks the fields a user is likely to search for, since the things backend
+// may not apply the search parameter to all of them.
+func thingMatches(t things.Thing, query string) bool {
+	if contains(t.Name, query) || contains(t.AlternativeName, query) || contains(t.ID, query) {
+		return true
+	}
+	return slices.ContainsFunc(t.Tags, func(tag string) bool { return contains(tag, query) })
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func isEmpty(s string) bool {
+	return s == ""
+}
@@ -0,0 +1,94 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/alarms"
+	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+func TestSearchGroupsMatchesBySource(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(
+		testDevices{result: devices.DeviceResult{Devices: []devices.Device{{DeviceID: "dev-1", SensorID: "a81758fffe0", Name: "Pump 1"}}}},
+		testThings{result: things.Result{Things: []things.Thing{
+			{ID: "t1", Name: "Container A", Tags: []string{"north"}},
+			{ID: "t2", Name: "Pump station", AlternativeName: "PS-04"},
+			{ID: "t3", Name: "Beach", Tags: []string{"Pumphouse"}},
+		}}},
+		testAlarms{result: alarms.Result{Alarms: []alarms.Alarm{
+			{DeviceID: "pump-7", Types: []string{"battery"}},
+			{DeviceID: "dev-2", Types: []string{"payload"}},
+		}}},
+	)
+
+	result, err := svc.Search(context.Background(), " pump ", 10)
+	is.NoErr(err)
+
+	is.Equal("pump", result.Query)
+	is.Equal(1, len(result.Sensors))
+	is.Equal("/sensors/dev-1", result.Sensors[0].Href)
+	is.Equal(2, len(result.Things))
+	is.Equal("t2", result.Things[0].ID)
+	is.Equal("PS-04", result.Things[0].Description)
+	is.Equal("t3", result.Things[1].ID)
+	is.Equal(1, len(result.Alarms))
+	is.Equal("pump-7", result.Alarms[0].ID)
+}
+
+func TestSearchKeepsResultsWhenOneSourceFails(t *testing.T) {
+	is := is.New(t)
+
+	svc := NewService(
+		testDevices{err: errors.New("unavailable")},
+		testThings{result: things.Result{Things: []things.Thing{{ID: "t1", Name: "Room 1"}}}},
+		testAlarms{err: errors.New("unavailable")},
+	)
+
+	result, err := svc.Search(context.Background(), "room", 10)
+	is.NoErr(err)
+	is.Equal(0, len(result.Sensors))
+	is.Equal(1, len(result.Things))
+}
+
+func TestSearchFailsWhenAllSourcesFail(t *testing.T) {
+	is := is.New(t)
+
+	failure := errors.New("unavailable")
+	svc := NewService(testDevices{err: failure}, testThings{err: failure}, testAlarms{err: failure})
+
+	_, err := svc.Search(context.Background(), "room", 10)
+	is.True(errors.Is(err, failure))
+}
+
+type testDevices struct {
+	result devices.DeviceResult
+	err    error
+}
+
+func (d testDevices) GetDevices(context.Context, int, int, map[string][]string) (devices.DeviceResult, error) {
+	return d.result, d.err
+}
+
+type testThings struct {
+	result things.Result
+	err    error
+}
+
+func (t testThings) GetThings(context.Context, int, int, map[string][]string) (things.Result, error) {
+	return t.result, t.err
+}
+
+type testAlarms struct {
+	result alarms.Result
+	err    error
+}
+
+func (a testAlarms) GetAlarms(context.Context, int, int, map[string][]string) (alarms.Result, error) {
+	return a.result, a.err
+}
@@ -0,0 +1,31 @@
+package search
+
+import (
+	"context"
+	"time"
+)
+
+type Management interface {
+	Search(ctx context.Context, query string, limit int) (Result, error)
+}
+
+// Result groups the hits of a global search by the kind of entity that matched.
+type Result struct {
+	Query   string
+	Sensors []Hit
+	Things  []Hit
+	Alarms  []Hit
+}
+
+// Hit is a single search result with enough information to render and link it.
+type Hit struct {
+	ID          string
+	Title       string
+	Description string
+	Href        string
+	ObservedAt  time.Time
+}
+
+func (r Result) Empty() bool {
+	return len(r.Sensors) == 0 && len(r.Things) == 0 && len(r.Alarms) == 0
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var validBlobKey = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]*$`)
+
+// s3Timeout is how long a request to the object storage may take, including the reading of a photo,
+// before the request of the user is given up on.
+const s3Timeout = 60 * time.Second
+
+// Blob is a file kept in a BlobStore. The caller must close Body.
+type Blob struct {
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// BlobStore keeps files that are too large to be documents in a Store, such as photos. Blobs are
+// addressed by a flat key of letters, digits, dots, dashes and underscores.
+type BlobStore interface {
+	PutBlob(ctx context.Context, key, contentType string, data []byte) error
+	GetBlob(ctx context.Context, key string) (Blob, error)
+	DeleteBlob(ctx context.Context, key string) error
+}
+
+// OpenBlobs creates a BlobStore from a storage URL. Supported schemes are file:// (a directory, also
+// used when no scheme is given) and s3:// for S3 compatible object storage, given as
+// s3://access-key:secret-key@host[:port]/bucket?region=eu-north-1. Add tls=false to talk plain http
+// to a local stand-in such as MinIO.
+func OpenBlobs(_ context.Context, storageURL string) (BlobStore, error) {
+	scheme, path, found := strings.Cut(storageURL, "://")
+	if !found {
+		return NewFileBlobStore(storageURL)
+	}
+
+	switch scheme {
+	case "file":
+		return NewFileBlobStore(path)
+	case "s3":
+		u, err := url.Parse(storageURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid s3 url: %w", err)
+		}
+
+		cfg := S3Config{
+			Endpoint: "https://" + u.Host,
+			Bucket:   strings.Trim(u.Path, "/"),
+			Region:   u.Query().Get("region"),
+		}
+		if u.Query().Get("tls") == "false" {
+			cfg.Endpoint = "http://" + u.Host
+		}
+		if u.User != nil {
+			cfg.AccessKey = u.User.Username()
+			cfg.SecretKey, _ = u.User.Password()
+		}
+
+		return NewS3BlobStore(cfg, &http.Client{Timeout: s3Timeout})
+	default:
+		return nil, fmt.Errorf("unsupported blob storage scheme %q", scheme)
+	}
+}
+
+type fileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore returns a BlobStore that keeps each blob as a file in dir.
+func NewFileBlobStore(dir string) (BlobStore, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("could not create blob directory %s: %w", dir, err)
+	}
+
+	return &fileBlobStore{dir: dir}, nil
+}
+
+func (s *fileBlobStore) PutBlob(_ context.Context, key, _ string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a crash never leaves a half written blob behind
+	tmp, err := os.CreateTemp(s.dir, "blob-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// GetBlob returns a stored file. The files are kept without their content type, so it is sniffed
+// from the first bytes of the file.
+func (s *fileBlobStore) GetBlob(_ context.Context, key string) (Blob, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return Blob{}, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Blob{}, ErrNotFound
+	}
+	if err != nil {
+		return Blob{}, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return Blob{}, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		f.Close()
+		return Blob{}, err
+	}
+
+	return Blob{
+		ContentType: http.DetectContentType(head[:n]),
+		Size:        info.Size(),
+		Body:        readCloser{Reader: io.MultiReader(bytes.NewReader(head[:n]), f), Closer: f},
+	}, nil
+}
+
+func (s *fileBlobStore) DeleteBlob(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func (s *fileBlobStore) path(key string) (string, error) {
+	if !validBlobKey.MatchString(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+var validCollectionName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type fileStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileStore returns a Store that keeps each collection as a JSON document in dir. Every change
+// rewrites the whole collection, so the file store is meant for development and small installations
+// rather than for collections that keep growing, such as the history of things.
+func NewFileStore(dir string) (Store, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("could not create storage directory %s: %w", dir, err)
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) Get(_ context.Context, collection, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	documents, err := s.read(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	document, ok := documents[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return document, nil
+}
+
+func (s *fileStore) Put(_ context.Context, collection, key string, value []byte) error {
+	if !json.Valid(value) {
+		return errors.New("file storage only accepts json documents")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	documents, err := s.read(collection)
+	if err != nil {
+		return err
+	}
+
+	documents[key] = value
+	return s.write(collection, documents)
+}
+
+func (s *fileStore) Delete(_ context.Context, collection, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	documents, err := s.read(collection)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := documents[key]; !ok {
+		return ErrNotFound
+	}
+
+	delete(documents, key)
+	return s.write(collection, documents)
+}
+
+func (s *fileStore) List(_ context.Context, collection string) ([][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	documents, err := s.read(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]byte, 0, len(documents))
+	for _, key := range slices.Sorted(maps.Keys(documents)) {
+		result = append(result, documents[key])
+	}
+
+	return result, nil
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+func (s *fileStore) path(collection string) (string, error) {
+	if !validCollectionName.MatchString(collection) {
+		return "", fmt.Errorf("invalid collection name %q", collection)
+	}
+	return filepath.Join(s.dir, collection+".json"), nil
+}
+
+func (s *fileStore) read(collection string) (map[string]json.RawMessage, error) {
+	path, err := s.path(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]json.RawMessage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	documents := map[string]json.RawMessage{}
+	err = json.Unmarshal(b, &documents)
+	if err != nil {
+		return nil, fmt.Errorf("could not read collection %s: %w", collection, err)
+	}
+
+	return documents, nil
+}
+
+func (s *fileStore) write(collection string, documents map[string]json.RawMessage) error {
+	path, err := s.path(collection)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(documents)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a crash never leaves a half written collection behind
+	tmp, err := os.CreateTemp(s.dir, collection+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
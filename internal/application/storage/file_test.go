@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFileStorePutGetListDelete(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store, err := NewFileStore(t.TempDir())
+	is.NoErr(err)
+
+	is.NoErr(store.Put(ctx, "views", "b", []byte(`{"name":"b"}`)))
+	is.NoErr(store.Put(ctx, "views", "a", []byte(`{"name":"a"}`)))
+
+	b, err := store.Get(ctx, "views", "a")
+	is.NoErr(err)
+	is.Equal(`{"name":"a"}`, string(b))
+
+	documents, err := store.List(ctx, "views")
+	is.NoErr(err)
+	is.Equal(2, len(documents))
+	is.Equal(`{"name":"a"}`, string(documents[0]))
+
+	is.NoErr(store.Delete(ctx, "views", "a"))
+	_, err = store.Get(ctx, "views", "a")
+	is.True(errors.Is(err, ErrNotFound))
+}
+
+func TestFileStoreRejectsInvalidCollectionNames(t *testing.T) {
+	is := is.New(t)
+
+	store, err := NewFileStore(t.TempDir())
+	is.NoErr(err)
+
+	err = store.Put(context.Background(), "../escape", "a", []byte(`{}`))
+	is.True(err != nil)
+}
+
+func TestOpenUsesFileStoreForPlainPaths(t *testing.T) {
+	is := is.New(t)
+
+	store, err := Open(context.Background(), "file://"+t.TempDir())
+	is.NoErr(err)
+
+	_, ok := store.(*fileStore)
+	is.True(ok)
+}
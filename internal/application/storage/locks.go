@@ -0,0 +1,45 @@
+package storage
+
+import "sync"
+
+// Locks serialises the changes to a document, so that two requests that read, change and write the
+// same document do not overwrite each other. It only guards against requests to the same instance of
+// diwise-web.
+type Locks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock counts the holder and the waiters of a lock, so that it can be dropped once no one needs it.
+type keyLock struct {
+	sync.Mutex
+	users int
+}
+
+// Lock waits until no one else holds the lock of the key, and returns the func that releases it.
+func (l *Locks) Lock(key string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*keyLock{}
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		l.locks[key] = lock
+	}
+	lock.users++
+	l.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
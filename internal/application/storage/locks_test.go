@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLocksAreDroppedOnceReleased(t *testing.T) {
+	is := is.New(t)
+
+	var locks Locks
+	count := 0
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			unlock := locks.Lock("things/bin-1")
+			defer unlock()
+			count++
+		})
+	}
+	wg.Wait()
+
+	is.Equal(50, count)
+	is.Equal(0, len(locks.locks))
+}
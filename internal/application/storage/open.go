@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqlitePragmas let readers go on while a document is written, and make a write wait for another
+// write to finish rather than fail with SQLITE_BUSY. The background jobs and the requests write at
+// the same time.
+const sqlitePragmas = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+// Open creates a Store from a storage URL. Supported schemes are file:// (a directory of JSON files for
+// development, also used when no scheme is given) and sqlite:// (a SQLite database file). SQLite storage uses the
+// pure Go driver from modernc.org/sqlite.
+func Open(ctx context.Context, storageURL string) (Store, error) {
+	scheme, path, found := strings.Cut(storageURL, "://")
+	if !found {
+		return NewFileStore(storageURL)
+	}
+
+	switch scheme {
+	case "file":
+		return NewFileStore(path)
+	case "sqlite":
+		db, err := sql.Open("sqlite", sqliteDSN(path))
+		if err != nil {
+			return nil, fmt.Errorf("could not open sqlite database %s: %w", path, err)
+		}
+		// SQLite allows a single writer, so the writes are queued in the pool instead of in the database
+		db.SetMaxOpenConns(1)
+
+		store, err := NewSQLStore(ctx, db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage scheme %q", scheme)
+	}
+}
+
+func sqliteDSN(path string) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + sqlitePragmas
+	}
+	return path + "?" + sqlitePragmas
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type sqlStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore returns a Store backed by a single documents table in db. The statements are written
+// for SQLite, and the table is created if it does not exist.
+func NewSQLStore(ctx context.Context, db *sql.DB) (Store, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS documents (
+		collection TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value BLOB NOT NULL,
+		PRIMARY KEY (collection, key)
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("could not create documents table: %w", err)
+	}
+
+	return &sqlStore{db: db}, nil
+}
+
+func (s *sqlStore) Get(ctx context.Context, collection, key string) ([]byte, error) {
+	var value []byte
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT value FROM documents WHERE collection = ? AND key = ?`, collection, key,
+	).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	return value, err
+}
+
+func (s *sqlStore) Put(ctx context.Context, collection, key string, value []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO documents (collection, key, value) VALUES (?, ?, ?)
+		ON CONFLICT (collection, key) DO UPDATE SET value = excluded.value`,
+		collection, key, value,
+	)
+	return err
+}
+
+func (s *sqlStore) Delete(ctx context.Context, collection, key string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM documents WHERE collection = ? AND key = ?`, collection, key)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *sqlStore) List(ctx context.Context, collection string) ([][]byte, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT value FROM documents WHERE collection = ? ORDER BY key`, collection)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := [][]byte{}
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestOpenSQLiteStorePutGetListDelete(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store, err := Open(ctx, "sqlite://"+filepath.Join(t.TempDir(), "diwise-web.db"))
+	is.NoErr(err)
+
+	_, ok := store.(*sqlStore)
+	is.True(ok)
+
+	is.NoErr(store.Put(ctx, "views", "b", []byte(`{"name":"b"}`)))
+	is.NoErr(store.Put(ctx, "views", "a", []byte(`{"name":"a"}`)))
+	is.NoErr(store.Put(ctx, "views", "a", []byte(`{"name":"A"}`)))
+
+	a, err := store.Get(ctx, "views", "a")
+	is.NoErr(err)
+	is.Equal(`{"name":"A"}`, string(a))
+
+	documents, err := store.List(ctx, "views")
+	is.NoErr(err)
+	is.Equal(2, len(documents))
+
+	is.NoErr(store.Delete(ctx, "views", "a"))
+	_, err = store.Get(ctx, "views", "a")
+	is.True(errors.Is(err, ErrNotFound))
+}
+
+func TestOpenSQLiteStoreQueuesConcurrentWrites(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store, err := Open(ctx, "sqlite://"+filepath.Join(t.TempDir(), "diwise-web.db"))
+	is.NoErr(err)
+	defer store.Close()
+
+	var journalMode string
+	is.NoErr(store.(*sqlStore).db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode))
+	is.Equal("wal", journalMode)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Go(func() {
+			errs[i] = store.Put(ctx, "history", fmt.Sprint(i), []byte(`{"n":1}`))
+		})
+	}
+	wg.Wait()
+	is.NoErr(errors.Join(errs...))
+
+	documents, err := store.List(ctx, "history")
+	is.NoErr(err)
+	is.Equal(20, len(documents))
+}
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Store is a small document store for data owned by diwise-web itself, such as saved views or user
+// preferences. Documents are opaque byte slices grouped by collection and addressed by key.
+type Store interface {
+	Get(ctx context.Context, collection, key string) ([]byte, error)
+	Put(ctx context.Context, collection, key string, value []byte) error
+	Delete(ctx context.Context, collection, key string) error
+	List(ctx context.Context, collection string) ([][]byte, error)
+	Close() error
+}
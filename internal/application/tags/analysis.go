@@ -0,0 +1,49 @@
+package tags
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// Count returns how many of the things carry each tag. Known tags that no thing carries are
+// counted as unused. The tags are ordered by name, ignoring case, so that near duplicates such as
+// "skolan" and "Skolan" end up next to each other.
+func Count(all []things.Thing, known []string) []Usage {
+	counts := map[string]int{}
+	for _, tag := range known {
+		counts[tag] += 0
+	}
+	for _, t := range all {
+		for _, tag := range slices.Compact(slices.Sorted(slices.Values(t.Tags))) {
+			counts[tag]++
+		}
+	}
+
+	usage := make([]Usage, 0, len(counts))
+	for tag, n := range counts {
+		usage = append(usage, Usage{Tag: tag, Things: n})
+	}
+	slices.SortFunc(usage, func(a, b Usage) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Tag), strings.ToLower(b.Tag)), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	return usage
+}
+
+// Rename replaces a tag with another in the tags of a thing, keeping the order and dropping the
+// duplicate if the thing already carries both.
+func Rename(tags []string, from, to string) []string {
+	renamed := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag == from {
+			tag = to
+		}
+		if !slices.Contains(renamed, tag) {
+			renamed = append(renamed, tag)
+		}
+	}
+	return renamed
+}
@@ -0,0 +1,32 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+func TestCountKeepsNearDuplicatesTogether(t *testing.T) {
+	is := is.New(t)
+
+	usage := Count([]things.Thing{
+		{ID: "a", Tags: []string{"skolan", "park", "skolan"}},
+		{ID: "b", Tags: []string{"Skolan"}},
+		{ID: "c", Tags: []string{"skolan"}},
+	}, []string{"skola", "park"})
+
+	is.Equal([]Usage{
+		{Tag: "park", Things: 1},
+		{Tag: "skola", Things: 0},
+		{Tag: "Skolan", Things: 1},
+		{Tag: "skolan", Things: 2},
+	}, usage)
+}
+
+func TestRenameMergesIntoAnExistingTag(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal([]string{"skolan", "park"}, Rename([]string{"Skolan", "park", "skolan"}, "Skolan", "skolan"))
+	is.Equal([]string{"park", "skola"}, Rename([]string{"park", "skolan"}, "skolan", "skola"))
+}
@@ -0,0 +1,294 @@
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/tags")
+
+const (
+	// deletedCollection holds the unused tags that are no longer suggested to the users of a tenant,
+	// keyed by tenant and tag. The things service keeps no list of tags of its own that they could be
+	// removed from.
+	deletedCollection = "deletedtags"
+
+	// maxJobs is how many jobs are kept for their progress to be fetched
+	maxJobs = 20
+)
+
+type thingSource interface {
+	things.Finder
+	GetTags(ctx context.Context) ([]string, error)
+	UpdateThing(ctx context.Context, thingID string, fields map[string]any) error
+}
+
+type tenantSource interface {
+	GetTenants(ctx context.Context) []string
+}
+
+type Service struct {
+	things  thingSource
+	tenants tenantSource
+	store   storage.Store
+
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+func NewService(things thingSource, tenants tenantSource, store storage.Store) *Service {
+	return &Service{things: things, tenants: tenants, store: store}
+}
+
+type deleted struct {
+	Tenant string `json:"tenant"`
+	Tag    string `json:"tag"`
+}
+
+// GetTags returns the tags of the things service, leaving out the tags that have been deleted for
+// every tenant of the user.
+func (s *Service) GetTags(ctx context.Context) ([]string, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-tags")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	tags, err := s.things.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.deleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(tags, func(tag string) bool { return slices.Contains(removed, tag) }), nil
+}
+
+// GetTagUsage returns every tag together with the number of things that carry it.
+func (s *Service) GetTagUsage(ctx context.Context) ([]Usage, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-tag-usage")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	known, err := s.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := things.GetAllThings(ctx, s.things, map[string][]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	return Count(all, known), nil
+}
+
+// RenameTag starts a job that renames a tag on every thing that carries it.
+func (s *Service) RenameTag(ctx context.Context, from, to string) (Job, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "rename-tag")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	owner, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return Job{}, err
+	}
+
+	to = strings.TrimSpace(to)
+	if from == "" || to == "" {
+		err = errors.Join(ErrInvalid, errors.New("a tag cannot be empty"))
+		return Job{}, err
+	}
+	if from == to {
+		err = errors.Join(ErrInvalid, errors.New("the new name is the same as the old"))
+		return Job{}, err
+	}
+
+	carriers, err := things.GetAllThings(ctx, s.things, map[string][]string{"tags": {from}})
+	if err != nil {
+		return Job{}, err
+	}
+	// the filter of the things service is not relied on to match the tag exactly
+	carriers = slices.DeleteFunc(carriers, func(t things.Thing) bool { return !slices.Contains(t.Tags, from) })
+
+	if err = s.restore(ctx, s.tenants.GetTenants(ctx), []string{to}); err != nil {
+		return Job{}, err
+	}
+
+	job := &Job{ID: uuid.NewString(), Owner: owner, From: from, To: to, Total: len(carriers), Failed: []string{}}
+	s.track(job)
+
+	// the job outlives the request but keeps the credentials of the user that started it
+	go s.rename(context.WithoutCancel(ctx), job, carriers)
+
+	return s.snapshot(job), nil
+}
+
+// GetTagJob returns how far a rename that the user started has come.
+func (s *Service) GetTagJob(ctx context.Context, id string) (Job, error) {
+	subject := authz.Subject(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.ID == id && job.Owner == subject {
+			return copyJob(job), nil
+		}
+	}
+
+	return Job{}, ErrNotFound
+}
+
+// DeleteTag stops suggesting a tag that none of the things of the user carries to the users of the
+// tenants of the user.
+func (s *Service) DeleteTag(ctx context.Context, tag string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "delete-tag")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	tenants := s.tenants.GetTenants(ctx)
+	if len(tenants) == 0 {
+		err = errors.Join(ErrInvalid, errors.New("the user has no tenant to delete the tag for"))
+		return err
+	}
+
+	carriers, err := things.GetAllThings(ctx, s.things, map[string][]string{"tags": {tag}})
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(carriers, func(t things.Thing) bool { return slices.Contains(t.Tags, tag) }) {
+		err = ErrInUse
+		return err
+	}
+
+	for _, tenant := range tenants {
+		var document []byte
+		if document, err = json.Marshal(deleted{Tenant: tenant, Tag: tag}); err != nil {
+			return err
+		}
+		if err = s.store.Put(ctx, deletedCollection, deletedKey(tenant, tag), document); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore suggests deleted tags again to the users of the tenants of the user once they are given to
+// a thing.
+func (s *Service) Restore(ctx context.Context, tags []string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "restore-tags")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	err = s.restore(ctx, s.tenants.GetTenants(ctx), tags)
+	return err
+}
+
+func (s *Service) restore(ctx context.Context, tenants, tags []string) error {
+	for _, tenant := range tenants {
+		for _, tag := range tags {
+			if err := s.store.Delete(ctx, deletedCollection, deletedKey(tenant, tag)); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func deletedKey(tenant, tag string) string {
+	return tenant + "/" + tag
+}
+
+func (s *Service) rename(ctx context.Context, job *Job, carriers []things.Thing) {
+	log := logging.GetFromContext(ctx)
+
+	things.ForEach(carriers, func(_ int, t things.Thing) {
+		err := s.things.UpdateThing(ctx, t.ID, map[string]any{"tags": Rename(t.Tags, job.From, job.To)})
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		job.Done++
+		if err != nil {
+			log.Error("could not rename tag", "thing_id", t.ID, "from", job.From, "to", job.To, "err", err.Error())
+			job.Failed = append(job.Failed, t.ID)
+		}
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slices.Sort(job.Failed)
+	job.Finished = true
+}
+
+func (s *Service) track(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	if len(s.jobs) > maxJobs {
+		s.jobs = s.jobs[len(s.jobs)-maxJobs:]
+	}
+}
+
+func (s *Service) snapshot(job *Job) Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyJob(job)
+}
+
+func copyJob(job *Job) Job {
+	c := *job
+	c.Failed = slices.Clone(job.Failed)
+	return c
+}
+
+// deleted returns the tags that have been deleted for every tenant of the user.
+func (s *Service) deleted(ctx context.Context) ([]string, error) {
+	tenants := s.tenants.GetTenants(ctx)
+	if len(tenants) == 0 {
+		return []string{}, nil
+	}
+
+	documents, err := s.store.List(ctx, deletedCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedFor := map[string]int{}
+	for _, document := range documents {
+		var d deleted
+		if err := json.Unmarshal(document, &d); err != nil {
+			return nil, err
+		}
+		if slices.Contains(tenants, d.Tenant) {
+			deletedFor[d.Tag]++
+		}
+	}
+
+	tags := []string{}
+	for tag, n := range deletedFor {
+		if n == len(tenants) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
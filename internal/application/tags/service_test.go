@@ -0,0 +1,167 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestRenameTagUpdatesEveryThingThatCarriesIt(t *testing.T) {
+	is := is.New(t)
+	ctx := context.WithValue(context.Background(), authz.SubjectClaim, "alice")
+
+	source := &testThings{things: []things.Thing{
+		{ID: "a", Tags: []string{"Skolan", "park"}},
+		{ID: "b", Tags: []string{"Skolan", "skolan"}},
+		{ID: "c", Tags: []string{"park"}},
+	}}
+	svc := newTestService(t, source)
+
+	_, err := svc.RenameTag(ctx, "Skolan", " ")
+	is.True(errors.Is(err, ErrInvalid))
+
+	job, err := svc.RenameTag(ctx, "Skolan", "skolan")
+	is.NoErr(err)
+	is.Equal(2, job.Total)
+
+	for range 100 {
+		if job, err = svc.GetTagJob(ctx, job.ID); err != nil || job.Finished {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	is.NoErr(err)
+	is.True(job.Finished)
+	is.Equal(100, job.Percent())
+	is.Equal([]string{}, job.Failed)
+	is.Equal([]string{"skolan", "park"}, source.tagsOf("a"))
+	is.Equal([]string{"skolan"}, source.tagsOf("b"))
+
+	// only the user that started the job may follow it
+	_, err = svc.GetTagJob(context.WithValue(context.Background(), authz.SubjectClaim, "bob"), job.ID)
+	is.True(errors.Is(err, ErrNotFound))
+
+	_, err = svc.RenameTag(context.Background(), "park", "parken")
+	is.True(errors.Is(err, authz.ErrNoSubject))
+}
+
+func TestDeleteTagOnlyDeletesUnusedTags(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	source := &testThings{things: []things.Thing{{ID: "a", Tags: []string{"park"}}}, known: []string{"park", "skola"}}
+	svc := newTestService(t, source)
+
+	is.True(errors.Is(svc.DeleteTag(ctx, "park"), ErrInUse))
+	is.NoErr(svc.DeleteTag(ctx, "skola"))
+
+	tags, err := svc.GetTags(ctx)
+	is.NoErr(err)
+	is.Equal([]string{"park"}, tags)
+
+	is.NoErr(svc.Restore(ctx, []string{"skola"}))
+	tags, err = svc.GetTags(ctx)
+	is.NoErr(err)
+	is.Equal([]string{"park", "skola"}, tags)
+}
+
+func TestDeletedTagsAreKeptPerTenant(t *testing.T) {
+	is := is.New(t)
+
+	store, err := storage.NewFileStore(t.TempDir())
+	is.NoErr(err)
+
+	source := &testThings{known: []string{"park", "skola"}}
+	svc := NewService(source, testTenants{"default"}, store)
+	other := NewService(source, testTenants{"other"}, store)
+	both := NewService(source, testTenants{"default", "other"}, store)
+
+	is.NoErr(svc.DeleteTag(context.Background(), "skola"))
+
+	tags, err := svc.GetTags(context.Background())
+	is.NoErr(err)
+	is.Equal([]string{"park"}, tags)
+
+	// the tag is still suggested to the users of other tenants
+	tags, err = other.GetTags(context.Background())
+	is.NoErr(err)
+	is.Equal([]string{"park", "skola"}, tags)
+	tags, err = both.GetTags(context.Background())
+	is.NoErr(err)
+	is.Equal([]string{"park", "skola"}, tags)
+
+	is.True(errors.Is(NewService(source, testTenants{}, store).DeleteTag(context.Background(), "park"), ErrInvalid))
+}
+
+func newTestService(t *testing.T, source *testThings) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(source, testTenants{"default"}, store)
+}
+
+type testTenants []string
+
+func (tt testTenants) GetTenants(context.Context) []string {
+	return slices.Clone(tt)
+}
+
+type testThings struct {
+	mu     sync.Mutex
+	things []things.Thing
+	known  []string
+}
+
+func (tt *testThings) GetThings(_ context.Context, offset, _ int, args map[string][]string) (things.Result, error) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	result := things.Result{}
+	if offset > 0 {
+		return result, nil
+	}
+	for _, t := range tt.things {
+		if tags := args["tags"]; len(tags) == 0 || slices.ContainsFunc(t.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+			result.Things = append(result.Things, t)
+		}
+	}
+	result.Count, result.TotalRecords = len(result.Things), len(result.Things)
+	return result, nil
+}
+
+func (tt *testThings) GetTags(context.Context) ([]string, error) {
+	return slices.Clone(tt.known), nil
+}
+
+func (tt *testThings) UpdateThing(_ context.Context, id string, fields map[string]any) error {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	for i := range tt.things {
+		if tt.things[i].ID == id {
+			tt.things[i].Tags = fields["tags"].([]string)
+		}
+	}
+	return nil
+}
+
+func (tt *testThings) tagsOf(id string) []string {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	for _, t := range tt.things {
+		if t.ID == id {
+			return t.Tags
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package tags
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalid  = errors.New("invalid tag")
+	ErrInUse    = errors.New("tag is in use")
+	ErrNotFound = errors.New("job not found")
+)
+
+type Management interface {
+	GetTagUsage(ctx context.Context) ([]Usage, error)
+	RenameTag(ctx context.Context, from, to string) (Job, error)
+	GetTagJob(ctx context.Context, id string) (Job, error)
+	DeleteTag(ctx context.Context, tag string) error
+}
+
+// Usage is the number of things that carry a tag.
+type Usage struct {
+	Tag    string
+	Things int
+}
+
+// Job renames a tag on every thing that carries it. Renaming a tag to a tag that is already in use
+// merges the two. The things are updated in the background and the job tells how far it has come.
+type Job struct {
+	ID string
+	// Owner is the subject of the user that started the job, the only one that may follow it.
+	Owner string
+	From  string
+	To    string
+	Total int
+	Done  int
+	// Failed are the things that could not be updated.
+	Failed   []string
+	Finished bool
+}
+
+func (j Job) Percent() int {
+	if j.Total == 0 {
+		return 100
+	}
+	return j.Done * 100 / j.Total
+}
@@ -0,0 +1,80 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Finder lists things a page at a time.
+type Finder interface {
+	GetThings(ctx context.Context, offset, limit int, args map[string][]string) (Result, error)
+}
+
+const (
+	allThingsPageSize = 500
+	allThingsMaxPages = 100
+
+	maxConcurrentCalls = 8
+)
+
+// GetAllThings pages through f until every thing matching args has been fetched.
+func GetAllThings(ctx context.Context, f Finder, args map[string][]string) ([]Thing, error) {
+	things := []Thing{}
+
+	for page := range allThingsMaxPages {
+		result, err := f.GetThings(ctx, page*allThingsPageSize, allThingsPageSize, args)
+		if err != nil {
+			return nil, err
+		}
+
+		things = append(things, result.Things...)
+
+		if result.Count == 0 || len(result.Things) == 0 || len(things) >= result.TotalRecords {
+			break
+		}
+	}
+
+	return things, nil
+}
+
+// ForEach calls fn for every thing, or for every item of other lists such as devices, and waits until
+// all calls have returned. A few calls run at the same time, so that fetching the history of many
+// things does not flood the backend.
+func ForEach[T any](items []T, fn func(i int, item T)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentCalls)
+
+	for i, item := range items {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i, item)
+		})
+	}
+
+	wg.Wait()
+}
+
+// Collect calls fetch for every thing, a few at a time like ForEach, and returns the results in the
+// order of the things. When fetch fails for a thing its result is replaced by failed, so that reports
+// can show the things that are missing, and an error is only returned if fetch failed for all of them.
+func Collect[T any](things []Thing, fetch func(t Thing) (T, error), failed func(t Thing, err error) T) ([]T, error) {
+	results := make([]T, len(things))
+	errs := make([]error, len(things))
+
+	ForEach(things, func(i int, t Thing) {
+		result, err := fetch(t)
+		if err != nil {
+			result, errs[i] = failed(t, err), err
+		}
+		results[i] = result
+	})
+
+	if len(things) > 0 && !slices.ContainsFunc(errs, func(e error) bool { return e == nil }) {
+		return nil, errors.Join(errs...)
+	}
+
+	return results, nil
+}
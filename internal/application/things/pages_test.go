@@ -0,0 +1,31 @@
+package things
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestCollectKeepsFailedThingsUnlessAllFail(t *testing.T) {
+	is := is.New(t)
+
+	fetch := func(t Thing) (string, error) {
+		if t.ID == "broken" {
+			return "", errors.New("unavailable")
+		}
+		return t.ID, nil
+	}
+	failed := func(t Thing, _ error) string { return t.ID + " failed" }
+
+	results, err := Collect([]Thing{{ID: "a"}, {ID: "broken"}, {ID: "b"}}, fetch, failed)
+	is.NoErr(err)
+	is.Equal([]string{"a", "broken failed", "b"}, results)
+
+	_, err = Collect([]Thing{{ID: "broken"}}, fetch, failed)
+	is.True(err != nil)
+
+	results, err = Collect([]Thing{}, fetch, failed)
+	is.NoErr(err)
+	is.Equal(0, len(results))
+}
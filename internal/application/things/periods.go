@@ -0,0 +1,133 @@
+package things
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// OnOffStateURN identifies the on/off state of a stopwatch, which things such as combined sewer
+// overflows and pumping stations use to report when an overflow or a pump starts and stops.
+const OnOffStateURN = "3350/5850"
+
+// Getter fetches a single thing, including the measurements selected by params.
+type Getter interface {
+	GetThing(ctx context.Context, id string, params map[string][]string) (Thing, error)
+}
+
+// Period is a span of time, such as one during which a boolean state was on.
+type Period struct {
+	Start time.Time
+	End   time.Time
+	// Ongoing is set when the state was still on at the end of the requested interval.
+	Ongoing bool
+}
+
+func (p Period) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// OnPeriods turns a series of boolean state changes into the periods where the state was on.
+// Periods are clipped to the interval from - to. A leading off means that the state was already on
+// when the interval started, and a period that has not ended yet is closed at to and marked as ongoing.
+func OnPeriods(values []Measurement, from, to time.Time) []Period {
+	states := slices.DeleteFunc(slices.Clone(values), func(m Measurement) bool { return m.BoolValue == nil })
+	slices.SortStableFunc(states, func(a, b Measurement) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	periods := []Period{}
+	var current *Period
+
+	for i, m := range states {
+		on := *m.BoolValue
+		switch {
+		case on && current == nil:
+			current = &Period{Start: latest(m.Timestamp, from)}
+		case !on && current != nil:
+			current.End = m.Timestamp
+			periods = append(periods, *current)
+			current = nil
+		case !on && i == 0 && m.Timestamp.After(from):
+			periods = append(periods, Period{Start: from, End: m.Timestamp})
+		}
+	}
+
+	if current != nil {
+		end := to
+		if now := time.Now(); now.Before(end) {
+			end = now
+		}
+		current.End = latest(end, current.Start)
+		current.Ongoing = true
+		periods = append(periods, *current)
+	}
+
+	return periods
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return b
+	}
+	return a
+}
+
+// GetOnOffHistory fetches a thing together with the changes of its on/off state between from and to.
+func GetOnOffHistory(ctx context.Context, g Getter, thingID string, from, to time.Time) (Thing, error) {
+	query := url.Values{}
+	query.Add("timerel", "between")
+	query.Add("timeat", from.UTC().Format(time.RFC3339))
+	query.Add("endTimeAt", to.UTC().Format(time.RFC3339))
+	query.Add("n", OnOffStateURN)
+	query.Add("distinct", "vb")
+
+	return g.GetThing(ctx, thingID, query)
+}
+
+// StartOfDay returns the midnight that starts the day of t, in the time zone of t.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns the Monday that starts the week of t.
+func StartOfWeek(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// NextDay returns the start of the day that follows the one containing t.
+func NextDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1)
+}
+
+// NextWeek returns the Monday that follows the week containing t.
+func NextWeek(t time.Time) time.Time {
+	return StartOfWeek(t).AddDate(0, 0, 7)
+}
+
+// NextMonth returns the first day of the month that follows the one containing t.
+func NextMonth(t time.Time) time.Time {
+	return StartOfMonth(t).AddDate(0, 1, 0)
+}
+
+// Buckets splits from - to into consecutive periods, where next gives the start of the period that
+// follows the one containing t. The first period starts at from even if that is in the middle of a
+// day, a week or a month, and the last one is cut off at to. Days, weeks and months follow the time
+// zone of from.
+func Buckets(from, to time.Time, next func(time.Time) time.Time) []Period {
+	buckets := []Period{}
+	for start := from; start.Before(to); {
+		end := next(start)
+		if end.After(to) {
+			end = to
+		}
+		buckets = append(buckets, Period{Start: start, End: end})
+		start = end
+	}
+	return buckets
+}
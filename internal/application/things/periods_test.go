@@ -0,0 +1,74 @@
+package things
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestOnPeriods(t *testing.T) {
+	is := is.New(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	periods := OnPeriods([]Measurement{
+		state(from.Add(3*time.Hour), false),
+		state(from.Add(1*time.Hour), true),
+		state(from.Add(2*time.Hour), false),
+		state(from.Add(20*time.Hour), true),
+		{Timestamp: from.Add(21 * time.Hour)},
+	}, from, to)
+
+	is.Equal(2, len(periods))
+	is.Equal(time.Hour, periods[0].Duration())
+	is.True(!periods[0].Ongoing)
+	is.Equal(4*time.Hour, periods[1].Duration())
+	is.True(periods[1].Ongoing)
+}
+
+func TestOnPeriodsClipsPeriodThatStartedBeforeInterval(t *testing.T) {
+	is := is.New(t)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	periods := OnPeriods([]Measurement{
+		state(from.Add(30*time.Minute), false),
+		state(from.Add(time.Hour), false),
+	}, from, from.Add(24*time.Hour))
+
+	is.Equal(1, len(periods))
+	is.Equal(from, periods[0].Start)
+	is.Equal(30*time.Minute, periods[0].Duration())
+}
+
+func state(at time.Time, on bool) Measurement {
+	return Measurement{Timestamp: at, BoolValue: &on}
+}
+
+func TestBucketsFollowTheCalendar(t *testing.T) {
+	is := is.New(t)
+	loc, err := time.LoadLocation("Europe/Stockholm")
+	is.NoErr(err)
+
+	// from a Wednesday afternoon, over the change to summer time, to the first of April
+	from := time.Date(2024, 3, 27, 15, 0, 0, 0, loc)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, loc)
+
+	days := Buckets(from, to, NextDay)
+	is.Equal(5, len(days))
+	is.Equal(from, days[0].Start)
+	is.Equal(time.Date(2024, 3, 28, 0, 0, 0, 0, loc), days[1].Start)
+	is.Equal(23*time.Hour, days[4].Duration())
+
+	weeks := Buckets(from, to, NextWeek)
+	is.Equal(1, len(weeks))
+	is.Equal(to, weeks[0].End)
+
+	months := Buckets(from, to, NextMonth)
+	is.Equal(1, len(months))
+
+	is.Equal(time.Date(2024, 3, 25, 0, 0, 0, 0, loc), StartOfWeek(from))
+	is.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, loc), StartOfMonth(from))
+}
@@ -0,0 +1,52 @@
+// Package thingstest provides a fake of the things service for the tests of the services that read
+// things from it.
+package thingstest
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// Things is a fake of the things service. GetThings pages through All, keeping the things of the
+// types that are asked for, either as a type or as a type and a subtype such as
+// Container-WasteContainer. GetThing returns the thing with the id in ByID.
+type Things struct {
+	All  []things.Thing
+	ByID map[string]things.Thing
+}
+
+func (t Things) GetThings(_ context.Context, offset, limit int, params map[string][]string) (things.Result, error) {
+	all := t.All
+	if types := params["type"]; len(types) > 0 {
+		all = slices.DeleteFunc(slices.Clone(all), func(thing things.Thing) bool {
+			return !slices.ContainsFunc(types, func(thingType string) bool {
+				return strings.EqualFold(thingType, thing.Type) || strings.EqualFold(thingType, thing.Type+"-"+thing.SubType)
+			})
+		})
+	}
+
+	page := all[min(offset, len(all)):min(offset+limit, len(all))]
+	return things.Result{Things: page, TotalRecords: len(all), Count: len(page), Offset: offset, Limit: limit}, nil
+}
+
+// GetThing returns the thing with the id in ByID, whatever the params, or client.ErrNotFound.
+func (t Things) GetThing(_ context.Context, id string, _ map[string][]string) (things.Thing, error) {
+	thing, ok := t.ByID[id]
+	if !ok {
+		return things.Thing{}, client.ErrNotFound
+	}
+	return thing, nil
+}
+
+// History turns a series of measurements per thing into things for ByID.
+func History(values map[string][]things.Measurement) map[string]things.Thing {
+	byID := map[string]things.Thing{}
+	for id, series := range values {
+		byID[id] = things.Thing{ID: id, Values: [][]things.Measurement{series}}
+	}
+	return byID
+}
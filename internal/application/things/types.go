@@ -37,6 +37,10 @@ type Thing struct {
 	ObservedAt      time.Time       `json:"observedAt"`
 	ValidURNs       []string        `json:"validURN,omitempty"`
 
+	// Parent is the thing that this thing is part of, such as the building of a room. The relation is
+	// kept by diwise-web rather than by the things service, and is set by the hierarchy service.
+	Parent string `json:"-"`
+
 	Values     [][]Measurement `json:"-"`
 	TypeValues TypeValues      `json:"-"`
 }
@@ -0,0 +1,276 @@
+package views
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/views")
+
+const (
+	viewsCollection    = "views"
+	defaultsCollection = "defaultviews"
+)
+
+// paging parameters are never part of a saved view
+var ignoredParams = []string{"page", "offset", "limit"}
+
+type Service struct {
+	store storage.Store
+}
+
+func NewService(store storage.Store) *Service {
+	return &Service{store: store}
+}
+
+// GetViews returns the views for a page that the current user owns or that have been shared by others.
+func (s *Service) GetViews(ctx context.Context, page string) ([]View, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-views")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	documents, err := s.store.List(ctx, viewsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := authz.Subject(ctx)
+	defaultID := s.defaultViewID(ctx, page)
+
+	result := []View{}
+	for _, document := range documents {
+		var view View
+		if err = json.Unmarshal(document, &view); err != nil {
+			return nil, err
+		}
+
+		if view.Page != page || !(owns(subject, view) || view.Shared) {
+			continue
+		}
+
+		view.Default = view.ID == defaultID
+		result = append(result, view)
+	}
+
+	slices.SortFunc(result, func(a, b View) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return result, nil
+}
+
+func (s *Service) GetView(ctx context.Context, id string) (View, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-view")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	view, err := s.get(ctx, id)
+	if err != nil {
+		return View{}, err
+	}
+
+	if !owns(authz.Subject(ctx), view) && !view.Shared {
+		err = storage.ErrNotFound
+		return View{}, err
+	}
+
+	view.Default = view.ID == s.defaultViewID(ctx, view.Page)
+	return view, nil
+}
+
+// SaveView stores a new view owned by the current user, or updates an existing one if the ID is set.
+func (s *Service) SaveView(ctx context.Context, view View) (View, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "save-view")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" || view.Page == "" {
+		err = errors.New("a view needs both a name and a page")
+		return View{}, err
+	}
+
+	subject, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return View{}, err
+	}
+
+	if view.ID == "" {
+		view.ID = uuid.NewString()
+		view.CreatedAt = time.Now().UTC()
+	} else {
+		var existing View
+		existing, err = s.get(ctx, view.ID)
+		if err != nil {
+			return View{}, err
+		}
+		if existing.Owner != subject {
+			err = ErrForbidden
+			return View{}, err
+		}
+		view.CreatedAt = existing.CreatedAt
+	}
+
+	view.Owner = subject
+	view.Query, err = sanitizeQuery(view.Query)
+	if err != nil {
+		return View{}, err
+	}
+
+	b, err := json.Marshal(view)
+	if err != nil {
+		return View{}, err
+	}
+
+	err = s.store.Put(ctx, viewsCollection, view.ID, b)
+	if err != nil {
+		return View{}, err
+	}
+
+	return view, nil
+}
+
+func (s *Service) DeleteView(ctx context.Context, id string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "delete-view")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	subject, err := authz.RequireSubject(ctx)
+	if err != nil {
+		return err
+	}
+
+	view, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if view.Owner != subject {
+		err = ErrForbidden
+		return err
+	}
+
+	if s.defaultViewID(ctx, view.Page) == id {
+		_ = s.store.Delete(ctx, defaultsCollection, defaultKey(ctx, view.Page))
+	}
+
+	err = s.store.Delete(ctx, viewsCollection, id)
+	return err
+}
+
+// SetDefaultView marks a view as the current user's default for a page. An empty id clears the default.
+func (s *Service) SetDefaultView(ctx context.Context, page, id string) error {
+	var err error
+	ctx, span := tracer.Start(ctx, "set-default-view")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	if _, err = authz.RequireSubject(ctx); err != nil {
+		return err
+	}
+
+	if id == "" {
+		err = s.store.Delete(ctx, defaultsCollection, defaultKey(ctx, page))
+		if errors.Is(err, storage.ErrNotFound) {
+			err = nil
+		}
+		return err
+	}
+
+	view, err := s.GetView(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if view.Page != page {
+		err = fmt.Errorf("view %s does not belong to page %s", id, page)
+		return err
+	}
+
+	b, err := json.Marshal(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Put(ctx, defaultsCollection, defaultKey(ctx, page), b)
+	return err
+}
+
+// GetDefaultView returns the current user's default view for a page, or storage.ErrNotFound if none is set.
+func (s *Service) GetDefaultView(ctx context.Context, page string) (View, error) {
+	id := s.defaultViewID(ctx, page)
+	if id == "" {
+		return View{}, storage.ErrNotFound
+	}
+
+	return s.GetView(ctx, id)
+}
+
+func (s *Service) get(ctx context.Context, id string) (View, error) {
+	b, err := s.store.Get(ctx, viewsCollection, id)
+	if err != nil {
+		return View{}, err
+	}
+
+	var view View
+	err = json.Unmarshal(b, &view)
+	return view, err
+}
+
+func (s *Service) defaultViewID(ctx context.Context, page string) string {
+	if authz.Subject(ctx) == "" {
+		return ""
+	}
+
+	b, err := s.store.Get(ctx, defaultsCollection, defaultKey(ctx, page))
+	if err != nil {
+		return ""
+	}
+
+	var id string
+	if json.Unmarshal(b, &id) != nil {
+		return ""
+	}
+
+	return id
+}
+
+// owns reports if subject is the owner of view. A user without a subject owns no views.
+func owns(subject string, view View) bool {
+	return subject != "" && view.Owner == subject
+}
+
+func defaultKey(ctx context.Context, page string) string {
+	return authz.Subject(ctx) + "/" + page
+}
+
+func sanitizeQuery(query string) (string, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return "", fmt.Errorf("invalid view query: %w", err)
+	}
+
+	for _, param := range ignoredParams {
+		values.Del(param)
+	}
+
+	for key, value := range values {
+		if len(value) == 0 || (len(value) == 1 && value[0] == "") {
+			values.Del(key)
+		}
+	}
+
+	return values.Encode(), nil
+}
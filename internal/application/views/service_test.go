@@ -0,0 +1,105 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestSaveViewStripsPagingAndEmptyParams(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	ctx := withSubject("alice")
+
+	view, err := svc.SaveView(ctx, View{Name: " Full containers ", Page: "things", Query: "type=Container&tags=&page=3&limit=15"})
+	is.NoErr(err)
+
+	is.Equal("Full containers", view.Name)
+	is.Equal("type=Container", view.Query)
+	is.Equal("alice", view.Owner)
+}
+
+func TestGetViewsReturnsOwnAndSharedViews(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	_, err := svc.SaveView(withSubject("alice"), View{Name: "Private", Page: "sensors", Query: "online=false"})
+	is.NoErr(err)
+	_, err = svc.SaveView(withSubject("alice"), View{Name: "Shared", Page: "sensors", Query: "active=true", Shared: true})
+	is.NoErr(err)
+	_, err = svc.SaveView(withSubject("alice"), View{Name: "Other page", Page: "things", Shared: true})
+	is.NoErr(err)
+
+	views, err := svc.GetViews(withSubject("bob"), "sensors")
+	is.NoErr(err)
+	is.Equal(1, len(views))
+	is.Equal("Shared", views[0].Name)
+
+	views, err = svc.GetViews(withSubject("alice"), "sensors")
+	is.NoErr(err)
+	is.Equal(2, len(views))
+}
+
+func TestDefaultViewIsPerUser(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	view, err := svc.SaveView(withSubject("alice"), View{Name: "Shared", Page: "sensors", Query: "active=true", Shared: true})
+	is.NoErr(err)
+
+	is.NoErr(svc.SetDefaultView(withSubject("bob"), "sensors", view.ID))
+
+	def, err := svc.GetDefaultView(withSubject("bob"), "sensors")
+	is.NoErr(err)
+	is.Equal(view.ID, def.ID)
+	is.True(def.Default)
+
+	_, err = svc.GetDefaultView(withSubject("alice"), "sensors")
+	is.True(errors.Is(err, storage.ErrNotFound))
+}
+
+func TestDeleteViewRequiresOwner(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+
+	view, err := svc.SaveView(withSubject("alice"), View{Name: "Shared", Page: "things", Shared: true})
+	is.NoErr(err)
+
+	err = svc.DeleteView(withSubject("bob"), view.ID)
+	is.True(errors.Is(err, ErrForbidden))
+
+	is.NoErr(svc.DeleteView(withSubject("alice"), view.ID))
+}
+
+func TestViewsRequireASubject(t *testing.T) {
+	is := is.New(t)
+	svc := newTestService(t)
+	anonymous := context.Background()
+
+	_, err := svc.SaveView(anonymous, View{Name: "Mine", Page: "things"})
+	is.True(errors.Is(err, authz.ErrNoSubject))
+
+	view, err := svc.SaveView(withSubject("alice"), View{Name: "Private", Page: "things"})
+	is.NoErr(err)
+
+	_, err = svc.GetView(anonymous, view.ID)
+	is.True(errors.Is(err, storage.ErrNotFound))
+	is.True(errors.Is(svc.DeleteView(anonymous, view.ID), authz.ErrNoSubject))
+	is.True(errors.Is(svc.SetDefaultView(anonymous, "things", ""), authz.ErrNoSubject))
+}
+
+func newTestService(t *testing.T) *Service {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewService(store)
+}
+
+func withSubject(subject string) context.Context {
+	return context.WithValue(context.Background(), authz.SubjectClaim, subject)
+}
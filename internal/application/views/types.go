@@ -0,0 +1,32 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrForbidden = errors.New("view is owned by another user")
+
+type Management interface {
+	GetViews(ctx context.Context, page string) ([]View, error)
+	GetView(ctx context.Context, id string) (View, error)
+	SaveView(ctx context.Context, view View) (View, error)
+	DeleteView(ctx context.Context, id string) error
+	SetDefaultView(ctx context.Context, page, id string) error
+	GetDefaultView(ctx context.Context, page string) (View, error)
+}
+
+// View is a named set of list filters for a page such as sensors or things.
+type View struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Page      string    `json:"page"`
+	Query     string    `json:"query"`
+	Owner     string    `json:"owner"`
+	Shared    bool      `json:"shared"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Default is set when the view is the current user's default view for its page.
+	Default bool `json:"-"`
+}
@@ -0,0 +1,172 @@
+package watermeters
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+const (
+	// the night is when the consumption of a household or a business is normally close to nothing
+	nightStartHour = 2
+	nightEndHour   = 5
+	// maxNightDelta is the longest time between two readings that the night flow is derived from
+	maxNightDelta = (nightEndHour - nightStartHour) * time.Hour
+
+	// minLeakFlow is the lowest night flow in m³/h, 10 litres per hour, that counts as water running
+	minLeakFlow = 0.01
+	// leakNights is how many nights in a row the water has to keep running to be a probable leak
+	leakNights = 3
+)
+
+// Reading is the cumulative volume in m³ that a meter reported at a point in time.
+type Reading struct {
+	Timestamp time.Time
+	Volume    float64
+}
+
+type delta struct {
+	from, to time.Time
+	volume   float64
+}
+
+// Readings picks the cumulative volumes out of a series of measurements. Volumes reported in litres
+// are converted to m³.
+func Readings(values []things.Measurement) []Reading {
+	readings := make([]Reading, 0, len(values))
+	for _, m := range values {
+		if m.Value == nil {
+			continue
+		}
+		volume := *m.Value
+		if strings.EqualFold(m.Unit, "l") {
+			volume = volume / 1000
+		}
+		readings = append(readings, Reading{Timestamp: m.Timestamp, Volume: volume})
+	}
+	slices.SortFunc(readings, func(a, b Reading) int { return a.Timestamp.Compare(b.Timestamp) })
+	return readings
+}
+
+// Analyze derives the consumption between from and to from the cumulative readings of a meter. Each
+// change of the volume is counted when the later reading was made. When the volume goes backwards
+// the meter is assumed to have restarted from zero, so the new reading is the consumption since then.
+// Days, weeks and months follow the time zone of from.
+func Analyze(readings []Reading, from, to time.Time) Consumption {
+	c := Consumption{From: from, To: to}
+
+	deltas := []delta{}
+	for i := 1; i < len(readings); i++ {
+		prev, curr := readings[i-1], readings[i]
+		if curr.Timestamp.Before(from) || !curr.Timestamp.Before(to) {
+			continue
+		}
+
+		volume := curr.Volume - prev.Volume
+		if volume < 0 {
+			c.Resets++
+			volume = curr.Volume
+		}
+
+		deltas = append(deltas, delta{from: prev.Timestamp, to: curr.Timestamp, volume: volume})
+		c.Total += volume
+	}
+
+	c.Days = volumes(deltas, things.Buckets(from, to, things.NextDay))
+	c.Weeks = volumes(deltas, things.Buckets(from, to, things.NextWeek))
+	c.Months = volumes(deltas, things.Buckets(from, to, things.NextMonth))
+	c.Nights = nights(deltas, from, to)
+	c.ProbableLeak, c.LeakSince = probableLeak(c.Nights)
+
+	return c
+}
+
+// volumes sums the consumption into each of the periods.
+func volumes(deltas []delta, periods []things.Period) []Bucket {
+	result := []Bucket{}
+	for _, p := range periods {
+		b := Bucket{Start: p.Start}
+		for _, d := range deltas {
+			if !d.to.Before(p.Start) && d.to.Before(p.End) {
+				b.Volume += d.volume
+			}
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+// nights finds the lowest hourly flow during each night of the period. The volume between two
+// readings is assumed to have run evenly, so a delta that only partly overlaps an hour of the night
+// counts with the overlapping part. Deltas longer than the night are left out, as they cannot tell the
+// flow at night from the use during the day, and so are nights without any readings.
+func nights(deltas []delta, from, to time.Time) []Night {
+	result := []Night{}
+	for day := things.StartOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		y, m, d := day.Date()
+
+		found := false
+		night := Night{Date: day}
+		for hour := nightStartHour; hour < nightEndHour; hour++ {
+			slotStart := time.Date(y, m, d, hour, 0, 0, 0, day.Location())
+			flow, ok := slotFlow(deltas, slotStart, slotStart.Add(time.Hour))
+			if ok && (!found || flow < night.MinFlow) {
+				night.MinFlow = flow
+				found = true
+			}
+		}
+
+		if found {
+			result = append(result, night)
+		}
+	}
+	return result
+}
+
+// slotFlow prorates the deltas that overlap start - end and returns the flow in m³/h during the part
+// of the slot that they cover.
+func slotFlow(deltas []delta, start, end time.Time) (float64, bool) {
+	volume, covered := 0.0, time.Duration(0)
+	for _, dl := range deltas {
+		duration := dl.to.Sub(dl.from)
+		if duration <= 0 || duration > maxNightDelta {
+			continue
+		}
+
+		overlapStart, overlapEnd := dl.from, dl.to
+		if start.After(overlapStart) {
+			overlapStart = start
+		}
+		if end.Before(overlapEnd) {
+			overlapEnd = end
+		}
+
+		overlap := overlapEnd.Sub(overlapStart)
+		if overlap <= 0 {
+			continue
+		}
+
+		volume += dl.volume * overlap.Hours() / duration.Hours()
+		covered += overlap
+	}
+
+	if covered == 0 {
+		return 0, false
+	}
+	return volume / covered.Hours(), true
+}
+
+// probableLeak reports if the latest nights all had water running, and since which night.
+func probableLeak(nights []Night) (bool, *time.Time) {
+	streak := 0
+	for i := len(nights) - 1; i >= 0 && nights[i].MinFlow >= minLeakFlow; i-- {
+		streak++
+	}
+	if streak < leakNights {
+		return false, nil
+	}
+	since := nights[len(nights)-streak].Date
+	return true, &since
+}
@@ -0,0 +1,100 @@
+package watermeters
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+var start = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func TestAnalyzeSumsDeltasAndHandlesMeterResets(t *testing.T) {
+	is := is.New(t)
+
+	readings := []Reading{
+		{start.Add(-time.Hour), 100},
+		{start.Add(6 * time.Hour), 101.5},
+		{start.Add(30 * time.Hour), 103},
+		{start.Add(40 * time.Hour), 0.5},
+		{start.Add(50 * time.Hour), 1},
+	}
+
+	c := Analyze(readings, start, start.AddDate(0, 0, 3))
+
+	is.Equal(1, c.Resets)
+	is.Equal(4.0, c.Total)
+	is.Equal(3, len(c.Days))
+	is.Equal(1.5, c.Days[0].Volume)
+	is.Equal(2.0, c.Days[1].Volume)
+	is.Equal(0.5, c.Days[2].Volume)
+	is.Equal(1, len(c.Months))
+	is.Equal(4.0, c.Months[0].Volume)
+}
+
+func TestAnalyzeFlagsWaterRunningEveryNightAsProbableLeak(t *testing.T) {
+	is := is.New(t)
+
+	readings := []Reading{}
+	volume := 0.0
+	for hour := range 24 * 5 {
+		// 20 litres per hour around the clock from the second day
+		if hour >= 24 {
+			volume += 0.02
+		}
+		readings = append(readings, Reading{start.Add(time.Duration(hour) * time.Hour), volume})
+	}
+
+	c := Analyze(readings, start, start.AddDate(0, 0, 5))
+
+	is.Equal(5, len(c.Nights))
+	is.Equal(0.0, c.Nights[0].MinFlow)
+	is.True(c.ProbableLeak)
+	is.Equal(start.AddDate(0, 0, 1), *c.LeakSince)
+}
+
+func TestAnalyzeDoesNotFlagQuietNights(t *testing.T) {
+	is := is.New(t)
+
+	readings := []Reading{}
+	volume := 0.0
+	for hour := range 24 * 5 {
+		if hour%24 >= 7 && hour%24 <= 22 {
+			volume += 0.05
+		}
+		readings = append(readings, Reading{start.Add(time.Duration(hour) * time.Hour), volume})
+	}
+
+	c := Analyze(readings, start, start.AddDate(0, 0, 5))
+	is.True(!c.ProbableLeak)
+}
+
+func TestAnalyzeProratesReadingsThatOverlapTheNight(t *testing.T) {
+	is := is.New(t)
+
+	// 12 litres per hour, with readings every 170 minutes so that none of them is within a single night
+	readings := []Reading{}
+	for i := range 18 {
+		at := start.Add(time.Duration(i*170) * time.Minute)
+		readings = append(readings, Reading{at, float64(i) * 0.012 * 170 / 60})
+	}
+
+	c := Analyze(readings, start, start.AddDate(0, 0, 2))
+	is.Equal(2, len(c.Nights))
+	is.True(math.Abs(c.Nights[0].MinFlow-0.012) < 1e-9)
+
+	// a single reading a day says nothing about the night
+	daily := []Reading{{start, 0}, {start.AddDate(0, 0, 1), 1}, {start.AddDate(0, 0, 2), 2}}
+	is.Equal(0, len(Analyze(daily, start, start.AddDate(0, 0, 2)).Nights))
+}
+
+func TestReadingsConvertsLitres(t *testing.T) {
+	is := is.New(t)
+
+	v := 2500.0
+	readings := Readings([]things.Measurement{{Timestamp: start, Value: &v, Unit: "L"}, {Timestamp: start}})
+	is.Equal(1, len(readings))
+	is.Equal(2.5, readings[0].Volume)
+}
@@ -0,0 +1,114 @@
+package watermeters
+
+import (
+	"cmp"
+	"context"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("diwise-web/app/watermeters")
+
+const (
+	waterMeterType      = "WaterMeter"
+	cumulativeVolumeURN = "3424/1"
+)
+
+type thingSource interface {
+	things.Finder
+	things.Getter
+}
+
+type Service struct {
+	things thingSource
+}
+
+func NewService(things thingSource) *Service {
+	return &Service{things: things}
+}
+
+// GetConsumption derives the consumption of a water meter between from and to.
+func (s *Service) GetConsumption(ctx context.Context, thingID string, from, to time.Time) (Consumption, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-consumption")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	c, err := s.consumption(ctx, thingID, from, to)
+	return c, err
+}
+
+// GetWaterMeterFleet derives the consumption of every water meter between from and to, with the
+// meters that have raised the most flags first and then the ones that used the most water. A meter
+// without readings is failed but still shows the flags it reported in the list of things.
+func (s *Service) GetWaterMeterFleet(ctx context.Context, from, to time.Time) ([]Consumption, error) {
+	var err error
+	ctx, span := tracer.Start(ctx, "get-water-meter-fleet")
+	defer func() { tracing.RecordAnyErrorAndEndSpan(err, span) }()
+
+	meters, err := things.GetAllThings(ctx, s.things, map[string][]string{"type": {waterMeterType}})
+	if err != nil {
+		return nil, err
+	}
+
+	log := logging.GetFromContext(ctx)
+
+	result, err := things.Collect(meters, func(meter things.Thing) (Consumption, error) {
+		return s.consumption(ctx, meter.ID, from, to)
+	}, func(meter things.Thing, err error) Consumption {
+		log.Debug("could not fetch water meter history", "thing_id", meter.ID, "err", err.Error())
+		return Consumption{From: from, To: to, Flags: flags(meter.TypeValues), Failed: true}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, meter := range meters {
+		result[i].ThingID = meter.ID
+		result[i].Name = cmp.Or(meter.Name, meter.ID)
+		result[i].Tenant = meter.Tenant
+	}
+
+	slices.SortStableFunc(result, func(a, b Consumption) int {
+		return cmp.Or(cmp.Compare(b.ActiveFlags(), a.ActiveFlags()), cmp.Compare(b.Total, a.Total), cmp.Compare(a.Name, b.Name))
+	})
+
+	return result, nil
+}
+
+func (s *Service) consumption(ctx context.Context, thingID string, from, to time.Time) (Consumption, error) {
+	// the reading before the period is needed to know how much was used during its first hours
+	query := url.Values{}
+	query.Add("timerel", "between")
+	query.Add("timeat", from.Add(-24*time.Hour).UTC().Format(time.RFC3339))
+	query.Add("endTimeAt", to.UTC().Format(time.RFC3339))
+	query.Add("n", cumulativeVolumeURN)
+
+	thing, err := s.things.GetThing(ctx, thingID, query)
+	if err != nil {
+		return Consumption{}, err
+	}
+
+	c := Analyze(Readings(slices.Concat(thing.Values...)), from, to)
+	c.ThingID = thingID
+	c.Name = cmp.Or(thing.Name, thingID)
+	c.Tenant = thing.Tenant
+	c.Flags = flags(thing.TypeValues)
+
+	return c, nil
+}
+
+func flags(tv things.TypeValues) Flags {
+	isSet := func(b *bool) bool { return b != nil && *b }
+	return Flags{
+		Leakage:  isSet(tv.Leakage),
+		Burst:    isSet(tv.Burst),
+		Backflow: isSet(tv.Backflow),
+		Fraud:    isSet(tv.Fraud),
+	}
+}
@@ -0,0 +1,47 @@
+package watermeters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/things/thingstest"
+	"github.com/matryer/is"
+)
+
+func TestGetWaterMeterFleetRanksFlagsBeforeConsumption(t *testing.T) {
+	is := is.New(t)
+
+	leakage := true
+	svc := NewService(thingstest.Things{
+		All: []things.Thing{
+			{ID: "small", Name: "Small", Type: waterMeterType},
+			{ID: "large", Name: "Large", Type: waterMeterType},
+			{ID: "flagged", Name: "Flagged", Type: waterMeterType, TypeValues: things.TypeValues{Leakage: &leakage}},
+		},
+		ByID: thingstest.History(map[string][]things.Measurement{
+			"small": hourly(10, 11),
+			"large": hourly(10, 30),
+		}),
+	})
+
+	fleet, err := svc.GetWaterMeterFleet(context.Background(), start, start.AddDate(0, 0, 1))
+	is.NoErr(err)
+	is.Equal(3, len(fleet))
+	is.Equal("flagged", fleet[0].ThingID)
+	is.True(fleet[0].Failed)
+	is.True(fleet[0].Flags.Leakage)
+	is.Equal("large", fleet[1].ThingID)
+	is.Equal(20.0, fleet[1].Total)
+	is.Equal("small", fleet[2].ThingID)
+}
+
+// hourly returns the cumulative volumes of a meter, one every hour from the start.
+func hourly(volumes ...float64) []things.Measurement {
+	values := []things.Measurement{}
+	for i, v := range volumes {
+		values = append(values, things.Measurement{Timestamp: start.Add(time.Duration(i) * time.Hour), Value: &v, Unit: "m3"})
+	}
+	return values
+}
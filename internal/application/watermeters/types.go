@@ -0,0 +1,86 @@
+package watermeters
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultDays is how many days, including today, the consumption of a meter covers unless another period is asked for.
+const DefaultDays = 30
+
+type Management interface {
+	GetConsumption(ctx context.Context, thingID string, from, to time.Time) (Consumption, error)
+	GetWaterMeterFleet(ctx context.Context, from, to time.Time) ([]Consumption, error)
+}
+
+// Bucket holds the consumption in m³ during a day, a week or a month.
+type Bucket struct {
+	Start  time.Time
+	Volume float64
+}
+
+// Night holds the lowest flow in m³/h that was measured during the night that starts at Date.
+type Night struct {
+	Date    time.Time
+	MinFlow float64
+}
+
+// Flags are the alarms that the meter itself reports.
+type Flags struct {
+	Leakage  bool
+	Burst    bool
+	Backflow bool
+	Fraud    bool
+}
+
+// Names lists the raised flags by the names the meter uses for them.
+func (f Flags) Names() []string {
+	names := []string{}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{{"backflow", f.Backflow}, {"burst", f.Burst}, {"fraud", f.Fraud}, {"leakage", f.Leakage}} {
+		if flag.set {
+			names = append(names, flag.name)
+		}
+	}
+	return names
+}
+
+// Consumption is the water use of a meter between From and To, derived from the changes of the
+// cumulative volume that the meter reports.
+type Consumption struct {
+	ThingID string
+	Name    string
+	Tenant  string
+	From    time.Time
+	To      time.Time
+
+	Total  float64
+	Days   []Bucket
+	Weeks  []Bucket
+	Months []Bucket
+	Nights []Night
+	// Resets counts how many times the cumulative volume went backwards, such as when the meter was replaced.
+	Resets int
+
+	Flags Flags
+	// ProbableLeak is set when water has kept flowing through the night for several nights in a row,
+	// which usually means a leak even before the meter raises its own leakage flag.
+	ProbableLeak bool
+	LeakSince    *time.Time
+
+	// Failed is set when the history of the meter could not be fetched.
+	Failed bool
+}
+
+// ActiveFlags counts the flags raised by the meter, including a probable leak from the night flow.
+func (c Consumption) ActiveFlags() int {
+	count := 0
+	for _, set := range []bool{c.Flags.Leakage, c.Flags.Burst, c.Flags.Backflow, c.Flags.Fraud, c.ProbableLeak} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
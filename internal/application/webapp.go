@@ -12,12 +12,32 @@ import (
 
 	"github.com/diwise/diwise-web/internal/application/admin"
 	"github.com/diwise/diwise-web/internal/application/alarms"
+	"github.com/diwise/diwise-web/internal/application/attachments"
 	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/collection"
 	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/hierarchy"
+	"github.com/diwise/diwise-web/internal/application/history"
 	"github.com/diwise/diwise-web/internal/application/measurements"
+	"github.com/diwise/diwise-web/internal/application/notes"
+	"github.com/diwise/diwise-web/internal/application/occupancy"
+	"github.com/diwise/diwise-web/internal/application/overflows"
+	"github.com/diwise/diwise-web/internal/application/passages"
+	"github.com/diwise/diwise-web/internal/application/preferences"
+	"github.com/diwise/diwise-web/internal/application/pumping"
+	"github.com/diwise/diwise-web/internal/application/quality"
+	"github.com/diwise/diwise-web/internal/application/readiness"
+	"github.com/diwise/diwise-web/internal/application/rules"
+	"github.com/diwise/diwise-web/internal/application/sandstorage"
+	"github.com/diwise/diwise-web/internal/application/search"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/tags"
 	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/application/views"
+	"github.com/diwise/diwise-web/internal/application/watermeters"
 	"github.com/diwise/diwise-web/internal/presentation/api/authz"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
 	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/tracing"
 	"go.opentelemetry.io/otel"
 )
@@ -31,19 +51,66 @@ type App struct {
 	devices      *devices.Service
 	measurements *measurements.Service
 	things       *things.Service
+	views        *views.Service
+	search       *search.Service
+	preferences  *preferences.Service
+	collection   *collection.Service
+	overflows    *overflows.Service
+	pumping      *pumping.Service
+	watermeters  *watermeters.Service
+	passages     *passages.Service
+	occupancy    *occupancy.Service
+	readiness    *readiness.Service
+	sandstorage  *sandstorage.Service
+	rules        *rules.Service
+	quality      *quality.Service
+	hierarchy    *hierarchy.Service
+	tags         *tags.Service
+	notes        *notes.Service
+	attachments  *attachments.Service
+	history      *history.Service
 }
 
-func New(ctx context.Context, devmgmt, thingsURL, adminURL, alarmsURL, measurementURL string) (*App, error) {
+func New(ctx context.Context, devmgmt, thingsURL, adminURL, alarmsURL, measurementURL string, store storage.Store, blobs storage.BlobStore) (*App, error) {
 	_ = ctx
 	client := client.NewClient(devmgmt, thingsURL, adminURL, alarmsURL, measurementURL)
-	return &App{
+	app := &App{
 		client:       client,
 		admin:        admin.NewService(client),
 		alarms:       alarms.NewService(client),
 		devices:      devices.NewService(client),
 		measurements: measurements.NewService(client),
 		things:       things.NewService(client),
-	}, nil
+		views:        views.NewService(store),
+		preferences:  preferences.NewService(store),
+		notes:        notes.NewService(store),
+		attachments:  attachments.NewService(store, blobs),
+	}
+	app.history = history.NewService(app.things, store)
+	app.search = search.NewService(app.devices, app.things, app.alarms)
+	app.collection = collection.NewService(app.things)
+	app.overflows = overflows.NewService(app.things)
+	app.pumping = pumping.NewService(app.things)
+	app.watermeters = watermeters.NewService(app.things)
+	app.passages = passages.NewService(app.things)
+	app.occupancy = occupancy.NewService(app.things)
+	app.readiness = readiness.NewService(app.things)
+	app.sandstorage = sandstorage.NewService(app.things, store)
+	app.rules = rules.NewService(app.things, store)
+	app.quality = quality.NewService(app.devices, app.measurements, store)
+	app.hierarchy = hierarchy.NewService(app.things, store)
+	app.tags = tags.NewService(trackedThings{Service: app.things, history: app.history}, app.admin, store)
+	return app, nil
+}
+
+// trackedThings records the changes that other services, such as renaming a tag, make to things.
+type trackedThings struct {
+	*things.Service
+	history *history.Service
+}
+
+func (t trackedThings) UpdateThing(ctx context.Context, thingID string, fields map[string]any) error {
+	return t.history.UpdateThing(ctx, thingID, fields)
 }
 
 func WithReverse(reverse bool) client.InputParam { return client.WithReverse(reverse) }
@@ -114,7 +181,10 @@ func (a *App) GetAlarms(ctx context.Context, offset, limit int, args map[string]
 }
 
 func (a *App) NewThing(ctx context.Context, t things.Thing) error {
-	return a.things.NewThing(ctx, t)
+	if err := a.history.NewThing(ctx, t); err != nil {
+		return err
+	}
+	return a.tags.Restore(ctx, t.Tags)
 }
 
 func (a *App) GetThing(ctx context.Context, id string, params map[string][]string) (things.Thing, error) {
@@ -130,7 +200,13 @@ func (a *App) GetThings(ctx context.Context, offset, limit int, params map[strin
 }
 
 func (a *App) UpdateThing(ctx context.Context, thingID string, fields map[string]any) error {
-	return a.things.UpdateThing(ctx, thingID, fields)
+	if err := a.history.UpdateThing(ctx, thingID, fields); err != nil {
+		return err
+	}
+	if t, ok := fields["tags"].([]string); ok {
+		return a.tags.Restore(ctx, t)
+	}
+	return nil
 }
 
 func (a *App) DeleteThing(ctx context.Context, thingID string) error {
@@ -138,7 +214,23 @@ func (a *App) DeleteThing(ctx context.Context, thingID string) error {
 }
 
 func (a *App) GetTags(ctx context.Context) ([]string, error) {
-	return a.things.GetTags(ctx)
+	return a.tags.GetTags(ctx)
+}
+
+func (a *App) GetTagUsage(ctx context.Context) ([]tags.Usage, error) {
+	return a.tags.GetTagUsage(ctx)
+}
+
+func (a *App) RenameTag(ctx context.Context, from, to string) (tags.Job, error) {
+	return a.tags.RenameTag(ctx, from, to)
+}
+
+func (a *App) GetTagJob(ctx context.Context, id string) (tags.Job, error) {
+	return a.tags.GetTagJob(ctx, id)
+}
+
+func (a *App) DeleteTag(ctx context.Context, tag string) error {
+	return a.tags.DeleteTag(ctx, tag)
 }
 
 func (a *App) GetTypes(ctx context.Context) ([]string, error) {
@@ -150,7 +242,207 @@ func (a *App) GetValidSensors(ctx context.Context, urns []string, search string)
 }
 
 func (a *App) ConnectSensor(ctx context.Context, thingID string, refDevices []string) error {
-	return a.things.ConnectSensor(ctx, thingID, refDevices)
+	return a.history.ConnectSensor(ctx, thingID, refDevices)
+}
+
+func (a *App) GetHistory(ctx context.Context, thingID string) ([]history.Entry, error) {
+	return a.history.GetHistory(ctx, thingID)
+}
+
+func (a *App) GetViews(ctx context.Context, page string) ([]views.View, error) {
+	return a.views.GetViews(ctx, page)
+}
+
+func (a *App) GetView(ctx context.Context, id string) (views.View, error) {
+	return a.views.GetView(ctx, id)
+}
+
+func (a *App) SaveView(ctx context.Context, view views.View) (views.View, error) {
+	return a.views.SaveView(ctx, view)
+}
+
+func (a *App) DeleteView(ctx context.Context, id string) error {
+	return a.views.DeleteView(ctx, id)
+}
+
+func (a *App) SetDefaultView(ctx context.Context, page, id string) error {
+	return a.views.SetDefaultView(ctx, page, id)
+}
+
+func (a *App) GetDefaultView(ctx context.Context, page string) (views.View, error) {
+	return a.views.GetDefaultView(ctx, page)
+}
+
+func (a *App) Search(ctx context.Context, query string, limit int) (search.Result, error) {
+	return a.search.Search(ctx, query, limit)
+}
+
+func (a *App) GetPreferences(ctx context.Context) (preferences.Preferences, error) {
+	return a.preferences.GetPreferences(ctx)
+}
+
+func (a *App) SavePreferences(ctx context.Context, p preferences.Preferences) (preferences.Preferences, error) {
+	return a.preferences.SavePreferences(ctx, p)
+}
+
+func (a *App) PlanCollectionRun(ctx context.Context, criteria collection.Criteria) (collection.Run, error) {
+	return a.collection.PlanCollectionRun(ctx, criteria)
+}
+
+func (a *App) GetOverflowEvents(ctx context.Context, thingID string, from, to time.Time) ([]overflows.Event, error) {
+	return a.overflows.GetOverflowEvents(ctx, thingID, from, to)
+}
+
+func (a *App) GetOverflowReport(ctx context.Context, from, to time.Time) (overflows.Report, error) {
+	return a.overflows.GetOverflowReport(ctx, from, to)
+}
+
+func (a *App) GetPumpingStats(ctx context.Context, thingID string, from, to time.Time) (pumping.Stats, error) {
+	return a.pumping.GetPumpingStats(ctx, thingID, from, to)
+}
+
+func (a *App) GetPumpingComparison(ctx context.Context, from, to time.Time) ([]pumping.Stats, error) {
+	return a.pumping.GetPumpingComparison(ctx, from, to)
+}
+
+func (a *App) GetConsumption(ctx context.Context, thingID string, from, to time.Time) (watermeters.Consumption, error) {
+	return a.watermeters.GetConsumption(ctx, thingID, from, to)
+}
+
+func (a *App) GetWaterMeterFleet(ctx context.Context, from, to time.Time) ([]watermeters.Consumption, error) {
+	return a.watermeters.GetWaterMeterFleet(ctx, from, to)
+}
+
+func (a *App) GetPassageStatistics(ctx context.Context, thingID string, from, to time.Time) (passages.Statistics, error) {
+	return a.passages.GetPassageStatistics(ctx, thingID, from, to)
+}
+
+func (a *App) GetOccupancy(ctx context.Context, thingID string, from, to time.Time) (occupancy.Space, error) {
+	return a.occupancy.GetOccupancy(ctx, thingID, from, to)
+}
+
+func (a *App) GetOccupancyReport(ctx context.Context, from, to time.Time) (occupancy.Report, error) {
+	return a.occupancy.GetOccupancyReport(ctx, from, to)
+}
+
+func (a *App) GetReadinessBoard(ctx context.Context) (readiness.Board, error) {
+	return a.readiness.GetReadinessBoard(ctx)
+}
+
+func (a *App) GetSandStorage(ctx context.Context, thingID string) (sandstorage.Storage, error) {
+	return a.sandstorage.GetSandStorage(ctx, thingID)
+}
+
+func (a *App) SetFillAggregation(ctx context.Context, thingID, aggregation string) error {
+	return a.sandstorage.SetFillAggregation(ctx, thingID, aggregation)
+}
+
+func (a *App) GetRefills(ctx context.Context, thingID string, from, to time.Time) ([]sandstorage.Refill, error) {
+	return a.sandstorage.GetRefills(ctx, thingID, from, to)
+}
+
+func (a *App) GetWinterReadiness(ctx context.Context, tenant string, from, to time.Time) (sandstorage.Report, error) {
+	return a.sandstorage.GetWinterReadiness(ctx, tenant, from, to)
+}
+
+func (a *App) GetRules(ctx context.Context) ([]rules.Rule, error) {
+	return a.rules.GetRules(ctx)
+}
+
+func (a *App) SaveRule(ctx context.Context, rule rules.Rule) (rules.Rule, error) {
+	return a.rules.SaveRule(ctx, rule)
+}
+
+func (a *App) DeleteRule(ctx context.Context, id string) error {
+	return a.rules.DeleteRule(ctx, id)
+}
+
+func (a *App) GetAlerts(ctx context.Context) ([]rules.Alert, error) {
+	return a.rules.GetAlerts(ctx)
+}
+
+// RunRuleEvaluator raises and clears the alerts of the rules at every interval until ctx is done.
+func (a *App) RunRuleEvaluator(ctx context.Context, interval time.Duration, authenticate func(context.Context) (context.Context, error)) {
+	runEvery(ctx, interval, authenticate, "rule evaluator", a.rules.EvaluateRules)
+}
+
+func (a *App) GetDataQualityReport(ctx context.Context, tenant string, tenants []string) (quality.Report, error) {
+	return a.quality.GetDataQualityReport(ctx, tenant, tenants)
+}
+
+func (a *App) GetSuspectDevices(ctx context.Context) ([]string, error) {
+	return a.quality.GetSuspectDevices(ctx)
+}
+
+// RunDataQualityChecker looks for gaps, stuck values and outliers in the measurements of the
+// devices at every interval until ctx is done.
+func (a *App) RunDataQualityChecker(ctx context.Context, interval time.Duration, authenticate func(context.Context) (context.Context, error)) {
+	runEvery(ctx, interval, authenticate, "data quality checker", a.quality.CheckDataQuality)
+}
+
+// runEvery runs a background job at every interval until ctx is done. The jobs have no user of their
+// own, so authenticate adds the credentials that the backend calls are made with.
+func runEvery(ctx context.Context, interval time.Duration, authenticate func(context.Context) (context.Context, error), job string, run func(context.Context) error) {
+	log := logging.GetFromContext(ctx).With("job", job)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			callCtx, err := authenticate(ctx)
+			if err != nil {
+				log.Error("could not authenticate background job", "err", err.Error())
+				continue
+			}
+			if err := run(callCtx); err != nil {
+				log.Error("background job failed", "err", err.Error())
+			}
+		}
+	}
+}
+
+func (a *App) GetHierarchy(ctx context.Context, thingID string) (hierarchy.Hierarchy, error) {
+	return a.hierarchy.GetHierarchy(ctx, thingID)
+}
+
+func (a *App) GetParentCandidates(ctx context.Context, thingID string) ([]things.Thing, error) {
+	return a.hierarchy.GetParentCandidates(ctx, thingID)
+}
+
+func (a *App) SetParent(ctx context.Context, thingID, parentID string) error {
+	return a.hierarchy.SetParent(ctx, thingID, parentID)
+}
+
+func (a *App) GetNotes(ctx context.Context, kind, subjectID string) ([]notes.Note, error) {
+	return a.notes.GetNotes(ctx, kind, subjectID)
+}
+
+func (a *App) AddNote(ctx context.Context, note notes.Note) (notes.Note, error) {
+	return a.notes.AddNote(ctx, note)
+}
+
+func (a *App) DeleteNote(ctx context.Context, kind, subjectID, id string) error {
+	return a.notes.DeleteNote(ctx, kind, subjectID, id)
+}
+
+func (a *App) GetAttachments(ctx context.Context, kind, subjectID string) ([]attachments.Attachment, error) {
+	return a.attachments.GetAttachments(ctx, kind, subjectID)
+}
+
+func (a *App) AddAttachment(ctx context.Context, kind, subjectID, name string, data []byte) (attachments.Attachment, error) {
+	return a.attachments.AddAttachment(ctx, kind, subjectID, name, data)
+}
+
+func (a *App) GetAttachmentFile(ctx context.Context, kind, subjectID, id string, thumbnail bool) (attachments.Attachment, storage.Blob, error) {
+	return a.attachments.GetAttachmentFile(ctx, kind, subjectID, id, thumbnail)
+}
+
+func (a *App) DeleteAttachment(ctx context.Context, kind, subjectID, id string) error {
+	return a.attachments.DeleteAttachment(ctx, kind, subjectID, id)
 }
 
 func (a *App) Export(ctx context.Context, params url.Values) ([]byte, error) {
@@ -12,11 +12,19 @@ import (
 	"time"
 
 	"github.com/diwise/diwise-web/internal/application"
+	"github.com/diwise/diwise-web/internal/application/geo"
 	"github.com/diwise/diwise-web/internal/presentation/api/authz"
 	"github.com/diwise/diwise-web/internal/presentation/api/handlers/admin"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/attachments"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/collection"
 	"github.com/diwise/diwise-web/internal/presentation/api/handlers/home"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/notes"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/reports"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/search"
 	"github.com/diwise/diwise-web/internal/presentation/api/handlers/sensors"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/settings"
 	"github.com/diwise/diwise-web/internal/presentation/api/handlers/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/views"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	webutils "github.com/diwise/diwise-web/internal/presentation/web/utils"
 
@@ -233,12 +241,72 @@ func RegisterHandlers(ctx context.Context, mux *http.ServeMux, middleware []func
 	r.HandleFunc("GET /things/{id}", things.NewThingDetailsPage(ctx, l10n, assetLoader.Load, app))
 	r.HandleFunc("POST /things/{id}", things.NewSaveThingDetailsPage(ctx, l10n, assetLoader.Load, app))
 	r.HandleFunc("POST /things/{id}/delete", things.NewDeleteThingDetailsPage(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("GET /things/{id}/passages/export", things.NewThingPassagesExportHandler(ctx, l10n, assetLoader.Load, app))
 	r.Handle("GET /components/things/new", RequireHX(things.NewThingComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/position", RequireHX(things.NewPositionComponentHandler(ctx, l10n, assetLoader.Load, app)))
 	r.Handle("GET /components/things/{id}/measurements", RequireHX(things.NewThingMeasurementComponentHandler(ctx, l10n, assetLoader.Load, app)))
 	r.Handle("GET /components/things/search-compatible-sensor-options", RequireHX(things.NewCompatibleSensorSearchOptionsHandler(ctx, l10n, assetLoader.Load, app)))
 	r.Handle("GET /components/things/list", RequireHX(things.NewThingsDataList(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/overflows", RequireHX(things.NewThingOverflowEventsComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/pumping", RequireHX(things.NewThingPumpingComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/consumption", RequireHX(things.NewThingConsumptionComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/passages", RequireHX(things.NewThingPassagesComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/occupancy", RequireHX(things.NewThingOccupancyComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/hierarchy", RequireHX(things.NewThingHierarchyComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/things/{id}/parent", RequireHX(things.NewThingParentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/history", RequireHX(things.NewThingHistoryComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/sandstorage", RequireHX(things.NewThingSandStorageComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/things/{id}/sandstorage/fill", RequireHX(things.NewThingSandStorageFillComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/things/{id}/sandstorage/aggregation", RequireHX(things.NewThingSandStorageAggregationHandler(ctx, l10n, assetLoader.Load, app)))
+
+	r.HandleFunc("GET /collection", collection.NewCollectionRunPage(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("GET /collection/export", collection.NewCollectionRunExportHandler(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/collection/run", RequireHX(collection.NewCollectionRunComponentHandler(ctx, l10n, assetLoader.Load, app)))
+
+	r.HandleFunc("GET /reports", reports.NewReportsPage(ctx, l10n, assetLoader.Load))
+	r.HandleFunc("GET /reports/overflows", reports.NewOverflowReportPage(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("GET /reports/overflows/export", reports.NewOverflowReportExportHandler(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/reports/overflows", RequireHX(reports.NewOverflowReportComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.HandleFunc("GET /reports/pumping", reports.NewPumpingReportPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/reports/pumping", RequireHX(reports.NewPumpingReportComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.HandleFunc("GET /reports/water", reports.NewWaterMeterReportPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/reports/water", RequireHX(reports.NewWaterMeterReportComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.HandleFunc("GET /reports/occupancy", reports.NewOccupancyReportPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/reports/occupancy", RequireHX(reports.NewOccupancyReportComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.HandleFunc("GET /reports/readiness", reports.NewReadinessBoardPage(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("GET /reports/readiness/checklist", reports.NewReadinessChecklistPage(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("GET /reports/winter", reports.NewWinterReadinessPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/reports/winter", RequireHX(reports.NewWinterReadinessComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.HandleFunc("GET /reports/dataquality", reports.NewDataQualityPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/reports/dataquality", RequireHX(reports.NewDataQualityComponentHandler(ctx, l10n, assetLoader.Load, app)))
+
+	r.Handle("GET /components/search", RequireHX(search.NewSearchResultsHandler(ctx, l10n, assetLoader.Load, app)))
+
+	r.Handle("GET /components/notes/{kind}/{id}", RequireHX(notes.NewNotesComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/notes/{kind}/{id}", RequireHX(notes.NewAddNoteHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/notes/{kind}/{id}/{note}/delete", RequireHX(notes.NewDeleteNoteHandler(ctx, l10n, assetLoader.Load, app)))
+
+	r.HandleFunc("GET /attachments/{kind}/{id}/{attachment}", attachments.NewAttachmentFileHandler(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/attachments/{kind}/{id}", RequireHX(attachments.NewAttachmentsComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/attachments/{kind}/{id}", RequireHX(attachments.NewUploadAttachmentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/attachments/{kind}/{id}/{attachment}/delete", RequireHX(attachments.NewDeleteAttachmentHandler(ctx, l10n, assetLoader.Load, app)))
+
+	r.HandleFunc("POST /views", views.NewSaveViewHandler(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("POST /views/{id}/delete", views.NewDeleteViewHandler(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("POST /views/{id}/default", views.NewDefaultViewHandler(ctx, l10n, assetLoader.Load, app))
+
+	r.HandleFunc("GET /settings", settings.NewSettingsPage(ctx, l10n, assetLoader.Load, app))
+	r.HandleFunc("POST /settings", settings.NewSaveSettingsHandler(ctx, l10n, assetLoader.Load, app))
 
 	r.Handle("GET /admin", admin.NewAdminPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/admin/rules", RequireHX(admin.NewRulesComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/admin/rules", RequireHX(admin.NewSaveRuleHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/admin/rules/{id}/delete", RequireHX(admin.NewDeleteRuleHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /admin/tags", admin.NewTagsPage(ctx, l10n, assetLoader.Load, app))
+	r.Handle("GET /components/admin/tags", RequireHX(admin.NewTagsComponentHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/admin/tags/rename", RequireHX(admin.NewRenameTagHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("GET /components/admin/tags/jobs/{id}", RequireHX(admin.NewTagJobHandler(ctx, l10n, assetLoader.Load, app)))
+	r.Handle("POST /components/admin/tags/delete", RequireHX(admin.NewDeleteTagHandler(ctx, l10n, assetLoader.Load, app)))
 
 	r.HandleFunc("GET /admin/export", func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query()
@@ -269,6 +337,14 @@ func RegisterHandlers(ctx context.Context, mux *http.ServeMux, middleware []func
 			return
 		}
 
+		// the grid coordinates are an extra, so the export is sent without them if they cannot be added
+		projections := geo.ExportProjections(helpers.GetUserPreferences(r.Context()).CoordinateSystem)
+		if withGrid, err := geo.AddGridCoordinates(b, query.Get("accept"), projections); err == nil {
+			b = withGrid
+		} else {
+			logging.GetFromContext(r.Context()).Warn("could not add grid coordinates to export", "accept", query.Get("accept"), "err", err.Error())
+		}
+
 		w.Header().Set("Content-Type", query.Get("accept"))
 		w.WriteHeader(http.StatusOK)
 		w.Write(b)
@@ -372,7 +448,7 @@ func RegisterHandlers(ctx context.Context, mux *http.ServeMux, middleware []func
 		),
 	)
 
-	var handler http.Handler = r
+	var handler http.Handler = settings.NewUserPreferencesMiddleware(ctx, app)(r)
 
 	// wrap the mux with any passed in middleware handlers
 	for _, mw := range slices.Backward(middleware) {
@@ -2,15 +2,29 @@ package authz
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 )
 
 type loggedInKey string
 type tokenKey string
+type subjectKey string
+type nameKey string
+type scopeKey string
 
 const AuthToken tokenKey = "jwt-token"
 const LoggedIn loggedInKey = "logged-in"
+const SubjectClaim subjectKey = "subject"
+const NameClaim nameKey = "name"
+const ScopeClaim scopeKey = "scope"
+
+// AdminScope is the scope of the users that may manage what applies to every tenant, such as the
+// threshold rules without a tenant and the tags of the things.
+const AdminScope = "admin"
 
 func NewContextFromAuthorizationHeader(ctx context.Context, r *http.Request) (context.Context, error) {
 	var found bool
@@ -22,6 +36,10 @@ func NewContextFromAuthorizationHeader(ctx context.Context, r *http.Request) (co
 	if authHeader != "" {
 		ctx = context.WithValue(ctx, LoggedIn, "yes")
 		ctx = context.WithValue(ctx, AuthToken, authHeader)
+		c := claimsFromToken(authHeader)
+		ctx = context.WithValue(ctx, SubjectClaim, c.Subject)
+		ctx = context.WithValue(ctx, NameClaim, c.name())
+		ctx = context.WithValue(ctx, ScopeClaim, c.Scope)
 	}
 
 	return ctx, nil
@@ -37,6 +55,12 @@ func Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// WithToken returns a context that calls the backend with token, for work that is not done on
+// behalf of a logged in user.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, AuthToken, token)
+}
+
 func IsLoggedIn(ctx context.Context) bool {
 	if value, ok := ctx.Value(LoggedIn).(string); ok {
 		return value == "yes"
@@ -51,3 +75,78 @@ func Token(ctx context.Context) string {
 
 	return ""
 }
+
+// Subject returns the subject claim of the logged in user, or an empty string if it is unknown.
+func Subject(ctx context.Context) string {
+	if subject, ok := ctx.Value(SubjectClaim).(string); ok {
+		return subject
+	}
+
+	return ""
+}
+
+// ErrNoSubject is returned when data that is kept per user is changed without a known user.
+var ErrNoSubject = errors.New("the request has no subject claim")
+
+// RequireSubject returns the subject claim of the logged in user, or ErrNoSubject if it is unknown, so
+// that an empty subject is never used as the key of data that belongs to a user.
+func RequireSubject(ctx context.Context) (string, error) {
+	subject := Subject(ctx)
+	if subject == "" {
+		return "", ErrNoSubject
+	}
+
+	return subject, nil
+}
+
+// Name returns the name of the logged in user as shown to others, falling back to the user name and
+// then to the subject claim when the token carries no name.
+func Name(ctx context.Context) string {
+	if name, ok := ctx.Value(NameClaim).(string); ok && name != "" {
+		return name
+	}
+
+	return Subject(ctx)
+}
+
+// HasScope reports if the space separated scope claim of the logged in user contains scope.
+func HasScope(ctx context.Context, scope string) bool {
+	granted, _ := ctx.Value(ScopeClaim).(string)
+	return slices.Contains(strings.Fields(granted), scope)
+}
+
+type claims struct {
+	Subject           string `json:"sub"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Scope             string `json:"scope"`
+}
+
+func (c claims) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.PreferredUsername
+}
+
+// claimsFromToken reads the claims that identify the user from a JWT. The signature is not verified
+// here since the token has already been validated by the token exchange before it reaches the
+// application.
+func claimsFromToken(token string) claims {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims{}
+	}
+
+	c := claims{}
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return claims{}
+	}
+
+	return c
+}
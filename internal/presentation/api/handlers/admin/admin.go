@@ -23,7 +23,7 @@ func NewAdminPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc
 			v2layout.CurrentComponent, "admin",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		model := featureadmin.AdminViewModel{
 			Token: authz.Token(ctx),
 		}
@@ -0,0 +1,207 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/diwise/diwise-web/internal/application/rules"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featureadmin "github.com/diwise/diwise-web/internal/presentation/web/components/features/admin"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+type rulesApp interface {
+	rules.Management
+	GetTenants(ctx context.Context) []string
+}
+
+func NewRulesComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app rulesApp) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		writeRules(w, r, l10n, app, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewSaveRuleHandler saves the rule in the form. A rule that is not valid is not saved, and the
+// rules are shown again together with what was wrong.
+func NewSaveRuleHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app rulesApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		rule, err := ruleFromForm(r, app.GetTenants(ctx), authz.HasScope(ctx, authz.AdminScope))
+		if err == nil {
+			_, err = app.SaveRule(ctx, rule)
+		}
+		if errors.Is(err, rules.ErrInvalid) {
+			writeRules(w, r, l10n, app, err.Error())
+			return
+		}
+		if err != nil {
+			log.Error("could not save rule", "err", err.Error())
+			http.Error(w, "could not save rule", http.StatusInternalServerError)
+			return
+		}
+
+		writeRules(w, r, l10n, app, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewDeleteRuleHandler removes a rule of one of the tenants of the user. Rules for every tenant can
+// only be removed by users with the admin scope.
+func NewDeleteRuleHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app rulesApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		all, err := app.GetRules(ctx)
+		if err != nil {
+			log.Error("could not fetch rules", "err", err.Error())
+			http.Error(w, "could not delete rule", http.StatusInternalServerError)
+			return
+		}
+
+		i := slices.IndexFunc(all, func(rule rules.Rule) bool { return rule.ID == id && !rule.Builtin })
+		if i < 0 {
+			http.Error(w, "rule not found", http.StatusNotFound)
+			return
+		}
+		if !mayManage(ctx, all[i], app.GetTenants(ctx)) {
+			http.Error(w, "the rule belongs to another tenant", http.StatusForbidden)
+			return
+		}
+
+		if err := app.DeleteRule(ctx, id); err != nil {
+			log.Error("could not delete rule", "rule_id", id, "err", err.Error())
+			http.Error(w, "could not delete rule", http.StatusInternalServerError)
+			return
+		}
+
+		writeRules(w, r, l10n, app, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func writeRules(w http.ResponseWriter, r *http.Request, l10n LocaleBundle, app rulesApp, problem string) {
+	ctx := r.Context()
+	log := logging.GetFromContext(ctx)
+	localizer := l10n.For(helpers.Language(r))
+
+	all, err := app.GetRules(ctx)
+	if err != nil {
+		log.Error("could not fetch rules", "err", err.Error())
+		http.Error(w, "could not fetch rules", http.StatusInternalServerError)
+		return
+	}
+
+	tenants := app.GetTenants(ctx)
+	model := featureadmin.RulesViewModel{
+		Tenants:    tenants,
+		AllTenants: authz.HasScope(ctx, authz.AdminScope),
+		Scopes:     rules.Scopes,
+		Properties: rules.Properties,
+		Error:      problem,
+	}
+
+	for _, rule := range all {
+		// rules for other tenants are neither shown nor possible to remove
+		if rule.Tenant != "" && !slices.Contains(tenants, rule.Tenant) {
+			continue
+		}
+		model.Rules = append(model.Rules, toRuleViewModel(rule))
+	}
+
+	component := featureadmin.Rules(localizer, model)
+	helpers.WriteComponentResponse(ctx, w, r, component, 16*1024, 0)
+}
+
+func toRuleViewModel(rule rules.Rule) featureadmin.RuleViewModel {
+	return featureadmin.RuleViewModel{
+		ID:       rule.ID,
+		Tenant:   rule.Tenant,
+		Scope:    rule.Scope,
+		Target:   rule.Target,
+		Property: rule.Property,
+		Warning:  formatLimit(rule.Warning),
+		Critical: formatLimit(rule.Critical),
+		Below:    rule.Below,
+		Alert:    rule.Alert,
+		Builtin:  rule.Builtin,
+	}
+}
+
+// mayManage reports if the user may change a rule, which is when it belongs to one of the tenants of
+// the user or, for a rule without a tenant that applies to every tenant, when the user is an admin.
+func mayManage(ctx context.Context, rule rules.Rule, tenants []string) bool {
+	if rule.Tenant == "" {
+		return authz.HasScope(ctx, authz.AdminScope)
+	}
+	return slices.Contains(tenants, rule.Tenant)
+}
+
+func ruleFromForm(r *http.Request, tenants []string, allTenants bool) (rules.Rule, error) {
+	rule := rules.Rule{
+		Tenant:   r.FormValue("tenant"),
+		Scope:    r.FormValue("scope"),
+		Target:   r.FormValue("target"),
+		Property: r.FormValue("property"),
+		Below:    r.FormValue("below") != "",
+		Alert:    r.FormValue("alert") != "",
+	}
+
+	if rule.Tenant == "" && !allTenants {
+		return rules.Rule{}, errors.Join(rules.ErrInvalid, errors.New("only admins can add rules for every tenant"))
+	}
+	if rule.Tenant != "" && !slices.Contains(tenants, rule.Tenant) {
+		return rules.Rule{}, errors.Join(rules.ErrInvalid, errors.New("unknown tenant"))
+	}
+
+	var err error
+	if rule.Warning, err = parseLimit(r.FormValue("warning")); err != nil {
+		return rules.Rule{}, err
+	}
+	if rule.Critical, err = parseLimit(r.FormValue("critical")); err != nil {
+		return rules.Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func parseLimit(value string) (*float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errors.Join(rules.ErrInvalid, errors.New("a limit must be a number"))
+	}
+
+	return &f, nil
+}
+
+func formatLimit(limit *float64) string {
+	if limit == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*limit, 'f', -1, 64)
+}
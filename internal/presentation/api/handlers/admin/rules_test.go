@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/rules"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestRuleFromFormParsesLimitsAndFlags(t *testing.T) {
+	is := is.New(t)
+
+	r := ruleForm(url.Values{
+		"tenant":   {"default"},
+		"scope":    {"tag"},
+		"target":   {"north"},
+		"property": {"temperature"},
+		"critical": {"26.5"},
+		"alert":    {"on"},
+	})
+
+	rule, err := ruleFromForm(r, []string{"default"}, false)
+	is.NoErr(err)
+	is.Equal("default", rule.Tenant)
+	is.Equal(rules.ScopeTag, rule.Scope)
+	is.True(rule.Warning == nil)
+	is.Equal(26.5, *rule.Critical)
+	is.True(rule.Alert)
+	is.True(!rule.Below)
+}
+
+func TestRuleFromFormRejectsOtherTenantsAndBadLimits(t *testing.T) {
+	is := is.New(t)
+
+	_, err := ruleFromForm(ruleForm(url.Values{"tenant": {"other"}, "critical": {"1"}}), []string{"default"}, true)
+	is.True(errors.Is(err, rules.ErrInvalid))
+
+	_, err = ruleFromForm(ruleForm(url.Values{"tenant": {"default"}, "warning": {"high"}}), []string{"default"}, false)
+	is.True(errors.Is(err, rules.ErrInvalid))
+
+	// only admins may add rules for every tenant
+	_, err = ruleFromForm(ruleForm(url.Values{"critical": {"1"}}), []string{"default"}, false)
+	is.True(errors.Is(err, rules.ErrInvalid))
+	_, err = ruleFromForm(ruleForm(url.Values{"critical": {"1"}}), []string{"default"}, true)
+	is.NoErr(err)
+}
+
+func TestDeleteRuleHandlerRefusesRulesOfOtherTenants(t *testing.T) {
+	is := is.New(t)
+
+	app := &testRulesApp{rules: []rules.Rule{
+		{ID: "other", Tenant: "other"},
+		{ID: "global"},
+		{ID: "default-sandstorage", Builtin: true},
+	}}
+	handler := NewDeleteRuleHandler(context.Background(), nil, nil, app)
+
+	deleteRule := func(ctx context.Context, id string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "/components/admin/rules/"+id+"/delete", nil)
+		r.SetPathValue("id", id)
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	is.Equal(http.StatusNotFound, deleteRule(context.Background(), "missing"))
+	is.Equal(http.StatusNotFound, deleteRule(context.Background(), "default-sandstorage"))
+	is.Equal(http.StatusForbidden, deleteRule(context.Background(), "other"))
+	is.Equal(http.StatusForbidden, deleteRule(context.Background(), "global"))
+	is.Equal(0, len(app.deleted))
+
+	admin := context.WithValue(context.Background(), authz.ScopeClaim, "openid admin")
+	is.Equal(http.StatusForbidden, deleteRule(admin, "other"))
+	is.True(mayManage(admin, app.rules[1], app.GetTenants(admin)))
+}
+
+type testRulesApp struct {
+	rules   []rules.Rule
+	deleted []string
+}
+
+func (a *testRulesApp) GetRules(context.Context) ([]rules.Rule, error) { return a.rules, nil }
+func (a *testRulesApp) SaveRule(_ context.Context, rule rules.Rule) (rules.Rule, error) {
+	return rule, nil
+}
+func (a *testRulesApp) DeleteRule(_ context.Context, id string) error {
+	a.deleted = append(a.deleted, id)
+	return nil
+}
+func (a *testRulesApp) GetAlerts(context.Context) ([]rules.Alert, error) { return nil, nil }
+func (a *testRulesApp) GetTenants(context.Context) []string              { return []string{"default"} }
+
+func ruleForm(values url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/components/admin/rules", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
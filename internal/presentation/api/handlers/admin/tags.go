@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/a-h/templ"
+	apptags "github.com/diwise/diwise-web/internal/application/tags"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featureadmin "github.com/diwise/diwise-web/internal/presentation/web/components/features/admin"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewTagsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app apptags.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "admin",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+
+		usage, err := app.GetTagUsage(ctx)
+		if err != nil {
+			log.Error("could not fetch tag usage", "err", err.Error())
+			http.Error(w, "could not fetch tags", http.StatusInternalServerError)
+			return
+		}
+
+		page := featureadmin.TagsPage(localizer, toTagsViewModel(usage, authz.HasScope(ctx, authz.AdminScope)))
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewTagsComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apptags.Management) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		writeTags(w, r, l10n, app)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewRenameTagHandler starts renaming a tag on every thing that carries it, and responds with the
+// progress of the rename. Only administrators may rename tags, since the things of every tenant that
+// the user can see are changed.
+func NewRenameTagHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apptags.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if !authz.HasScope(ctx, authz.AdminScope) {
+			http.Error(w, "only administrators may rename tags", http.StatusForbidden)
+			return
+		}
+
+		job, err := app.RenameTag(ctx, r.FormValue("from"), r.FormValue("to"))
+		if errors.Is(err, authz.ErrNoSubject) {
+			http.Error(w, "unknown user", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, apptags.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Error("could not rename tag", "err", err.Error())
+			http.Error(w, "could not rename tag", http.StatusInternalServerError)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		component := featureadmin.TagJob(localizer, toTagJobViewModel(job))
+		helpers.WriteComponentResponse(ctx, w, r, component, 4*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewTagJobHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apptags.Management) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		job, err := app.GetTagJob(ctx, r.PathValue("id"))
+		if errors.Is(err, apptags.ErrNotFound) {
+			// htmx stops polling when the response is 286
+			w.WriteHeader(286)
+			return
+		}
+		if err != nil {
+			http.Error(w, "could not fetch rename progress", http.StatusInternalServerError)
+			return
+		}
+
+		component := featureadmin.TagJob(localizer, toTagJobViewModel(job))
+		helpers.WriteComponentResponse(ctx, w, r, component, 4*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewDeleteTagHandler stops suggesting an unused tag to the tenants of the user. Only administrators may
+// delete tags.
+func NewDeleteTagHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apptags.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !authz.HasScope(r.Context(), authz.AdminScope) {
+			http.Error(w, "only administrators may delete tags", http.StatusForbidden)
+			return
+		}
+
+		tag := r.FormValue("tag")
+		if tag == "" {
+			http.Error(w, "no tag found in form", http.StatusBadRequest)
+			return
+		}
+
+		err := app.DeleteTag(r.Context(), tag)
+		if errors.Is(err, apptags.ErrInUse) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, apptags.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Error("could not delete tag", "tag", tag, "err", err.Error())
+			http.Error(w, "could not delete tag", http.StatusInternalServerError)
+			return
+		}
+
+		writeTags(w, r, l10n, app)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func writeTags(w http.ResponseWriter, r *http.Request, l10n LocaleBundle, app apptags.Management) {
+	ctx := r.Context()
+	localizer := l10n.For(helpers.Language(r))
+
+	usage, err := app.GetTagUsage(ctx)
+	if err != nil {
+		logging.GetFromContext(ctx).Error("could not fetch tag usage", "err", err.Error())
+		http.Error(w, "could not fetch tags", http.StatusInternalServerError)
+		return
+	}
+
+	component := featureadmin.Tags(localizer, toTagsViewModel(usage, authz.HasScope(ctx, authz.AdminScope)))
+	helpers.WriteComponentResponse(ctx, w, r, component, 20*1024, 0)
+}
+
+func toTagsViewModel(usage []apptags.Usage, manage bool) featureadmin.TagsViewModel {
+	model := featureadmin.TagsViewModel{Tags: make([]featureadmin.TagViewModel, 0, len(usage)), Manage: manage}
+	for _, u := range usage {
+		model.Tags = append(model.Tags, featureadmin.TagViewModel{Tag: u.Tag, Things: u.Things})
+	}
+	return model
+}
+
+func toTagJobViewModel(job apptags.Job) featureadmin.TagJobViewModel {
+	return featureadmin.TagJobViewModel{
+		ID:       job.ID,
+		From:     job.From,
+		To:       job.To,
+		Total:    job.Total,
+		Done:     job.Done,
+		Percent:  job.Percent(),
+		Failed:   job.Failed,
+		Finished: job.Finished,
+	}
+}
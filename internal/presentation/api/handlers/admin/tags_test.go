@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	apptags "github.com/diwise/diwise-web/internal/application/tags"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/matryer/is"
+)
+
+func TestDeleteTagHandlerRefusesTagsInUse(t *testing.T) {
+	is := is.New(t)
+
+	handler := NewDeleteTagHandler(context.Background(), nil, nil, testTagsApp{})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/components/admin/tags/delete", strings.NewReader(url.Values{"tag": {"park"}}.Encode()))
+	r = r.WithContext(context.WithValue(r.Context(), authz.ScopeClaim, "openid admin"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(w, r)
+
+	is.Equal(http.StatusConflict, w.Code)
+}
+
+func TestOnlyAdministratorsMayRenameAndDeleteTags(t *testing.T) {
+	is := is.New(t)
+
+	post := func(handler http.Handler, path string, form url.Values) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		r = r.WithContext(context.WithValue(r.Context(), authz.ScopeClaim, "openid"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	is.Equal(http.StatusForbidden, post(NewRenameTagHandler(context.Background(), nil, nil, testTagsApp{}), "/components/admin/tags/rename", url.Values{"from": {"park"}, "to": {"parken"}}))
+	is.Equal(http.StatusForbidden, post(NewDeleteTagHandler(context.Background(), nil, nil, testTagsApp{}), "/components/admin/tags/delete", url.Values{"tag": {"park"}}))
+}
+
+func TestTagJobViewModelShowsProgress(t *testing.T) {
+	is := is.New(t)
+
+	model := toTagJobViewModel(apptags.Job{ID: "job", From: "Skolan", To: "skolan", Total: 4, Done: 1, Failed: []string{"a"}})
+	is.Equal(25, model.Percent)
+	is.Equal([]string{"a"}, model.Failed)
+	is.True(!model.Finished)
+}
+
+type testTagsApp struct{}
+
+func (testTagsApp) GetTagUsage(context.Context) ([]apptags.Usage, error) { return nil, nil }
+func (testTagsApp) RenameTag(context.Context, string, string) (apptags.Job, error) {
+	return apptags.Job{}, nil
+}
+func (testTagsApp) GetTagJob(context.Context, string) (apptags.Job, error) { return apptags.Job{}, nil }
+func (testTagsApp) DeleteTag(context.Context, string) error                { return apptags.ErrInUse }
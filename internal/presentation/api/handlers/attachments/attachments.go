@@ -0,0 +1,260 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	appattachments "github.com/diwise/diwise-web/internal/application/attachments"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// MaxFiles is the most photos that can be uploaded at once.
+const MaxFiles = 10
+
+// maxUploadSize leaves room for the rest of the multipart form besides the photos.
+const maxUploadSize = MaxFiles*appattachments.MaxSize + 1<<20
+
+type attachmentsApp interface {
+	appattachments.Management
+	helpers.SubjectFinder
+}
+
+// NewAttachmentsComponentHandler shows the photos attached to a thing or a sensor.
+func NewAttachmentsComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app attachmentsApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		writeGallery(ctx, w, r, localizer, app, kind, id, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewUploadAttachmentHandler attaches one or more uploaded photos to a thing or a sensor and shows
+// the gallery again. Photos that cannot be attached are named in a message, while the rest are kept.
+func NewUploadAttachmentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app attachmentsApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		err := r.ParseMultipartForm(1 << 20)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeGallery(ctx, w, r, localizer, app, kind, id, localizer.Get("photostoolarge"))
+				return
+			}
+			http.Error(w, "could not parse form", http.StatusBadRequest)
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		files := r.MultipartForm.File["photos"]
+		if len(files) > MaxFiles {
+			writeGallery(ctx, w, r, localizer, app, kind, id, localizer.Get("toomanyphotos"))
+			return
+		}
+
+		problems := []string{}
+		for _, file := range files {
+			data, err := readFile(file)
+			if err == nil {
+				_, err = app.AddAttachment(ctx, kind, id, file.Filename, data)
+			}
+
+			if errors.Is(err, authz.ErrNoSubject) {
+				http.Error(w, "only a known user can upload photos", http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				message, ok := errorMessage(localizer, err)
+				if !ok {
+					logging.GetFromContext(ctx).Error("could not add attachment", "kind", kind, "id", id, "err", err.Error())
+					http.Error(w, "could not add attachment", http.StatusInternalServerError)
+					return
+				}
+				problems = append(problems, fmt.Sprintf("%s: %s", file.Filename, message))
+			}
+		}
+
+		writeGallery(ctx, w, r, localizer, app, kind, id, strings.Join(problems, " "))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewDeleteAttachmentHandler removes a photo uploaded by the current user.
+func NewDeleteAttachmentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app attachmentsApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		err := app.DeleteAttachment(ctx, kind, id, r.PathValue("attachment"))
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			http.Error(w, "attachment not found", http.StatusNotFound)
+			return
+		case errors.Is(err, appattachments.ErrNotAuthor), errors.Is(err, authz.ErrNoSubject):
+			http.Error(w, "only the uploader can delete an attachment", http.StatusForbidden)
+			return
+		case err != nil:
+			logging.GetFromContext(ctx).Error("could not delete attachment", "kind", kind, "id", id, "err", err.Error())
+			http.Error(w, "could not delete attachment", http.StatusInternalServerError)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		writeGallery(ctx, w, r, localizer, app, kind, id, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewAttachmentFileHandler serves an attached photo, or its thumbnail when size=thumbnail.
+func NewAttachmentFileHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app attachmentsApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		thumbnail := r.URL.Query().Get("size") == "thumbnail"
+		attachment, file, err := app.GetAttachmentFile(ctx, kind, id, r.PathValue("attachment"), thumbnail)
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, "attachment not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logging.GetFromContext(ctx).Error("could not fetch attachment", "kind", kind, "id", id, "err", err.Error())
+			http.Error(w, "could not fetch attachment", http.StatusInternalServerError)
+			return
+		}
+		defer file.Body.Close()
+
+		contentType := attachment.ContentType
+		if thumbnail {
+			contentType = "image/jpeg"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Name}))
+		// an attachment is never changed, only removed, so it can be kept by the browser
+		w.Header().Set("Cache-Control", "private, max-age=86400")
+		if file.Size > 0 {
+			w.Header().Set("Content-Length", fmt.Sprint(file.Size))
+		}
+
+		if _, err := io.Copy(w, file.Body); err != nil {
+			logging.GetFromContext(ctx).Warn("could not write attachment", "kind", kind, "id", id, "err", err.Error())
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func readFile(file *multipart.FileHeader) ([]byte, error) {
+	if file.Size > appattachments.MaxSize {
+		return nil, appattachments.ErrTooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, appattachments.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > appattachments.MaxSize {
+		return nil, appattachments.ErrTooLarge
+	}
+
+	return data, nil
+}
+
+// errorMessage tells the user why a photo could not be attached, if it was because of the photo.
+func errorMessage(l10n Localizer, err error) (string, bool) {
+	switch {
+	case errors.Is(err, appattachments.ErrTooLarge):
+		return l10n.Get("phototoolarge"), true
+	case errors.Is(err, appattachments.ErrUnsupportedType):
+		return l10n.Get("photounsupported"), true
+	case errors.Is(err, appattachments.ErrInvalid):
+		return l10n.Get("photoinvalid"), true
+	default:
+		return "", false
+	}
+}
+
+func writeGallery(ctx context.Context, w http.ResponseWriter, r *http.Request, localizer Localizer, app appattachments.Management, kind, id, message string) {
+	all, err := app.GetAttachments(ctx, kind, id)
+	if err != nil {
+		logging.GetFromContext(ctx).Error("could not fetch attachments", "kind", kind, "id", id, "err", err.Error())
+		http.Error(w, "could not fetch attachments", http.StatusInternalServerError)
+		return
+	}
+
+	loc := helpers.Location(ctx)
+	subject := authz.Subject(ctx)
+
+	gallery := shared.AttachmentGallery{
+		Kind:      kind,
+		SubjectID: id,
+		Photos:    make([]shared.AttachmentEntry, 0, len(all)),
+		Accept:    strings.Join(appattachments.ContentTypes, ","),
+		Error:     message,
+	}
+	for _, attachment := range all {
+		gallery.Photos = append(gallery.Photos, shared.AttachmentEntry{
+			ID:           attachment.ID,
+			Name:         attachment.Name,
+			Author:       attachment.Author,
+			Uploaded:     attachment.Uploaded.In(loc).Format("2006-01-02 15:04"),
+			DateTime:     attachment.Uploaded.Format(time.RFC3339),
+			URL:          shared.AttachmentURL(kind, id, attachment.ID, false),
+			ThumbnailURL: shared.AttachmentURL(kind, id, attachment.ID, true),
+			Owned:        attachment.AuthorID == subject,
+		})
+	}
+
+	helpers.WriteComponentResponse(ctx, w, r, shared.AttachmentsSection(localizer, gallery), 8*1024, 0)
+}
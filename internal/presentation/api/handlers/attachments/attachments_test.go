@@ -0,0 +1,136 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	appattachments "github.com/diwise/diwise-web/internal/application/attachments"
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	frontendtoolkit "github.com/diwise/frontend-toolkit"
+	ftkmock "github.com/diwise/frontend-toolkit/mock"
+	"github.com/matryer/is"
+)
+
+func TestUploadAttachesPhotosAndNamesTheRest(t *testing.T) {
+	is := is.New(t)
+	mux := newTestMux(t)
+
+	body := bytes.Buffer{}
+	form := multipart.NewWriter(&body)
+	photo, _ := form.CreateFormFile("photos", "outlet.png")
+	png.Encode(photo, image.NewGray(image.Rect(0, 0, 40, 30)))
+	text, _ := form.CreateFormFile("photos", "notes.txt")
+	text.Write([]byte("not a photo"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/components/attachments/thing/outlet-1", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusOK, rec.Code)
+	is.True(strings.Contains(rec.Body.String(), "notes.txt: photounsupported"))
+
+	thumbnail := regexp.MustCompile(`src="(/attachments/thing/outlet-1/[^"?]+\?size=thumbnail)"`).FindStringSubmatch(rec.Body.String())
+	is.True(thumbnail != nil)
+
+	req = httptest.NewRequest(http.MethodGet, thumbnail[1], nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal("image/jpeg", rec.Header().Get("Content-Type"))
+	is.Equal("nosniff", rec.Header().Get("X-Content-Type-Options"))
+}
+
+func TestAttachmentsAreOnlyServedForTheirThing(t *testing.T) {
+	is := is.New(t)
+	mux := newTestMux(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/attachments/thing/outlet-1/../../data/views.json", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	is.True(rec.Code != http.StatusOK)
+
+	req = httptest.NewRequest(http.MethodGet, "/attachments/building/b-1/photo", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	is.Equal(http.StatusBadRequest, rec.Code)
+}
+
+func newTestMux(t *testing.T) http.Handler {
+	store, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs, err := storage.NewFileBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	app := testApp{Service: appattachments.NewService(store, blobs)}
+	l10n := testLocaleBundle()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /attachments/{kind}/{id}/{attachment}", NewAttachmentFileHandler(ctx, l10n, nil, app))
+	mux.HandleFunc("POST /components/attachments/{kind}/{id}", NewUploadAttachmentHandler(ctx, l10n, nil, app))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authz.SubjectClaim, "alice")))
+	})
+}
+
+func TestAttachmentsAreOnlyShownForThingsThatTheUserCanSee(t *testing.T) {
+	is := is.New(t)
+	mux := newTestMux(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/attachments/thing/other-tenant/photo", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	is.Equal(http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/components/attachments/device/other-tenant", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	is.Equal(http.StatusNotFound, rec.Code)
+}
+
+// testApp can only see the thing outlet-1.
+type testApp struct {
+	*appattachments.Service
+}
+
+func (testApp) GetThing(_ context.Context, id string, _ map[string][]string) (things.Thing, error) {
+	if id != "outlet-1" {
+		return things.Thing{}, client.ErrNotFound
+	}
+	return things.Thing{ID: id}, nil
+}
+
+func (testApp) GetDevice(context.Context, string) (devices.Device, error) {
+	return devices.Device{}, client.ErrNotFound
+}
+
+func testLocaleBundle() *ftkmock.LocaleBundleMock {
+	return &ftkmock.LocaleBundleMock{
+		ForFunc: func(string) frontendtoolkit.Localizer {
+			return &ftkmock.LocalizerMock{
+				GetFunc:         func(key string) string { return key },
+				GetWithDataFunc: func(key string, _ map[string]any) string { return key },
+			}
+		},
+	}
+}
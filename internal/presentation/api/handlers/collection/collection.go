@@ -0,0 +1,251 @@
+package collection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/a-h/templ"
+	appcollection "github.com/diwise/diwise-web/internal/application/collection"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurecollection "github.com/diwise/diwise-web/internal/presentation/web/components/features/collection"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+const defaultThreshold = 80
+
+// the depot defaults to the same position that the maps are centered on
+var defaultDepot = appcollection.Point{Latitude: 62.3908, Longitude: 17.3069}
+
+type collectionApp interface {
+	appcollection.Management
+	GetTenants(ctx context.Context) []string
+	GetTags(ctx context.Context) ([]string, error)
+}
+
+func NewCollectionRunPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app collectionApp) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "collection",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+		criteria, err := parseCriteria(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tags, _ := app.GetTags(ctx)
+		model := featurecollection.CollectionRunPageViewModel{
+			Threshold:      criteria.Threshold,
+			Tenant:         criteria.Tenant,
+			DepotLatitude:  criteria.Depot.Latitude,
+			DepotLongitude: criteria.Depot.Longitude,
+			Tenants:        app.GetTenants(ctx),
+			Tags:           tags,
+		}
+		if len(criteria.Tags) > 0 {
+			model.Tag = criteria.Tags[0]
+		}
+
+		// a run is only planned when the page is opened with criteria, such as from a bookmark
+		if r.URL.Query().Has("threshold") {
+			run, err := app.PlanCollectionRun(ctx, criteria)
+			if err != nil {
+				log.Error("could not plan collection run", "err", err.Error())
+				http.Error(w, "could not plan collection run", http.StatusInternalServerError)
+				return
+			}
+			runModel := toViewModel(run)
+			model.Run = &runModel
+		}
+
+		page := featurecollection.CollectionRunPage(localizer, model)
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewCollectionRunComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app collectionApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		criteria, err := parseCriteria(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		run, err := app.PlanCollectionRun(ctx, criteria)
+		if err != nil {
+			log.Error("could not plan collection run", "err", err.Error())
+			http.Error(w, "could not plan collection run", http.StatusInternalServerError)
+			return
+		}
+
+		model := toViewModel(run)
+		w.Header().Set("HX-Push-Url", "/collection?"+model.Query)
+
+		component := featurecollection.CollectionRun(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewCollectionRunExportHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app collectionApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		criteria, err := parseCriteria(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		run, err := app.PlanCollectionRun(ctx, criteria)
+		if err != nil {
+			log.Error("could not plan collection run", "err", err.Error())
+			http.Error(w, "could not plan collection run", http.StatusInternalServerError)
+			return
+		}
+
+		var b []byte
+		var contentType string
+
+		format := r.URL.Query().Get("format")
+		switch format {
+		case "gpx":
+			b, err = run.GPX()
+			contentType = "application/gpx+xml"
+		case "csv":
+			b, err = run.CSV()
+			if err == nil {
+				b, err = geo.AddGridColumns(b, geo.ExportProjections(helpers.GetUserPreferences(r.Context()).CoordinateSystem))
+			}
+			contentType = "text/csv; charset=utf-8"
+		default:
+			http.Error(w, "unsupported export format", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "could not export collection run", http.StatusInternalServerError)
+			return
+		}
+
+		filename := fmt.Sprintf("collection-run-%s.%s", run.CreatedAt.Format("20060102-1504"), format)
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		w.Write(b)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func parseCriteria(query url.Values) (appcollection.Criteria, error) {
+	criteria := appcollection.Criteria{
+		Threshold: defaultThreshold,
+		Tenant:    query.Get("tenant"),
+		Depot:     defaultDepot,
+	}
+
+	for _, tag := range query["tags"] {
+		if tag != "" {
+			criteria.Tags = append(criteria.Tags, tag)
+		}
+	}
+
+	parse := func(key string, target *float64, lower, upper float64) error {
+		value := query.Get(key)
+		if value == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < lower || f > upper {
+			return fmt.Errorf("invalid %s", key)
+		}
+		*target = f
+		return nil
+	}
+
+	if err := parse("threshold", &criteria.Threshold, 0, 100); err != nil {
+		return criteria, err
+	}
+	if err := parse("depotLatitude", &criteria.Depot.Latitude, -90, 90); err != nil {
+		return criteria, err
+	}
+	if err := parse("depotLongitude", &criteria.Depot.Longitude, -180, 180); err != nil {
+		return criteria, err
+	}
+
+	return criteria, nil
+}
+
+func criteriaQuery(criteria appcollection.Criteria) string {
+	query := url.Values{}
+	query.Set("threshold", strconv.FormatFloat(criteria.Threshold, 'f', -1, 64))
+	query.Set("depotLatitude", strconv.FormatFloat(criteria.Depot.Latitude, 'f', 6, 64))
+	query.Set("depotLongitude", strconv.FormatFloat(criteria.Depot.Longitude, 'f', 6, 64))
+	if criteria.Tenant != "" {
+		query.Set("tenant", criteria.Tenant)
+	}
+	for _, tag := range criteria.Tags {
+		query.Add("tags", tag)
+	}
+	return query.Encode()
+}
+
+func toViewModel(run appcollection.Run) featurecollection.RunViewModel {
+	model := featurecollection.RunViewModel{
+		Query:          criteriaQuery(run.Criteria),
+		Distance:       run.Distance,
+		DepotLatitude:  run.Criteria.Depot.Latitude,
+		DepotLongitude: run.Criteria.Depot.Longitude,
+		Stops:          make([]featurecollection.StopViewModel, 0, len(run.Stops)),
+	}
+
+	for i, stop := range run.Stops {
+		model.Stops = append(model.Stops, toStopViewModel(i+1, stop))
+	}
+	for _, stop := range run.Unplaced {
+		model.Unplaced = append(model.Unplaced, toStopViewModel(0, stop))
+	}
+
+	return model
+}
+
+func toStopViewModel(order int, stop appcollection.Stop) featurecollection.StopViewModel {
+	return featurecollection.StopViewModel{
+		Order:     order,
+		ID:        stop.ThingID,
+		Name:      stop.Name,
+		Percent:   stop.Percent,
+		Latitude:  stop.Point.Latitude,
+		Longitude: stop.Point.Longitude,
+		Tags:      stop.Tags,
+	}
+}
@@ -0,0 +1,45 @@
+package collection
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseCriteriaRoundTripsThroughQuery(t *testing.T) {
+	is := is.New(t)
+
+	query, _ := url.ParseQuery("threshold=75&tenant=default&tags=north&tags=&depotLatitude=62.1&depotLongitude=17.2")
+	criteria, err := parseCriteria(query)
+	is.NoErr(err)
+
+	is.Equal(75.0, criteria.Threshold)
+	is.Equal("default", criteria.Tenant)
+	is.Equal([]string{"north"}, criteria.Tags)
+	is.Equal(62.1, criteria.Depot.Latitude)
+
+	again, _ := url.ParseQuery(criteriaQuery(criteria))
+	roundTripped, err := parseCriteria(again)
+	is.NoErr(err)
+	is.Equal(criteria, roundTripped)
+}
+
+func TestParseCriteriaRejectsOutOfRangeValues(t *testing.T) {
+	is := is.New(t)
+
+	_, err := parseCriteria(url.Values{"threshold": {"120"}})
+	is.True(err != nil)
+
+	_, err = parseCriteria(url.Values{"depotLatitude": {"north"}})
+	is.True(err != nil)
+}
+
+func TestParseCriteriaDefaults(t *testing.T) {
+	is := is.New(t)
+
+	criteria, err := parseCriteria(url.Values{})
+	is.NoErr(err)
+	is.Equal(float64(defaultThreshold), criteria.Threshold)
+	is.Equal(defaultDepot, criteria.Depot)
+}
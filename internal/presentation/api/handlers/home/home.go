@@ -7,6 +7,7 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	appclient "github.com/diwise/diwise-web/internal/application/client"
 	"github.com/diwise/diwise-web/internal/application/devices"
 	"github.com/diwise/diwise-web/internal/application/measurements"
+	"github.com/diwise/diwise-web/internal/application/rules"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	featurehome "github.com/diwise/diwise-web/internal/presentation/web/components/features/home"
 	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
@@ -28,6 +30,8 @@ type homeApp interface {
 	alarms.Management
 	devices.Management
 	measurements.Management
+	rules.Management
+	GetTenants(ctx context.Context) []string
 }
 
 func NewHomePage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app homeApp) http.HandlerFunc {
@@ -39,7 +43,7 @@ func NewHomePage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc,
 			v2layout.CurrentComponent, "home",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		pageIndex := helpers.UrlParamOrDefault(r, "page", "1")
 		offset, limit := getOffsetAndLimit(r)
 
@@ -52,7 +56,7 @@ func NewHomePage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc,
 		for _, a := range result.Alarms {
 			model.Alarms = append(model.Alarms, featurehome.AlarmViewModel{
 				DeviceID:   a.DeviceID,
-				ObservedAt: a.ObservedAt,
+				ObservedAt: a.ObservedAt.In(helpers.Location(ctx)),
 				Types:      a.Types,
 			})
 		}
@@ -60,6 +64,9 @@ func NewHomePage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc,
 		pageIndexInt, _ := strconv.Atoi(pageIndex)
 		pageLast := int(math.Ceil(float64(result.TotalRecords) / float64(limit)))
 		model.Paging = getPaging(pageIndexInt, pageLast, limit, args)
+		if model.Paging.PageIndex == 1 {
+			model.Alerts = getAlerts(ctx, app)
+		}
 
 		home := featurehome.Home(localizer, model)
 		component := templ.Component(v2layout.StartPage(version, localizer, assets, home))
@@ -79,7 +86,7 @@ func NewAlarmsTable(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app
 			v2layout.CurrentComponent, "home",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		pageIndex := helpers.UrlParamOrDefault(r, "page", "1")
 		offset, limit := getOffsetAndLimit(r)
 
@@ -94,7 +101,7 @@ func NewAlarmsTable(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app
 		for _, a := range result.Alarms {
 			model.Alarms = append(model.Alarms, featurehome.AlarmViewModel{
 				DeviceID:   a.DeviceID,
-				ObservedAt: a.ObservedAt,
+				ObservedAt: a.ObservedAt.In(helpers.Location(ctx)),
 				Types:      a.Types,
 			})
 		}
@@ -102,6 +109,9 @@ func NewAlarmsTable(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app
 		pageIndexInt, _ := strconv.Atoi(pageIndex)
 		pageLast := int(math.Ceil(float64(result.TotalRecords) / float64(limit)))
 		model.Paging = getPaging(pageIndexInt, pageLast, limit, args)
+		if model.Paging.PageIndex == 1 {
+			model.Alerts = getAlerts(ctx, app)
+		}
 
 		component := featurehome.AlarmsTableSection(localizer, model)
 		helpers.WriteComponentResponse(ctx, w, r, component, 12*1024, 0)
@@ -112,7 +122,7 @@ func NewAlarmsTable(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app
 
 func NewOverviewCardsHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app homeApp) http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		ctx := r.Context()
 
 		stats, err := app.GetStatistics(ctx)
@@ -227,6 +237,30 @@ func usageChartColor(isDark bool, index int) string {
 	return colors[index%len(colors)]
 }
 
+// getAlerts returns the alerts raised by the threshold rules for the things of the tenants of the user.
+func getAlerts(ctx context.Context, app homeApp) []featurehome.AlertViewModel {
+	alerts, _ := app.GetAlerts(ctx)
+	tenants := app.GetTenants(ctx)
+
+	result := make([]featurehome.AlertViewModel, 0, len(alerts))
+	for _, a := range alerts {
+		if !slices.Contains(tenants, a.Tenant) {
+			continue
+		}
+		result = append(result, featurehome.AlertViewModel{
+			ThingID:  a.ThingID,
+			Name:     a.Name,
+			Property: a.Property,
+			Value:    a.Value,
+			Limit:    a.Limit,
+			Below:    a.Below,
+			Since:    a.Since.In(helpers.Location(ctx)),
+		})
+	}
+
+	return result
+}
+
 func getPaging(pageIndex, pageLast, pageSize int, args url.Values) featurehome.PagingViewModel {
 	return featurehome.PagingViewModel{
 		PageIndex: max(pageIndex, 1),
@@ -0,0 +1,180 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	appnotes "github.com/diwise/diwise-web/internal/application/notes"
+	"github.com/diwise/diwise-web/internal/application/storage"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+const timeLayout = "2006-01-02T15:04"
+
+type notesApp interface {
+	appnotes.Management
+	helpers.SubjectFinder
+}
+
+// NewNotesComponentHandler shows the maintenance log of a thing or a sensor.
+func NewNotesComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app notesApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		writeNotes(ctx, w, r, localizer, app, kind, id, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewAddNoteHandler adds a note to the maintenance log of a thing or a sensor and shows the log
+// again, with the form still open if the note could not be added.
+func NewAddNoteHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app notesApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "could not parse form", http.StatusBadRequest)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		note := appnotes.Note{
+			Kind:      kind,
+			SubjectID: id,
+			Category:  r.Form.Get("category"),
+			Text:      r.Form.Get("text"),
+		}
+
+		if value := strings.TrimSpace(r.Form.Get("time")); value != "" {
+			at, err := time.ParseInLocation(timeLayout, value, helpers.Location(ctx))
+			if err != nil {
+				writeNotes(ctx, w, r, localizer, app, kind, id, localizer.Get("invalidnote"))
+				return
+			}
+			note.Time = at
+		}
+
+		_, err := app.AddNote(ctx, note)
+		if errors.Is(err, appnotes.ErrInvalid) {
+			writeNotes(ctx, w, r, localizer, app, kind, id, localizer.Get("invalidnote"))
+			return
+		}
+		if errors.Is(err, authz.ErrNoSubject) {
+			http.Error(w, "only a known user can add a note", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			logging.GetFromContext(ctx).Error("could not add note", "kind", kind, "id", id, "err", err.Error())
+			http.Error(w, "could not add note", http.StatusInternalServerError)
+			return
+		}
+
+		writeNotes(ctx, w, r, localizer, app, kind, id, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewDeleteNoteHandler removes a note written by the current user.
+func NewDeleteNoteHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app notesApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		kind, id, ok := helpers.Subject(w, r, app)
+		if !ok {
+			return
+		}
+
+		err := app.DeleteNote(ctx, kind, id, r.PathValue("note"))
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			http.Error(w, "note not found", http.StatusNotFound)
+			return
+		case errors.Is(err, appnotes.ErrNotAuthor), errors.Is(err, authz.ErrNoSubject):
+			http.Error(w, "only the author can delete a note", http.StatusForbidden)
+			return
+		case err != nil:
+			logging.GetFromContext(ctx).Error("could not delete note", "kind", kind, "id", id, "err", err.Error())
+			http.Error(w, "could not delete note", http.StatusInternalServerError)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		writeNotes(ctx, w, r, localizer, app, kind, id, "")
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// Annotate marks the notes of a thing or a sensor between from and to on a measurement chart, in
+// addition to any notes already marked. The chart is left as it is if the notes cannot be fetched.
+func Annotate(ctx context.Context, config *shared.AdvancedChartConfig, l10n Localizer, app appnotes.Management, kind, id string, from, to time.Time) {
+	all, err := app.GetNotes(ctx, kind, id)
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not fetch notes for chart", "kind", kind, "id", id, "err", err.Error())
+		return
+	}
+
+	between := appnotes.Between(all, from, to)
+	for _, note := range slices.Backward(between) {
+		config.Annotate(helpers.ChartAnnotation(ctx, note.Time, time.Time{}, l10n.Get("notecategory"+note.Category), ""))
+	}
+}
+
+func writeNotes(ctx context.Context, w http.ResponseWriter, r *http.Request, localizer Localizer, app notesApp, kind, id, message string) {
+	all, err := app.GetNotes(ctx, kind, id)
+	if err != nil {
+		logging.GetFromContext(ctx).Error("could not fetch notes", "kind", kind, "id", id, "err", err.Error())
+		http.Error(w, "could not fetch notes", http.StatusInternalServerError)
+		return
+	}
+
+	loc := helpers.Location(ctx)
+	subject := authz.Subject(ctx)
+
+	model := shared.NoteLog{
+		Kind:       kind,
+		SubjectID:  id,
+		Notes:      make([]shared.NoteEntry, 0, len(all)),
+		Categories: appnotes.Categories,
+		Now:        time.Now().In(loc).Format(timeLayout),
+		Error:      message,
+	}
+	for _, note := range all {
+		model.Notes = append(model.Notes, shared.NoteEntry{
+			ID:       note.ID,
+			Author:   note.Author,
+			Time:     note.Time.In(loc).Format("2006-01-02 15:04"),
+			DateTime: note.Time.Format(time.RFC3339),
+			Category: note.Category,
+			Text:     note.Text,
+			Owned:    note.AuthorID == subject,
+		})
+	}
+
+	helpers.WriteComponentResponse(ctx, w, r, shared.NotesSection(localizer, model), 8*1024, 0)
+}
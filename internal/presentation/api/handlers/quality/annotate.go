@@ -0,0 +1,28 @@
+package quality
+
+import (
+	"context"
+	"time"
+
+	appquality "github.com/diwise/diwise-web/internal/application/quality"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// issueColor sets the markers of suspect data apart from the notes and other markers on the charts.
+const issueColor = "#dc2626"
+
+// Annotate marks the gaps, stuck values and outliers of a series on its chart. Interval is the
+// longest that the sensor should be silent, or zero if it is not known. The time from the last value
+// to the end of the chart, or to now if that is earlier, is checked for a gap too.
+func Annotate(ctx context.Context, config *shared.AdvancedChartConfig, l10n Localizer, points []appquality.Point, interval time.Duration, end time.Time) {
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+
+	for _, issue := range appquality.Analyse(points, interval, end) {
+		config.Annotate(helpers.ChartAnnotation(ctx, issue.From, issue.To, l10n.Get("quality"+issue.Kind), issueColor))
+	}
+}
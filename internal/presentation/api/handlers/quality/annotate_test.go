@@ -0,0 +1,39 @@
+package quality
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appquality "github.com/diwise/diwise-web/internal/application/quality"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	ftkmock "github.com/diwise/frontend-toolkit/mock"
+	"github.com/matryer/is"
+)
+
+func TestAnnotateMarksGapsAndOutliers(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	points := []appquality.Point{}
+	for i := range 24 {
+		if i >= 5 && i < 10 {
+			continue
+		}
+		value := 20.0 + float64(i%3)
+		if i == 15 {
+			value = 300
+		}
+		points = append(points, appquality.Point{Time: start.Add(time.Duration(i) * time.Hour), Value: value})
+	}
+
+	ctx := helpers.WithUserPreferences(context.Background(), helpers.UserPreferences{Location: time.UTC})
+	config := shared.AdvancedChartConfig{}
+	Annotate(ctx, &config, &ftkmock.LocalizerMock{GetFunc: func(key string) string { return key }}, points, time.Hour, start.Add(23*time.Hour))
+
+	lines := config.Options.Plugins.Annotations.Lines
+	is.Equal(2, len(lines))
+	is.Equal(shared.ChartAnnotation{X: "2026-05-04 04:00", To: "2026-05-04 10:00", Label: "qualitygap", Color: issueColor}, lines[0])
+	is.Equal(shared.ChartAnnotation{X: "2026-05-04 15:00", Label: "qualityoutlier", Color: issueColor}, lines[1])
+}
@@ -0,0 +1,149 @@
+package reports
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	appquality "github.com/diwise/diwise-web/internal/application/quality"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+type dataQualityApp interface {
+	appquality.Management
+	GetTenants(ctx context.Context) []string
+}
+
+func NewDataQualityPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app dataQualityApp) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+
+		tenants := app.GetTenants(ctx)
+		report, ok := dataQualityReport(ctx, w, r, app, tenants)
+		if !ok {
+			return
+		}
+
+		page := featurereports.DataQualityPage(localizer, featurereports.DataQualityPageViewModel{
+			Tenants: tenants,
+			Report:  toDataQualityViewModel(helpers.Location(ctx), report),
+		})
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewDataQualityComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app dataQualityApp) http.HandlerFunc {
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		report, ok := dataQualityReport(ctx, w, r, app, app.GetTenants(ctx))
+		if !ok {
+			return
+		}
+
+		model := toDataQualityViewModel(helpers.Location(ctx), report)
+		w.Header().Set("HX-Push-Url", "/reports/dataquality?"+model.Query)
+
+		component := featurereports.DataQuality(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// dataQualityReport returns the report for the tenant that is asked for, or for every tenant of the
+// user. A 403 is written if the user may not see the tenant.
+func dataQualityReport(ctx context.Context, w http.ResponseWriter, r *http.Request, app dataQualityApp, tenants []string) (appquality.Report, bool) {
+	tenant := r.URL.Query().Get("tenant")
+	if tenant != "" && !slices.Contains(tenants, tenant) {
+		http.Error(w, "unknown organisation", http.StatusForbidden)
+		return appquality.Report{}, false
+	}
+
+	report, err := app.GetDataQualityReport(ctx, tenant, tenants)
+	if err != nil {
+		logging.GetFromContext(ctx).Error("could not create data quality report", "err", err.Error())
+		http.Error(w, "could not create data quality report", http.StatusInternalServerError)
+		return appquality.Report{}, false
+	}
+
+	return report, true
+}
+
+func toDataQualityViewModel(loc *time.Location, report appquality.Report) featurereports.DataQualityViewModel {
+	query := url.Values{}
+	if report.Tenant != "" {
+		query.Set("tenant", report.Tenant)
+	}
+
+	model := featurereports.DataQualityViewModel{
+		Query:   query.Encode(),
+		Tenant:  report.Tenant,
+		Sensors: make([]featurereports.SuspectSensorViewModel, 0, len(report.Findings)),
+	}
+	if !report.CheckedAt.IsZero() {
+		model.CheckedAt = report.CheckedAt.In(loc).Format("2006-01-02 15:04")
+	}
+
+	for _, f := range report.Findings {
+		sensor := featurereports.SuspectSensorViewModel{
+			ID:       f.DeviceID,
+			Name:     cmp.Or(f.Name, f.DeviceID),
+			Tenant:   f.Tenant,
+			Gaps:     f.Count(appquality.KindGap),
+			Stuck:    f.Count(appquality.KindStuck),
+			Outliers: f.Count(appquality.KindOutlier),
+		}
+
+		for _, s := range f.Series {
+			sensor.Partial = sensor.Partial || s.Partial
+			for _, i := range s.Issues {
+				issue := featurereports.DataIssueViewModel{
+					Measurement: strings.TrimPrefix(s.ID, f.DeviceID+"/"),
+					Kind:        i.Kind,
+					From:        i.From.In(loc).Format("2006-01-02 15:04"),
+					To:          i.To.In(loc).Format("2006-01-02 15:04"),
+					Count:       i.Count,
+				}
+				if i.Value != nil {
+					issue.Value = fmt.Sprintf("%g", *i.Value)
+				}
+				sensor.Issues = append(sensor.Issues, issue)
+			}
+		}
+
+		model.Gaps += sensor.Gaps
+		model.Stuck += sensor.Stuck
+		model.Outliers += sensor.Outliers
+		model.Sensors = append(model.Sensors, sensor)
+	}
+
+	return model
+}
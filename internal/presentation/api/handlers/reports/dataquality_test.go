@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appquality "github.com/diwise/diwise-web/internal/application/quality"
+	frontendtoolkit "github.com/diwise/frontend-toolkit"
+	ftkmock "github.com/diwise/frontend-toolkit/mock"
+	"github.com/matryer/is"
+)
+
+func TestDataQualityIsOnlyShownForTheTenantsOfTheUser(t *testing.T) {
+	is := is.New(t)
+
+	app := &testDataQualityApp{tenants: []string{"default"}}
+	handler := NewDataQualityComponentHandler(context.Background(), testLocaleBundle(), nil, app)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/components/reports/dataquality?tenant=other", nil))
+	is.Equal(http.StatusForbidden, rec.Code)
+	is.Equal(0, app.calls)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/components/reports/dataquality", nil))
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal([]string{"default"}, app.asked)
+}
+
+func TestDataQualityViewModelCountsIssues(t *testing.T) {
+	is := is.New(t)
+
+	at := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	model := toDataQualityViewModel(time.UTC, appquality.Report{
+		Tenant:    "default",
+		CheckedAt: at,
+		Findings: []appquality.Finding{
+			{DeviceID: "dev-1", Tenant: "default", Series: []appquality.Series{
+				{ID: "dev-1/3303/5700", Issues: []appquality.Issue{
+					{Kind: appquality.KindGap, From: at.Add(-5 * time.Hour), To: at, Count: 4},
+					{Kind: appquality.KindOutlier, From: at, To: at, Value: new(412.5)},
+				}},
+			}},
+			{DeviceID: "dev-2", Name: "Level sensor", Tenant: "default", Series: []appquality.Series{
+				{ID: "dev-2/3330/5700", Issues: []appquality.Issue{{Kind: appquality.KindStuck, From: at, To: at, Value: new(7.0), Count: 20}}},
+			}},
+		},
+	})
+
+	is.Equal("tenant=default", model.Query)
+	is.Equal("2026-05-04 10:00", model.CheckedAt)
+	is.Equal(1, model.Gaps)
+	is.Equal(1, model.Stuck)
+	is.Equal(1, model.Outliers)
+	is.Equal("dev-1", model.Sensors[0].Name)
+	is.Equal("3303/5700", model.Sensors[0].Issues[0].Measurement)
+	is.Equal("412.5", model.Sensors[0].Issues[1].Value)
+	is.Equal("Level sensor", model.Sensors[1].Name)
+}
+
+type testDataQualityApp struct {
+	tenants []string
+	asked   []string
+	calls   int
+}
+
+func (a *testDataQualityApp) GetDataQualityReport(_ context.Context, tenant string, tenants []string) (appquality.Report, error) {
+	a.calls++
+	a.asked = tenants
+	return appquality.Report{Tenant: tenant}, nil
+}
+
+func (a *testDataQualityApp) GetSuspectDevices(context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (a *testDataQualityApp) GetTenants(context.Context) []string {
+	return a.tenants
+}
+
+func testLocaleBundle() *ftkmock.LocaleBundleMock {
+	return &ftkmock.LocaleBundleMock{
+		ForFunc: func(string) frontendtoolkit.Localizer {
+			return &ftkmock.LocalizerMock{
+				GetFunc:         func(key string) string { return key },
+				GetWithDataFunc: func(key string, _ map[string]any) string { return key },
+			}
+		},
+	}
+}
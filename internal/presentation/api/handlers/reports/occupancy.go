@@ -0,0 +1,143 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/a-h/templ"
+	appoccupancy "github.com/diwise/diwise-web/internal/application/occupancy"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewOccupancyReportPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app appoccupancy.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+		period, err := occupancyReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := app.GetOccupancyReport(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not create occupancy report", "err", err.Error())
+			http.Error(w, "could not create occupancy report", http.StatusInternalServerError)
+			return
+		}
+
+		page := featurereports.OccupancyReportPage(localizer, featurereports.OccupancyReportPageViewModel{
+			Report: toOccupancyReportViewModel(period, report),
+		})
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewOccupancyReportComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appoccupancy.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		period, err := occupancyReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := app.GetOccupancyReport(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not create occupancy report", "err", err.Error())
+			http.Error(w, "could not create occupancy report", http.StatusInternalServerError)
+			return
+		}
+
+		model := toOccupancyReportViewModel(period, report)
+		w.Header().Set("HX-Push-Url", "/reports/occupancy?"+model.Query)
+
+		component := featurereports.OccupancyReport(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func occupancyReportPeriod(r *http.Request) (helpers.DateRange, error) {
+	now := time.Now().In(helpers.Location(r.Context()))
+	return helpers.ParseDateRange(r, now.AddDate(0, 0, 1-appoccupancy.DefaultDays))
+}
+
+func toOccupancyReportViewModel(period helpers.DateRange, report appoccupancy.Report) featurereports.OccupancyReportViewModel {
+	query := url.Values{}
+	query.Set("from", period.FirstDay())
+	query.Set("to", period.LastDay())
+
+	model := featurereports.OccupancyReportViewModel{
+		Query:  query.Encode(),
+		From:   period.FirstDay(),
+		To:     period.LastDay(),
+		Spaces: make([]featurereports.OccupancySpaceViewModel, 0, len(report.Spaces)),
+		Groups: make([]featurereports.OccupancyGroupViewModel, 0, len(report.Groups)),
+	}
+
+	for hour := appoccupancy.OfficeStartHour; hour < appoccupancy.OfficeEndHour; hour++ {
+		model.Hours = append(model.Hours, shared.FormatHourOfDay(hour))
+	}
+
+	total := appoccupancy.HourShare{}
+	for _, s := range report.Spaces {
+		total.Occupied += s.Occupied
+		total.Total += s.Total
+
+		space := featurereports.OccupancySpaceViewModel{
+			ID:          s.ThingID,
+			Name:        s.Name,
+			Type:        s.Type,
+			Tags:        s.Tags,
+			Utilisation: s.Percent(),
+			Failed:      s.Failed,
+		}
+		for _, hour := range s.PeakHours() {
+			space.PeakHours = append(space.PeakHours, shared.FormatHourOfDay(hour))
+		}
+		model.Spaces = append(model.Spaces, space)
+	}
+	model.Utilisation = total.Percent()
+
+	for _, g := range report.Groups {
+		group := featurereports.OccupancyGroupViewModel{
+			Name:        g.Name,
+			Spaces:      g.Spaces,
+			Utilisation: g.Percent(),
+		}
+		for hour := appoccupancy.OfficeStartHour; hour < appoccupancy.OfficeEndHour; hour++ {
+			group.ByHour = append(group.ByHour, g.ByHour[hour].Percent())
+		}
+		model.Groups = append(model.Groups, group)
+	}
+
+	return model
+}
@@ -0,0 +1,35 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	appoccupancy "github.com/diwise/diwise-web/internal/application/occupancy"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestOccupancyReportViewModelLeavesFailedSpacesOutOfTheTotal(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	group := appoccupancy.Group{Name: "floor 2", Spaces: 2, HourShare: appoccupancy.HourShare{Occupied: 3, Total: 12}}
+	group.ByHour[appoccupancy.OfficeStartHour] = appoccupancy.HourShare{Occupied: 1, Total: 2}
+
+	model := toOccupancyReportViewModel(helpers.DateRange{From: day, To: day.AddDate(0, 0, 1)}, appoccupancy.Report{
+		Spaces: []appoccupancy.Space{
+			{ThingID: "room-1", HourShare: appoccupancy.HourShare{Occupied: 1, Total: 6}},
+			{ThingID: "room-2", HourShare: appoccupancy.HourShare{Occupied: 2, Total: 6}},
+			{ThingID: "room-3", Failed: true},
+		},
+		Groups: []appoccupancy.Group{group},
+	})
+
+	is.Equal(25.0, model.Utilisation)
+	is.Equal(3, len(model.Spaces))
+	is.True(model.Spaces[2].Failed)
+	is.Equal(appoccupancy.OfficeEndHour-appoccupancy.OfficeStartHour, len(model.Hours))
+	is.Equal(len(model.Hours), len(model.Groups[0].ByHour))
+	is.Equal(50.0, model.Groups[0].ByHour[0])
+	is.Equal(25.0, model.Groups[0].Utilisation)
+}
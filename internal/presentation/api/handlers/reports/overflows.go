@@ -0,0 +1,198 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/a-h/templ"
+	appoverflows "github.com/diwise/diwise-web/internal/application/overflows"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewOverflowReportPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app appoverflows.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+		period, err := overflowReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := app.GetOverflowReport(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not create overflow report", "err", err.Error())
+			http.Error(w, "could not create overflow report", http.StatusInternalServerError)
+			return
+		}
+
+		page := featurereports.OverflowReportPage(localizer, featurereports.OverflowReportPageViewModel{
+			Report: toOverflowReportViewModel(period, report, helpers.Location(ctx)),
+		})
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 60*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewOverflowReportComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appoverflows.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		period, err := overflowReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := app.GetOverflowReport(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not create overflow report", "err", err.Error())
+			http.Error(w, "could not create overflow report", http.StatusInternalServerError)
+			return
+		}
+
+		model := toOverflowReportViewModel(period, report, helpers.Location(ctx))
+		w.Header().Set("HX-Push-Url", "/reports/overflows?"+model.Query)
+
+		component := featurereports.OverflowReport(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 50*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewOverflowReportExportHandler exports the overflow report as CSV, either as one row per outlet
+// (report=summary) or one row per overflow (report=events). The events of a single outlet are
+// exported when a thingid is given.
+func NewOverflowReportExportHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app appoverflows.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		loc := helpers.Location(ctx)
+
+		period, err := overflowReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		kind := r.URL.Query().Get("report")
+		if kind != "summary" && kind != "events" {
+			http.Error(w, "unsupported report", http.StatusBadRequest)
+			return
+		}
+
+		var report appoverflows.Report
+		if thingID := r.URL.Query().Get("thingid"); thingID != "" {
+			report = appoverflows.Report{From: period.From, To: period.To}
+			report.Events, err = app.GetOverflowEvents(ctx, thingID, period.From, period.To)
+		} else {
+			report, err = app.GetOverflowReport(ctx, period.From, period.To)
+		}
+		if err != nil {
+			log.Error("could not create overflow report", "err", err.Error())
+			http.Error(w, "could not create overflow report", http.StatusInternalServerError)
+			return
+		}
+
+		var b []byte
+		if kind == "summary" {
+			b, err = report.SummaryCSV(loc)
+		} else {
+			b, err = report.EventsCSV(loc)
+		}
+		if err != nil {
+			http.Error(w, "could not export overflow report", http.StatusInternalServerError)
+			return
+		}
+
+		filename := fmt.Sprintf("overflows-%s-%s-%s.csv", kind, period.FirstDay(), period.LastDay())
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		w.Write(b)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// overflowReportPeriod defaults to the current year, which is the period reported to the authorities.
+func overflowReportPeriod(r *http.Request) (helpers.DateRange, error) {
+	now := time.Now().In(helpers.Location(r.Context()))
+	return helpers.ParseDateRange(r, time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location()))
+}
+
+func toOverflowReportViewModel(period helpers.DateRange, report appoverflows.Report, loc *time.Location) featurereports.OverflowReportViewModel {
+	query := url.Values{}
+	query.Set("from", period.FirstDay())
+	query.Set("to", period.LastDay())
+
+	model := featurereports.OverflowReportViewModel{
+		Query:   query.Encode(),
+		From:    period.FirstDay(),
+		To:      period.LastDay(),
+		Count:   report.Count(),
+		Total:   report.Total(),
+		Outlets: make([]featurereports.OverflowOutletViewModel, 0, len(report.Summaries)),
+		Events:  make([]featurereports.OverflowEventViewModel, 0, len(report.Events)),
+	}
+
+	for _, s := range report.Summaries {
+		outlet := featurereports.OverflowOutletViewModel{
+			ID:      s.ThingID,
+			Name:    s.Name,
+			Tenant:  s.Tenant,
+			Count:   s.Count,
+			Total:   s.Total,
+			Longest: s.Longest,
+			Failed:  s.Failed,
+		}
+		if s.LastStart != nil {
+			outlet.LastStart = new(s.LastStart.In(loc))
+		}
+		model.Outlets = append(model.Outlets, outlet)
+	}
+
+	for _, e := range report.Events {
+		model.Events = append(model.Events, featurereports.OverflowEventViewModel{
+			ThingID:  e.ThingID,
+			Name:     e.Name,
+			Start:    e.Start.In(loc),
+			End:      e.End.In(loc),
+			Duration: e.Duration(),
+			Ongoing:  e.Ongoing,
+		})
+	}
+
+	// list the most recent overflows first
+	slices.Reverse(model.Events)
+
+	return model
+}
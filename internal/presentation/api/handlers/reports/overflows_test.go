@@ -0,0 +1,72 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appoverflows "github.com/diwise/diwise-web/internal/application/overflows"
+	"github.com/diwise/diwise-web/internal/application/things"
+	"github.com/matryer/is"
+)
+
+func TestOverflowReportPeriodDefaultsToCurrentYear(t *testing.T) {
+	is := is.New(t)
+
+	period, err := overflowReportPeriod(httptest.NewRequest(http.MethodGet, "/reports/overflows", nil))
+	is.NoErr(err)
+	is.Equal(time.January, period.From.Month())
+	is.Equal(1, period.From.Day())
+	is.Equal(time.Now().Year(), period.From.Year())
+
+	period, err = overflowReportPeriod(httptest.NewRequest(http.MethodGet, "/reports/overflows?from=2024-01-01&to=2024-12-31", nil))
+	is.NoErr(err)
+	is.Equal("2024-01-01", period.FirstDay())
+	is.Equal("2024-12-31", period.LastDay())
+
+	_, err = overflowReportPeriod(httptest.NewRequest(http.MethodGet, "/reports/overflows?from=2024-12-31&to=2024-01-01", nil))
+	is.True(err != nil)
+}
+
+func TestOverflowReportExportOfSingleOutlet(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	app := &testApp{events: []appoverflows.Event{{ThingID: "cso-1", Name: "Outlet 1", Period: things.Period{Start: start, End: start.Add(30 * time.Minute)}}}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/reports/overflows/export?report=events&thingid=cso-1&from=2024-01-01&to=2024-12-31", nil)
+	NewOverflowReportExportHandler(context.Background(), nil, nil, app).ServeHTTP(w, r)
+
+	is.Equal(http.StatusOK, w.Code)
+	is.Equal("cso-1", app.thingID)
+	is.True(strings.Contains(w.Header().Get("Content-Disposition"), "overflows-events-2024-01-01-2024-12-31.csv"))
+	is.Equal(2, strings.Count(w.Body.String(), "\n"))
+}
+
+func TestOverflowReportExportRejectsUnknownReport(t *testing.T) {
+	is := is.New(t)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/reports/overflows/export?report=everything", nil)
+	NewOverflowReportExportHandler(context.Background(), nil, nil, &testApp{}).ServeHTTP(w, r)
+
+	is.Equal(http.StatusBadRequest, w.Code)
+}
+
+type testApp struct {
+	events  []appoverflows.Event
+	thingID string
+}
+
+func (a *testApp) GetOverflowEvents(_ context.Context, thingID string, _, _ time.Time) ([]appoverflows.Event, error) {
+	a.thingID = thingID
+	return a.events, nil
+}
+
+func (a *testApp) GetOverflowReport(_ context.Context, from, to time.Time) (appoverflows.Report, error) {
+	return appoverflows.Report{From: from, To: to, Events: a.events}, nil
+}
@@ -0,0 +1,129 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/a-h/templ"
+	apppumping "github.com/diwise/diwise-web/internal/application/pumping"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewPumpingReportPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app apppumping.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+		period, err := pumpingReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stations, err := app.GetPumpingComparison(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not compare pumping stations", "err", err.Error())
+			http.Error(w, "could not compare pumping stations", http.StatusInternalServerError)
+			return
+		}
+
+		page := featurereports.PumpingReportPage(localizer, featurereports.PumpingReportPageViewModel{
+			Report: toPumpingReportViewModel(period, stations),
+		})
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewPumpingReportComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apppumping.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		period, err := pumpingReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stations, err := app.GetPumpingComparison(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not compare pumping stations", "err", err.Error())
+			http.Error(w, "could not compare pumping stations", http.StatusInternalServerError)
+			return
+		}
+
+		model := toPumpingReportViewModel(period, stations)
+		w.Header().Set("HX-Push-Url", "/reports/pumping?"+model.Query)
+
+		component := featurereports.PumpingReport(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func pumpingReportPeriod(r *http.Request) (helpers.DateRange, error) {
+	now := time.Now().In(helpers.Location(r.Context()))
+	return helpers.ParseDateRange(r, now.AddDate(0, 0, 1-apppumping.DefaultDays))
+}
+
+func toPumpingReportViewModel(period helpers.DateRange, stations []apppumping.Stats) featurereports.PumpingReportViewModel {
+	query := url.Values{}
+	query.Set("from", period.FirstDay())
+	query.Set("to", period.LastDay())
+
+	model := featurereports.PumpingReportViewModel{
+		Query:    query.Encode(),
+		From:     period.FirstDay(),
+		To:       period.LastDay(),
+		Stations: make([]featurereports.PumpingStationViewModel, 0, len(stations)),
+	}
+
+	for _, s := range stations {
+		station := featurereports.PumpingStationViewModel{
+			ID:           s.ThingID,
+			Name:         s.Name,
+			Tenant:       s.Tenant,
+			Starts:       s.Starts,
+			StartsPerDay: s.AverageStartsPerDay(),
+			Runtime:      s.Runtime,
+			AverageCycle: s.AverageCycle,
+			LongestCycle: s.LongestCycle,
+			Running:      s.Running,
+			Failed:       s.Failed,
+		}
+		for _, a := range s.Anomalies {
+			switch a.Kind {
+			case apppumping.AnomalyContinuousRunning:
+				station.ContinuousRuns++
+			case apppumping.AnomalyFrequentStarts:
+				station.FrequentStarts++
+			}
+		}
+		model.Stations = append(model.Stations, station)
+	}
+
+	return model
+}
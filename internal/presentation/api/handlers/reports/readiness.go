@@ -0,0 +1,135 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/a-h/templ"
+	appreadiness "github.com/diwise/diwise-web/internal/application/readiness"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewReadinessBoardPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app appreadiness.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+
+		board, err := app.GetReadinessBoard(ctx)
+		if err != nil {
+			log.Error("could not create readiness board", "err", err.Error())
+			http.Error(w, "could not create readiness board", http.StatusInternalServerError)
+			return
+		}
+
+		page := featurereports.ReadinessBoardPage(localizer, toReadinessBoardViewModel(board, time.Now().In(helpers.Location(ctx))))
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 60*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewReadinessChecklistPage renders the readiness board as a checklist to print and bring along
+// when the lifebuoys are inspected on site.
+func NewReadinessChecklistPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app appreadiness.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		board, err := app.GetReadinessBoard(ctx)
+		if err != nil {
+			log.Error("could not create readiness checklist", "err", err.Error())
+			http.Error(w, "could not create readiness checklist", http.StatusInternalServerError)
+			return
+		}
+
+		checklist := featurereports.ReadinessChecklist(localizer, toReadinessBoardViewModel(board, time.Now().In(helpers.Location(ctx))))
+		component := v2layout.PrintPage(localizer, assets, localizer.Get("readinesschecklist"), checklist)
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func toReadinessBoardViewModel(board appreadiness.Board, now time.Time) featurereports.ReadinessBoardViewModel {
+	model := featurereports.ReadinessBoardViewModel{
+		Lifebuoys: board.Lifebuoys(),
+		Missing:   board.Missing(),
+		Groups:    make([]featurereports.LifebuoyGroupViewModel, 0, len(board.Groups)),
+		Beaches:   make([]featurereports.BeachViewModel, 0, len(board.Beaches)),
+		Printed:   now.Format("2006-01-02 15:04"),
+	}
+
+	for _, g := range board.Groups {
+		group := featurereports.LifebuoyGroupViewModel{
+			Name:    g.Name,
+			BeachID: g.BeachID,
+			Missing: g.Missing(),
+		}
+
+		for _, l := range g.Lifebuoys {
+			lifebuoy := featurereports.LifebuoyViewModel{
+				ID:        l.ThingID,
+				Name:      l.Name,
+				Tags:      l.Tags,
+				Latitude:  l.Latitude,
+				Longitude: l.Longitude,
+			}
+
+			switch {
+			case l.Present == nil:
+				lifebuoy.Status = "nostatus"
+				model.NoStatus++
+			case l.Missing():
+				lifebuoy.Status = "missing"
+				if l.MissingSince != nil {
+					lifebuoy.MissingFor = shared.FormatAge(now.Sub(*l.MissingSince))
+				}
+			default:
+				lifebuoy.Status = "present"
+			}
+
+			group.Lifebuoys = append(group.Lifebuoys, lifebuoy)
+		}
+
+		model.Groups = append(model.Groups, group)
+	}
+
+	for _, b := range board.Beaches {
+		beach := featurereports.BeachViewModel{
+			ID:          b.ThingID,
+			Name:        b.Name,
+			Latitude:    b.Latitude,
+			Longitude:   b.Longitude,
+			Temperature: b.Temperature,
+			Trend:       b.Trend,
+		}
+		if !b.ObservedAt.IsZero() {
+			beach.ObservedAt = b.ObservedAt.In(now.Location()).Format("2006-01-02 15:04")
+		}
+		model.Beaches = append(model.Beaches, beach)
+	}
+
+	return model
+}
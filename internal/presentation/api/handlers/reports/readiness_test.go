@@ -0,0 +1,39 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	appreadiness "github.com/diwise/diwise-web/internal/application/readiness"
+	"github.com/matryer/is"
+)
+
+func TestReadinessBoardViewModelShowsHowLongLifebuoysHaveBeenMissing(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	present, missing := true, false
+	since := now.Add(-50 * time.Hour)
+
+	model := toReadinessBoardViewModel(appreadiness.Board{
+		Groups: []appreadiness.Group{{
+			Name:    "North beach",
+			BeachID: "north",
+			Lifebuoys: []appreadiness.Lifebuoy{
+				{ThingID: "a", Present: &missing, MissingSince: &since},
+				{ThingID: "b", Present: &missing},
+				{ThingID: "c", Present: &present},
+				{ThingID: "d"},
+			},
+		}},
+	}, now)
+
+	is.Equal(4, model.Lifebuoys)
+	is.Equal(2, model.Missing)
+	is.Equal(1, model.NoStatus)
+	is.Equal(2, model.Groups[0].Missing)
+	is.Equal("2 d 2 h", model.Groups[0].Lifebuoys[0].MissingFor)
+	is.Equal("", model.Groups[0].Lifebuoys[1].MissingFor)
+	is.Equal("present", model.Groups[0].Lifebuoys[2].Status)
+	is.Equal("nostatus", model.Groups[0].Lifebuoys[3].Status)
+}
@@ -0,0 +1,118 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/a-h/templ"
+	appwatermeters "github.com/diwise/diwise-web/internal/application/watermeters"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewWaterMeterReportPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app appwatermeters.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+		period, err := waterMeterReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		meters, err := app.GetWaterMeterFleet(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not rank water meters", "err", err.Error())
+			http.Error(w, "could not rank water meters", http.StatusInternalServerError)
+			return
+		}
+
+		page := featurereports.WaterMeterReportPage(localizer, featurereports.WaterMeterReportPageViewModel{
+			Report: toWaterMeterReportViewModel(period, meters),
+		})
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewWaterMeterReportComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appwatermeters.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		period, err := waterMeterReportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		meters, err := app.GetWaterMeterFleet(ctx, period.From, period.To)
+		if err != nil {
+			log.Error("could not rank water meters", "err", err.Error())
+			http.Error(w, "could not rank water meters", http.StatusInternalServerError)
+			return
+		}
+
+		model := toWaterMeterReportViewModel(period, meters)
+		w.Header().Set("HX-Push-Url", "/reports/water?"+model.Query)
+
+		component := featurereports.WaterMeterReport(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func waterMeterReportPeriod(r *http.Request) (helpers.DateRange, error) {
+	now := time.Now().In(helpers.Location(r.Context()))
+	return helpers.ParseDateRange(r, now.AddDate(0, 0, 1-appwatermeters.DefaultDays))
+}
+
+func toWaterMeterReportViewModel(period helpers.DateRange, meters []appwatermeters.Consumption) featurereports.WaterMeterReportViewModel {
+	query := url.Values{}
+	query.Set("from", period.FirstDay())
+	query.Set("to", period.LastDay())
+
+	model := featurereports.WaterMeterReportViewModel{
+		Query:  query.Encode(),
+		From:   period.FirstDay(),
+		To:     period.LastDay(),
+		Meters: make([]featurereports.WaterMeterViewModel, 0, len(meters)),
+	}
+
+	for _, m := range meters {
+		model.Total += m.Total
+		model.Meters = append(model.Meters, featurereports.WaterMeterViewModel{
+			ID:           m.ThingID,
+			Name:         m.Name,
+			Tenant:       m.Tenant,
+			Consumption:  m.Total,
+			Flags:        m.Flags.Names(),
+			ProbableLeak: m.ProbableLeak,
+			Failed:       m.Failed,
+		})
+	}
+
+	return model
+}
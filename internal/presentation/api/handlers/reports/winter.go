@@ -0,0 +1,138 @@
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/a-h/templ"
+	appsandstorage "github.com/diwise/diwise-web/internal/application/sandstorage"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featurereports "github.com/diwise/diwise-web/internal/presentation/web/components/features/reports"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+type winterReadinessApp interface {
+	appsandstorage.Management
+	GetTenants(ctx context.Context) []string
+}
+
+func NewWinterReadinessPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app winterReadinessApp) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "reports",
+		)
+
+		localizer := l10n.For(helpers.Language(r))
+		period, err := winterReadinessPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tenant := r.URL.Query().Get("tenant")
+		report, err := app.GetWinterReadiness(ctx, tenant, period.From, period.To)
+		if err != nil {
+			log.Error("could not create winter readiness report", "err", err.Error())
+			http.Error(w, "could not create winter readiness report", http.StatusInternalServerError)
+			return
+		}
+
+		page := featurereports.WinterReadinessPage(localizer, featurereports.WinterReadinessPageViewModel{
+			Tenants: app.GetTenants(ctx),
+			Report:  toWinterReadinessViewModel(period, helpers.Location(ctx), report),
+		})
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, page))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, page)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 40*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewWinterReadinessComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app winterReadinessApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		period, err := winterReadinessPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tenant := r.URL.Query().Get("tenant")
+		report, err := app.GetWinterReadiness(ctx, tenant, period.From, period.To)
+		if err != nil {
+			log.Error("could not create winter readiness report", "err", err.Error())
+			http.Error(w, "could not create winter readiness report", http.StatusInternalServerError)
+			return
+		}
+
+		model := toWinterReadinessViewModel(period, helpers.Location(ctx), report)
+		w.Header().Set("HX-Push-Url", "/reports/winter?"+model.Query)
+
+		component := featurereports.WinterReadiness(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// winterReadinessPeriod defaults to the current winter season, so that the latest refill is the one
+// made ahead of the winter.
+func winterReadinessPeriod(r *http.Request) (helpers.DateRange, error) {
+	now := time.Now().In(helpers.Location(r.Context()))
+	return helpers.ParseDateRange(r, appsandstorage.SeasonStart(now))
+}
+
+func toWinterReadinessViewModel(period helpers.DateRange, loc *time.Location, report appsandstorage.Report) featurereports.WinterReadinessViewModel {
+	query := url.Values{}
+	if report.Tenant != "" {
+		query.Set("tenant", report.Tenant)
+	}
+	query.Set("from", period.FirstDay())
+	query.Set("to", period.LastDay())
+
+	model := featurereports.WinterReadinessViewModel{
+		Query:    query.Encode(),
+		Tenant:   report.Tenant,
+		From:     period.FirstDay(),
+		To:       period.LastDay(),
+		Ready:    report.Count(appsandstorage.StatusReady),
+		Low:      report.Count(appsandstorage.StatusLow),
+		Critical: report.Count(appsandstorage.StatusCritical),
+		Storages: make([]featurereports.SandStorageViewModel, 0, len(report.Storages)),
+	}
+
+	for _, s := range report.Storages {
+		storage := featurereports.SandStorageViewModel{
+			ID:      s.ThingID,
+			Name:    s.Name,
+			Tenant:  s.Tenant,
+			Fill:    s.Fill,
+			Status:  s.Status(),
+			Sensors: len(s.Sensors) + len(s.Silent),
+			Failed:  s.Failed,
+		}
+		if s.LastRefill != nil {
+			storage.LastRefill = s.LastRefill.At.In(loc).Format(time.DateOnly)
+		}
+		model.Storages = append(model.Storages, storage)
+	}
+
+	return model
+}
@@ -0,0 +1,32 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	appsandstorage "github.com/diwise/diwise-web/internal/application/sandstorage"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestWinterReadinessViewModelCountsSilentSensors(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	model := toWinterReadinessViewModel(helpers.DateRange{From: day, To: day.AddDate(0, 1, 0)}, time.UTC, appsandstorage.Report{
+		Tenant: "default",
+		Storages: []appsandstorage.Storage{
+			{ThingID: "low", Fill: new(20.0), Sensors: []appsandstorage.Sensor{{DeviceID: "a"}}, Silent: []string{"b"}},
+			{ThingID: "full", Fill: new(90.0), LastRefill: &appsandstorage.Refill{At: day.AddDate(0, 0, 3), Before: 30, After: 90}},
+			{ThingID: "broken", Failed: true},
+		},
+	})
+
+	is.Equal("from=2026-09-01&tenant=default&to=2026-09-30", model.Query)
+	is.Equal(1, model.Critical)
+	is.Equal(1, model.Ready)
+	is.Equal(2, model.Storages[0].Sensors)
+	is.Equal("critical", model.Storages[0].Status)
+	is.Equal("2026-09-04", model.Storages[1].LastRefill)
+	is.True(model.Storages[2].Failed)
+}
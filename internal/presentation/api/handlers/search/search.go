@@ -0,0 +1,74 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	appsearch "github.com/diwise/diwise-web/internal/application/search"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresearch "github.com/diwise/diwise-web/internal/presentation/web/components/features/search"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// maximum number of hits shown per group
+const resultsPerGroup = 5
+
+func NewSearchResultsHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appsearch.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		localizer := l10n.For(helpers.Language(r))
+
+		result, err := app.Search(ctx, r.URL.Query().Get("q"), resultsPerGroup)
+		if err != nil {
+			log.Error("could not search", "err", err.Error())
+			http.Error(w, "could not search", http.StatusInternalServerError)
+			return
+		}
+
+		component := featuresearch.Results(localizer, newResultsViewModel(localizer, result, helpers.Location(ctx)))
+		helpers.WriteComponentResponse(ctx, w, r, component, 8*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func newResultsViewModel(l10n Localizer, result appsearch.Result, loc *time.Location) featuresearch.ResultsViewModel {
+	model := featuresearch.ResultsViewModel{Query: result.Query}
+
+	groups := []struct {
+		title string
+		hits  []appsearch.Hit
+	}{
+		{l10n.Get("sensors"), result.Sensors},
+		{l10n.Get("things"), result.Things},
+		{l10n.Get("alerts"), result.Alarms},
+	}
+
+	for _, group := range groups {
+		if len(group.hits) == 0 {
+			continue
+		}
+
+		items := make([]featuresearch.ItemViewModel, 0, len(group.hits))
+		for _, hit := range group.hits {
+			description := hit.Description
+			if !hit.ObservedAt.IsZero() {
+				description = hit.ObservedAt.In(loc).Format("2006-01-02 15:04") + " " + description
+			}
+			items = append(items, featuresearch.ItemViewModel{
+				Title:       hit.Title,
+				Description: description,
+				Href:        hit.Href,
+			})
+		}
+
+		model.Groups = append(model.Groups, featuresearch.GroupViewModel{Title: group.title, Items: items})
+	}
+
+	return model
+}
@@ -2,13 +2,20 @@ package sensors
 
 import (
 	"context"
+	"errors"
 	"maps"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/diwise/diwise-web/internal/application/client"
 	"github.com/diwise/diwise-web/internal/application/devices"
 	appmeasurements "github.com/diwise/diwise-web/internal/application/measurements"
+	appnotes "github.com/diwise/diwise-web/internal/application/notes"
+	appquality "github.com/diwise/diwise-web/internal/application/quality"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/notes"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/quality"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	featuresensors "github.com/diwise/diwise-web/internal/presentation/web/components/features/sensors"
 	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
@@ -20,21 +27,29 @@ import (
 type sensorComponentsApp interface {
 	devices.Management
 	appmeasurements.Management
+	appnotes.Management
 }
 
-func NewMeasurementComponentHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app sensorComponentsApp) http.HandlerFunc {
+func NewMeasurementComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app sensorComponentsApp) http.HandlerFunc {
 	log := logging.GetFromContext(ctx)
+	pointBudget := helpers.GetChartPointBudget(ctx)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ctx := logging.NewContextWithLogger(r.Context(), log)
 		id := r.URL.Query().Get("sensorMeasurementTypes")
 
 		layout := "2006-01-02T15:04"
+		loc := helpers.Location(ctx)
+		prefs := helpers.GetUserPreferences(ctx)
+
 		t := r.URL.Query().Get("timeAt")
 		if t == "" {
-			t = time.Now().Add(-24 * time.Hour).Format(layout)
+			t = time.Now().In(loc).Add(-24 * time.Hour).Format(layout)
+			if !prefs.ChartStart.IsZero() {
+				t = prefs.ChartStart.Format(layout)
+			}
 		}
-		startTime, err := time.Parse(layout, t)
+		startTime, err := time.ParseInLocation(layout, t, loc)
 		if err != nil {
 			http.Error(w, "could not parse timeAt", http.StatusBadRequest)
 			return
@@ -42,46 +57,138 @@ func NewMeasurementComponentHandler(ctx context.Context, _ LocaleBundle, _ Asset
 
 		et := r.URL.Query().Get("endTimeAt")
 		if et == "" {
-			et = time.Now().Format(layout)
+			et = time.Now().In(loc).Format(layout)
+			if !prefs.ChartEnd.IsZero() {
+				et = prefs.ChartEnd.Format(layout)
+			}
 		}
-		endTime, err := time.Parse(layout, et)
+		endTime, err := time.ParseInLocation(layout, et, loc)
 		if err != nil {
 			http.Error(w, "could not parse endTimeAt", http.StatusBadRequest)
 			return
 		}
 
-		measurementData := appmeasurements.Data{Values: []appmeasurements.Value{}}
+		resolution := appmeasurements.ParseResolution(r.URL.Query().Get("resolution")).Resolve(startTime, endTime)
+		aggregation := appmeasurements.ParseAggregation(r.URL.Query().Get("aggregation"))
+
+		series := measurementSeries{
+			Data: appmeasurements.Data{Values: []appmeasurements.Value{}},
+		}
 		if id != "" {
-			measurementData, err = app.GetMeasurementData(
-				ctx,
-				id,
-				client.WithLastN(true),
-				client.WithTimeRel("between", startTime, endTime),
-				client.WithLimit(100),
-				client.WithReverse(true),
-			)
+			series, err = fetchMeasurementSeries(ctx, app, id, startTime, endTime, resolution, aggregation)
 			if err != nil {
 				http.Error(w, "could not fetch measurement data", http.StatusBadRequest)
 				return
 			}
 		}
 
-		component := featuresensors.MeasurementChartComponent(measurementChartConfig(r, measurementData))
+		localizer := l10n.For(helpers.Language(r))
+		charted := series
+		charted.Data.Values = appmeasurements.DownsampleValues(series.Data.Values, pointBudget)
+		config := measurementChartConfig(r, localizer, charted, resolution)
+		if deviceID, _, ok := strings.Cut(id, "/"); ok {
+			notes.Annotate(ctx, &config, localizer, app, appnotes.KindDevice, deviceID, startTime, endTime)
+			if !resolution.Aggregated() {
+				quality.Annotate(ctx, &config, localizer, appquality.MeasurementPoints(series.Data.Values), deviceInterval(ctx, app, deviceID), endTime)
+			}
+		}
+		component := featuresensors.MeasurementChartComponent(config)
 		helpers.WriteComponentResponse(ctx, w, r, component, 20*1024, 5*time.Minute)
 	}
 
 	return http.HandlerFunc(fn)
 }
 
-func measurementChartConfig(r *http.Request, measurements appmeasurements.Data) shared.AdvancedChartConfig {
+// deviceInterval is the longest that a device should be silent, by its profile or else by the device
+// itself, or zero if it is not known.
+func deviceInterval(ctx context.Context, app sensorComponentsApp, deviceID string) time.Duration {
+	device, err := app.GetDevice(ctx, deviceID)
+	if err != nil {
+		logging.GetFromContext(ctx).Debug("could not fetch the interval of the device", "device_id", deviceID, "err", err.Error())
+		return 0
+	}
+
+	interval := device.Interval
+	if device.SensorProfile != nil && device.SensorProfile.Interval > 0 {
+		interval = device.SensorProfile.Interval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// maxRawValues caps the raw values fetched for a chart, which are then downsampled to the point
+// budget of the chart.
+const maxRawValues = 10000
+
+// measurementSeries holds the values to chart and, when averaging, the min and max values used to draw a band around the mean.
+type measurementSeries struct {
+	Data appmeasurements.Data
+	Min  *appmeasurements.Data
+	Max  *appmeasurements.Data
+}
+
+func fetchMeasurementSeries(ctx context.Context, app sensorComponentsApp, id string, startTime, endTime time.Time, resolution appmeasurements.Resolution, aggregation appmeasurements.Aggregation) (measurementSeries, error) {
+	if !resolution.Aggregated() {
+		data, err := app.GetMeasurementData(
+			ctx,
+			id,
+			client.WithLastN(true),
+			client.WithTimeRel("between", startTime, endTime),
+			client.WithLimit(maxRawValues),
+			client.WithReverse(true),
+		)
+		return measurementSeries{Data: data}, err
+	}
+
+	params := func(aggr appmeasurements.Aggregation) []client.InputParam {
+		return []client.InputParam{
+			client.WithTimeRel("between", startTime, endTime),
+			client.WithTimeUnit(string(resolution)),
+			client.WithAggrMethods(string(aggr)),
+			client.WithLimit(1000),
+		}
+	}
+
+	if aggregation != appmeasurements.AggregationAvg {
+		data, err := app.GetMeasurementData(ctx, id, params(aggregation)...)
+		return measurementSeries{Data: data}, err
+	}
+
+	aggregations := []appmeasurements.Aggregation{appmeasurements.AggregationAvg, appmeasurements.AggregationMin, appmeasurements.AggregationMax}
+	results := make([]appmeasurements.Data, len(aggregations))
+	errs := make([]error, len(aggregations))
+
+	var wg sync.WaitGroup
+	for i, aggr := range aggregations {
+		wg.Go(func() {
+			results[i], errs[i] = app.GetMeasurementData(ctx, id, params(aggr)...)
+		})
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return measurementSeries{}, err
+	}
+
+	return measurementSeries{Data: results[0], Min: &results[1], Max: &results[2]}, nil
+}
+
+func measurementChartConfig(r *http.Request, l10n Localizer, series measurementSeries, resolution appmeasurements.Resolution) shared.AdvancedChartConfig {
 	beginAtZero := false
 	theme := chartTheme(helpers.IsDarkMode(r))
 
+	datasets := []shared.AdvancedChartDataset{measurementDataset(r, series.Data)}
+	if series.Min != nil && series.Max != nil {
+		datasets = append(datasets, measurementBandDatasets(r, l10n, series)...)
+	}
+
+	timeScale := measurementTimeScale(theme)
+	timeScale.Time.Unit = resolution.TimeUnit()
+
 	return shared.AdvancedChartConfig{
 		Type: "line",
 		Data: shared.AdvancedChartData{
-			Labels:   measurementLabels(measurements),
-			Datasets: []shared.AdvancedChartDataset{measurementDataset(r, measurements)},
+			Labels:   measurementLabels(series.Data, helpers.Location(r.Context())),
+			Datasets: datasets,
 		},
 		Options: shared.AdvancedChartOptions{
 			Responsive:          true,
@@ -108,7 +215,7 @@ func measurementChartConfig(r *http.Request, measurements appmeasurements.Data)
 				},
 			},
 			Scales: map[string]shared.AxisScale{
-				"x": measurementTimeScale(theme),
+				"x": timeScale,
 				"y": {
 					Offset:      new(true),
 					BeginAtZero: &beginAtZero,
@@ -147,16 +254,9 @@ func measurementDataset(r *http.Request, measurements appmeasurements.Data) shar
 			dataset.Label = value.Unit
 		}
 
-		switch {
-		case value.Value != nil:
-			dataset.Data = append(dataset.Data, *value.Value)
-		case value.BoolValue != nil:
-			if *value.BoolValue {
-				dataset.Data = append(dataset.Data, 1.0)
-			} else {
-				dataset.Data = append(dataset.Data, 0.0)
-			}
-		default:
+		if number, ok := value.Number(); ok {
+			dataset.Data = append(dataset.Data, number)
+		} else {
 			dataset.Data = append(dataset.Data, nil)
 		}
 	}
@@ -164,10 +264,27 @@ func measurementDataset(r *http.Request, measurements appmeasurements.Data) shar
 	return dataset
 }
 
-func measurementLabels(measurements appmeasurements.Data) []string {
+// measurementBandDatasets draws a band between the min and max values around the mean.
+func measurementBandDatasets(r *http.Request, l10n Localizer, series measurementSeries) []shared.AdvancedChartDataset {
+	loc := helpers.Location(r.Context())
+	points := func(data appmeasurements.Data) []shared.ChartPoint {
+		result := make([]shared.ChartPoint, 0, len(data.Values))
+		for _, value := range data.Values {
+			if number, ok := value.Number(); ok {
+				result = append(result, shared.ChartPoint{X: value.Timestamp.In(loc).Format("2006-01-02 15:04"), Y: number})
+			}
+		}
+		return result
+	}
+
+	color := shared.ChartBandColor(helpers.IsDarkMode(r))
+	return shared.BandDatasets(l10n.Get("aggregationmin"), l10n.Get("aggregationmax"), color, points(*series.Min), points(*series.Max))
+}
+
+func measurementLabels(measurements appmeasurements.Data, loc *time.Location) []string {
 	labels := make([]string, 0, len(measurements.Values))
 	for _, value := range measurements.Values {
-		labels = append(labels, value.Timestamp.Format("2006-01-02 15:04"))
+		labels = append(labels, value.Timestamp.In(loc).Format("2006-01-02 15:04"))
 	}
 	return labels
 }
@@ -179,6 +296,7 @@ func NewStatusChartsComponentHandler(ctx context.Context, l10n LocaleBundle, _ A
 		ctx := r.Context()
 		id := r.PathValue("id")
 
+		loc := helpers.Location(ctx)
 		result, err := app.GetSensorStatus(ctx, id)
 		if err != nil {
 			log.Error("could not fetch status for sensor", "device_id", id, "err", err)
@@ -186,7 +304,7 @@ func NewStatusChartsComponentHandler(ctx context.Context, l10n LocaleBundle, _ A
 			return
 		}
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		labels := make([]string, 0, len(result))
 
 		batteryLevel := newStatusDataset(localizer.Get("batterylevel"), "yBatteryLevel")
@@ -196,7 +314,7 @@ func NewStatusChartsComponentHandler(ctx context.Context, l10n LocaleBundle, _ A
 		rssi := newStatusDataset(localizer.Get("rssi"), "yRSSI")
 
 		for _, status := range result {
-			labels = append(labels, status.ObservedAt.Format("2006-01-02 15:04"))
+			labels = append(labels, status.ObservedAt.In(loc).Format("2006-01-02 15:04"))
 
 			if status.BatteryLevel > 0 {
 				batteryLevel.Data = append(batteryLevel.Data, float64(status.BatteryLevel))
@@ -354,8 +472,10 @@ func measurementTimeScale(theme chartThemeConfig) shared.AxisScale {
 			TooltipFormat: "yyyy-MM-dd HH:mm",
 			Parser:        "yyyy-MM-dd HH:mm",
 			DisplayFormats: map[string]string{
-				"hour": "HH:mm",
-				"day":  "yyyy-MM-dd",
+				"hour":  "HH:mm",
+				"day":   "yyyy-MM-dd",
+				"week":  "yyyy-MM-dd",
+				"month": "yyyy-MM",
 			},
 		},
 	}
@@ -45,7 +45,7 @@ func NewSensorDetailsPage(ctx context.Context, l10n LocaleBundle, assets AssetLo
 			v2layout.CurrentComponent, "sensors",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		editMode := r.URL.Query().Get("mode") == "edit"
 		model, err := composeDetailsModel(ctx, id, app, localizer, editMode)
 		if err != nil {
@@ -93,7 +93,7 @@ func NewSaveSensorDetailsPage(ctx context.Context, _ LocaleBundle, _ AssetLoader
 
 func NewMeasurementTypesComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app sensorDetailsApp) http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		sensorType := r.URL.Query().Get("sensorType")
 
 		component := featuresensors.MeasurementTypeOptionsField(localizer, featuresensors.MeasurementTypeOptionsProps{
@@ -113,7 +113,7 @@ func NewAttachSensorDialogHandler(_ context.Context, l10n LocaleBundle, assets A
 			return
 		}
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		renderDialog := func(status int, model featuresensors.AttachSensorDialogViewModel) {
 			component := featuresensors.AttachSensorDialog(localizer, assets, model)
 			writeComponentStatus(r.Context(), w, status, component)
@@ -182,7 +182,7 @@ func NewAttachSensorDialogHandler(_ context.Context, l10n LocaleBundle, assets A
 				return
 			}
 
-			if err := writeUpdatedEditPage(r.Context(), w, r, l10n.For(r.Header.Get("Accept-Language")), assets, id, app); err != nil {
+			if err := writeUpdatedEditPage(r.Context(), w, r, l10n.For(helpers.Language(r)), assets, id, app); err != nil {
 				http.Error(w, "could not fetch sensor", http.StatusInternalServerError)
 				return
 			}
@@ -202,7 +202,7 @@ func NewDetachSensorDialogHandler(_ context.Context, l10n LocaleBundle, assets A
 			return
 		}
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 
 		switch r.Method {
 		case http.MethodGet:
@@ -243,7 +243,7 @@ func NewAttachSensorSearchOptionsHandler(_ context.Context, l10n LocaleBundle, _
 	fn := func(w http.ResponseWriter, r *http.Request) {
 
 		query := strings.TrimSpace(r.URL.Query().Get("q"))
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		args := map[string][]string{
 			"assigned": {"false"},
 			"search":   {query},
@@ -381,6 +381,7 @@ func composeDetailsModel(ctx context.Context, id string, app sensorDetailsApp, l
 		Latitude:    device.Location.Latitude,
 		Longitude:   device.Location.Longitude,
 		ObservedAt:  device.ObservedAt(),
+		Grid:        helpers.GridPosition(ctx, device.Location.Latitude, device.Location.Longitude),
 	}
 
 	if device.Environment != nil {
@@ -427,9 +428,14 @@ func composeDetailsModel(ctx context.Context, id string, app sensorDetailsApp, l
 		})
 	}
 
+	loc := helpers.Location(ctx)
+	model.ObservedAt = model.ObservedAt.In(loc)
+	model.ChartStart = helpers.GetUserPreferences(ctx).ChartStart
+	model.ChartEnd = helpers.GetUserPreferences(ctx).ChartEnd
+
 	for _, measurement := range measurements {
 		item := featuresensors.MeasurementViewModel{
-			Timestamp: measurement.Timestamp,
+			Timestamp: measurement.Timestamp.In(loc),
 			Unit:      measurement.Unit,
 			Value:     measurement.Value,
 			BoolValue: measurement.BoolValue,
@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"context"
+	"maps"
 	"math"
 	"net/http"
 	"net/url"
@@ -13,6 +14,7 @@ import (
 	"github.com/a-h/templ"
 	"github.com/diwise/diwise-web/internal/application/admin"
 	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/views"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	featuresensors "github.com/diwise/diwise-web/internal/presentation/web/components/features/sensors"
 	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
@@ -23,9 +25,15 @@ import (
 type sensorsApp interface {
 	admin.Management
 	devices.Management
+	GetSuspectDevices(ctx context.Context) ([]string, error)
 }
 
-func NewSensorsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app sensorsApp) http.HandlerFunc {
+type sensorsPageApp interface {
+	sensorsApp
+	views.Lister
+}
+
+func NewSensorsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app sensorsPageApp) http.HandlerFunc {
 	version := helpers.GetVersion(ctx)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
@@ -34,12 +42,17 @@ func NewSensorsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFu
 			v2layout.CurrentComponent, "sensors",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		if views.ApplyDefaultView(w, r, app, "sensors") {
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
 		model, err := composeListModel(ctx, r, app, true)
 		if err != nil {
 			http.Error(w, "could not fetch sensors", http.StatusInternalServerError)
 			return
 		}
+		model.SavedViews = views.SavedViews(ctx, app, "sensors")
 
 		content := featuresensors.SensorsPage(localizer, model)
 		page := templ.Component(v2layout.StartPage(version, localizer, assets, content))
@@ -59,7 +72,7 @@ func NewSensorsTable(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, ap
 			v2layout.CurrentComponent, "sensors",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		model, err := composeListModel(ctx, r, app, false)
 		if err != nil {
 			http.Error(w, "could not fetch sensors", http.StatusInternalServerError)
@@ -80,7 +93,7 @@ func NewSensorsDataList(ctx context.Context, l10n LocaleBundle, assets AssetLoad
 			v2layout.CurrentComponent, "sensors",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		model, err := composeListModel(ctx, r, app, false)
 		if err != nil {
 			http.Error(w, "could not fetch sensors", http.StatusInternalServerError)
@@ -97,7 +110,7 @@ func NewSensorsDataList(ctx context.Context, l10n LocaleBundle, assets AssetLoad
 func composeListModel(ctx context.Context, r *http.Request, app sensorsApp, includePageMeta bool) (featuresensors.SensorsPageViewModel, error) {
 	pageIndex := helpers.UrlParamOrDefault(r, "page", "1")
 	offset, limit := helpers.GetOffsetAndLimit(r)
-	showMap := r.URL.Query().Get("mapview") == "true"
+	showMap := helpers.ShowMapView(r)
 
 	args := r.URL.Query()
 	helpers.SanitizeParams(args, "page", "limit", "offset")
@@ -108,7 +121,13 @@ func composeListModel(ctx context.Context, r *http.Request, app sensorsApp, incl
 		limit = 1000
 	}
 
-	result, err := app.GetDevices(ctx, offset, limit, args)
+	var result devices.DeviceResult
+	var err error
+	if args.Get("suspect") == "true" {
+		result, err = getSuspectDevices(ctx, app, offset, limit, args)
+	} else {
+		result, err = app.GetDevices(ctx, offset, limit, args)
+	}
 	if err != nil {
 		return featuresensors.SensorsPageViewModel{}, err
 	}
@@ -123,10 +142,11 @@ func composeListModel(ctx context.Context, r *http.Request, app sensorsApp, incl
 			Search:        r.URL.Query().Get("search"),
 			LastSeen:      r.URL.Query().Get("lastseen"),
 			LastSeenDate:  parseLastSeen(r.URL.Query().Get("lastseen")),
-			LocaleTag:     localeTagFromHeader(r.Header.Get("Accept-Language")),
+			LocaleTag:     localeTagFromHeader(helpers.Language(r)),
 			SelectedTypes: selectedTypes,
 			Active:        r.URL.Query().Get("active"),
 			Online:        r.URL.Query().Get("online"),
+			Suspect:       r.URL.Query().Get("suspect"),
 			PageSize:      limit,
 		},
 		Paging: featuresensors.PagingViewModel{
@@ -140,8 +160,11 @@ func composeListModel(ctx context.Context, r *http.Request, app sensorsApp, incl
 		},
 	}
 
+	loc := helpers.Location(ctx)
 	for _, device := range result.Devices {
-		model.Sensors = append(model.Sensors, toViewModel(device))
+		sensor := toViewModel(device)
+		sensor.LastSeen = sensor.LastSeen.In(loc)
+		model.Sensors = append(model.Sensors, sensor)
 	}
 
 	if includePageMeta {
@@ -157,6 +180,31 @@ func composeListModel(ctx context.Context, r *http.Request, app sensorsApp, incl
 	return model, nil
 }
 
+// getSuspectDevices returns a page of the devices that the last data quality check found issues in.
+// The backend cannot filter on those, so every device that matches the other filters is fetched and
+// the suspect ones are picked out here.
+func getSuspectDevices(ctx context.Context, app sensorsApp, offset, limit int, args url.Values) (devices.DeviceResult, error) {
+	suspect, err := app.GetSuspectDevices(ctx)
+	if err != nil {
+		return devices.DeviceResult{}, err
+	}
+
+	all := []devices.Device{}
+	if len(suspect) > 0 {
+		filters := maps.Clone(args)
+		filters.Del("suspect")
+
+		all, err = devices.GetAllDevices(ctx, app, filters)
+		if err != nil {
+			return devices.DeviceResult{}, err
+		}
+		all = slices.DeleteFunc(all, func(d devices.Device) bool { return !slices.Contains(suspect, d.DeviceID) })
+	}
+
+	page := all[min(offset, len(all)):min(offset+limit, len(all))]
+	return devices.DeviceResult{Devices: page, TotalRecords: len(all), Count: len(page), Offset: offset, Limit: limit}, nil
+}
+
 func normalizeTypeFilter(args url.Values) []string {
 	rawTypes := args["type"]
 	if len(rawTypes) == 0 {
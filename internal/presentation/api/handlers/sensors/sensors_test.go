@@ -1,6 +1,7 @@
 package sensors
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"testing"
@@ -132,3 +133,36 @@ func TestMeasurementTypeOptionsPrefersProvidedLabels(t *testing.T) {
 	is.Equal("Temperature", options[0].Label)
 	is.Equal(true, options[0].Selected)
 }
+
+func TestGetSuspectDevicesPagesThroughTheSuspectOnes(t *testing.T) {
+	is := is.New(t)
+
+	app := &testSensorsApp{
+		devices: []devices.Device{{DeviceID: "a"}, {DeviceID: "b"}, {DeviceID: "c"}, {DeviceID: "d"}},
+		suspect: []string{"b", "d", "gone"},
+	}
+
+	result, err := getSuspectDevices(context.Background(), app, 1, 1, url.Values{"suspect": {"true"}, "active": {"true"}})
+	is.NoErr(err)
+	is.Equal(2, result.TotalRecords)
+	is.Equal(1, result.Count)
+	is.Equal("d", result.Devices[0].DeviceID)
+	is.Equal(url.Values{"active": {"true"}}, url.Values(app.args))
+}
+
+type testSensorsApp struct {
+	testDeviceApp
+	devices []devices.Device
+	suspect []string
+	args    map[string][]string
+}
+
+func (a *testSensorsApp) GetDevices(_ context.Context, offset, limit int, args map[string][]string) (devices.DeviceResult, error) {
+	a.args = args
+	page := a.devices[min(offset, len(a.devices)):min(offset+limit, len(a.devices))]
+	return devices.DeviceResult{Devices: page, TotalRecords: len(a.devices), Count: len(page), Offset: offset, Limit: limit}, nil
+}
+
+func (a *testSensorsApp) GetSuspectDevices(context.Context) ([]string, error) {
+	return a.suspect, nil
+}
@@ -0,0 +1,167 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/application/preferences"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresettings "github.com/diwise/diwise-web/internal/presentation/web/components/features/settings"
+	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewSettingsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app preferences.Management) http.HandlerFunc {
+	version := helpers.GetVersion(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := helpers.Decorate(
+			r.Context(),
+			v2layout.CurrentComponent, "settings",
+		)
+
+		prefs, err := app.GetPreferences(ctx)
+		if err != nil {
+			http.Error(w, "could not fetch preferences", http.StatusInternalServerError)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		model := featuresettings.SettingsViewModel{
+			PageSize:    prefs.PageSize,
+			ListView:    prefs.ListView,
+			Language:    prefs.Language,
+			TimeZone:    prefs.TimeZone,
+			ChartRange:  prefs.ChartRange,
+			Saved:       r.URL.Query().Get("saved") == "true",
+			PageSizes:   preferences.PageSizes,
+			ListViews:   preferences.ListViews,
+			Languages:   preferences.Languages,
+			TimeZones:   preferences.TimeZones,
+			ChartRanges: preferences.ChartRanges,
+
+			CoordinateSystem:  prefs.CoordinateSystem,
+			CoordinateSystems: coordinateSystemOptions(),
+		}
+
+		settingsPage := featuresettings.SettingsPage(localizer, model)
+		component := templ.Component(v2layout.StartPage(version, localizer, assets, settingsPage))
+		if helpers.IsHxRequest(r) {
+			component = v2layout.AppShell(localizer, assets, settingsPage)
+		}
+
+		helpers.WriteComponentResponse(ctx, w, r, component, 30*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewSaveSettingsHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app preferences.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, "could not parse form", http.StatusBadRequest)
+			return
+		}
+
+		prefs := preferences.Preferences{
+			ListView:   r.Form.Get("listView"),
+			Language:   r.Form.Get("language"),
+			TimeZone:   r.Form.Get("timeZone"),
+			ChartRange: r.Form.Get("chartRange"),
+
+			CoordinateSystem: r.Form.Get("coordinateSystem"),
+		}
+
+		if size := r.Form.Get("pageSize"); size != "" {
+			prefs.PageSize, err = strconv.Atoi(size)
+			if err != nil {
+				http.Error(w, "invalid page size", http.StatusBadRequest)
+				return
+			}
+		}
+
+		_, err = app.SavePreferences(ctx, prefs)
+		if err != nil {
+			if errors.Is(err, preferences.ErrInvalid) {
+				http.Error(w, "invalid preferences", http.StatusBadRequest)
+				return
+			}
+			log.Error("could not save preferences", "err", err.Error())
+			http.Error(w, "could not save preferences", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/settings?saved=true", http.StatusSeeOther)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewUserPreferencesMiddleware loads the preferences of the logged in user and exposes them to the
+// handlers through the request context, where the preferred language is read by helpers.Language.
+func NewUserPreferencesMiddleware(ctx context.Context, app preferences.Management) func(http.Handler) http.Handler {
+	log := logging.GetFromContext(ctx)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !authz.IsLoggedIn(r.Context()) || skipPreferences(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			prefs, err := app.GetPreferences(r.Context())
+			if err != nil {
+				log.Warn("could not load user preferences", "err", err.Error())
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withPreferences(r.Context(), prefs)))
+		})
+	}
+}
+
+func withPreferences(ctx context.Context, prefs preferences.Preferences) context.Context {
+	userPrefs := helpers.UserPreferences{
+		PageSize: prefs.PageSize,
+		MapView:  prefs.ListView == preferences.ListViewMap,
+		Location: prefs.Location(),
+
+		CoordinateSystem: prefs.CoordinateSystem,
+		Language:         prefs.Language,
+	}
+
+	now := time.Now()
+	if userPrefs.Location != nil {
+		now = now.In(userPrefs.Location)
+	}
+	userPrefs.ChartStart, userPrefs.ChartEnd = prefs.ChartBounds(now.Truncate(time.Minute))
+
+	return helpers.WithUserPreferences(ctx, userPrefs)
+}
+
+func skipPreferences(path string) bool {
+	return strings.HasPrefix(path, "/assets/") || strings.HasPrefix(path, "/events/") || path == "/favicon.ico"
+}
+
+func coordinateSystemOptions() []featuresettings.CoordinateSystemOption {
+	options := []featuresettings.CoordinateSystemOption{{Value: geo.WGS84, Label: "WGS 84"}}
+	for _, p := range geo.Projections {
+		options = append(options, featuresettings.CoordinateSystemOption{Value: p.ID, Label: p.Name})
+	}
+	return options
+}
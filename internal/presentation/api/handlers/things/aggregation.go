@@ -0,0 +1,103 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	appmeasurements "github.com/diwise/diwise-web/internal/application/measurements"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// thingMeasurementSeries holds the thing values to chart and, when averaging, the min and max
+// values used to draw a band around the mean.
+type thingMeasurementSeries struct {
+	Thing appthings.Thing
+	Min   *appthings.Thing
+	Max   *appthings.Thing
+}
+
+func fetchThingMeasurementSeries(ctx context.Context, app thingsApp, id string, query url.Values, resolution appmeasurements.Resolution, aggregation appmeasurements.Aggregation) (thingMeasurementSeries, error) {
+	if !aggregatedMeasurementQuery(query, resolution) {
+		thing, err := app.GetThing(ctx, id, query)
+		return thingMeasurementSeries{Thing: thing}, err
+	}
+
+	if aggregation != appmeasurements.AggregationAvg {
+		thing, err := app.GetThing(ctx, id, withMeasurementAggregation(query, resolution, aggregation))
+		return thingMeasurementSeries{Thing: thing}, err
+	}
+
+	aggregations := []appmeasurements.Aggregation{appmeasurements.AggregationAvg, appmeasurements.AggregationMin, appmeasurements.AggregationMax}
+	results := make([]appthings.Thing, len(aggregations))
+	errs := make([]error, len(aggregations))
+
+	var wg sync.WaitGroup
+	for i, aggr := range aggregations {
+		wg.Go(func() {
+			results[i], errs[i] = app.GetThing(ctx, id, withMeasurementAggregation(query, resolution, aggr))
+		})
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return thingMeasurementSeries{}, err
+	}
+
+	return thingMeasurementSeries{Thing: results[0], Min: &results[1], Max: &results[2]}, nil
+}
+
+// aggregatedMeasurementQuery reports whether the backend should aggregate the values for the query.
+// Boolean and stopwatch series already have a dedicated query and are never aggregated here.
+func aggregatedMeasurementQuery(query url.Values, resolution appmeasurements.Resolution) bool {
+	if !resolution.Aggregated() {
+		return false
+	}
+
+	n := query.Get("n")
+	return !strings.HasPrefix(n, "10351/") && !strings.HasPrefix(n, "3302/") && !strings.HasPrefix(n, "3350/")
+}
+
+func withMeasurementAggregation(query url.Values, resolution appmeasurements.Resolution, aggregation appmeasurements.Aggregation) url.Values {
+	q := maps.Clone(query)
+	q.Set("timeunit", string(resolution))
+	q.Set("aggrMethods", string(aggregation))
+	return q
+}
+
+func applyThingMeasurementAggregation(config *shared.AdvancedChartConfig, r *http.Request, l10n Localizer, series thingMeasurementSeries, resolution appmeasurements.Resolution) {
+	if x, ok := config.Options.Scales["x"]; ok && x.Time != nil {
+		x.Time.Unit = resolution.TimeUnit()
+		config.Options.Scales["x"] = x
+	}
+
+	if series.Min == nil || series.Max == nil || len(series.Thing.Values) != 1 {
+		return
+	}
+
+	loc := helpers.Location(r.Context())
+	points := func(values [][]appthings.Measurement) []shared.ChartPoint {
+		result := []shared.ChartPoint{}
+		for _, group := range values {
+			for _, value := range group {
+				if value.Value != nil {
+					result = append(result, shared.ChartPoint{X: value.Timestamp.In(loc).Format("2006-01-02 15:04"), Y: *value.Value})
+				}
+			}
+		}
+		return result
+	}
+
+	color := shared.ChartBandColor(helpers.IsDarkMode(r))
+	config.Data.Datasets = append(config.Data.Datasets,
+		shared.BandDatasets(l10n.Get("aggregationmin"), l10n.Get("aggregationmax"), color, points(series.Min.Values), points(series.Max.Values))...,
+	)
+}
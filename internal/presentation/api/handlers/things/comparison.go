@@ -0,0 +1,141 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	appmeasurements "github.com/diwise/diwise-web/internal/application/measurements"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// fetchComparisonValues fetches the values of the period that the window from start to end is
+// compared with, moved forward in time so that they line up with the window on the chart.
+func fetchComparisonValues(ctx context.Context, app thingsApp, id, measurement string, comparison appmeasurements.Comparison, start, end time.Time, resolution appmeasurements.Resolution, aggregation appmeasurements.Aggregation) (appthings.Thing, error) {
+	from, to := comparison.Shift(start, end)
+
+	query := measurementQuery(measurement, from, to)
+	if aggregatedMeasurementQuery(query, resolution) {
+		query = withMeasurementAggregation(query, resolution, aggregation)
+	}
+
+	thing, err := app.GetThing(ctx, id, query)
+	if err != nil {
+		return appthings.Thing{}, err
+	}
+
+	offset := start.Sub(from)
+	values := make([][]appthings.Measurement, 0, len(thing.Values))
+	for _, group := range thing.Values {
+		shifted := make([]appthings.Measurement, 0, len(group))
+		for _, value := range group {
+			value.Timestamp = value.Timestamp.Add(offset)
+			shifted = append(shifted, value)
+		}
+		values = append(values, shifted)
+	}
+	thing.Values = values
+
+	return thing, nil
+}
+
+// applyThingMeasurementComparison draws the values of the earlier period as dashed lines in the
+// colours of the series they are compared with.
+func applyThingMeasurementComparison(config *shared.AdvancedChartConfig, r *http.Request, l10n Localizer, measurement string, comparison appmeasurements.Comparison, previous appthings.Thing) {
+	isDark := helpers.IsDarkMode(r)
+	loc := helpers.Location(r.Context())
+
+	for index, group := range previous.Values {
+		dataset := thingMeasurementDataset(l10n, measurement, group, index, isDark, loc)
+		dataset.Label = fmt.Sprintf("%s (%s)", dataset.Label, l10n.Get("comparison"+string(comparison)))
+		dataset.BorderDash = []int{6, 4}
+		dataset.BorderWidth = 1
+		dataset.PointRadius = 0
+		if thingChartType(measurement) != "line" {
+			dataset.Type = "line"
+		}
+		config.Data.Datasets = append(config.Data.Datasets, dataset)
+	}
+}
+
+// comparisonViewModel summarises how the values of the window have changed since the earlier
+// period. Only numeric values are compared.
+func comparisonViewModel(l10n Localizer, comparison appmeasurements.Comparison, current, previous appthings.Thing, start, end time.Time, unit string, loc *time.Location) *featuresthings.MeasurementComparisonViewModel {
+	from, to := comparison.Shift(start, end)
+
+	model := &featuresthings.MeasurementComparisonViewModel{
+		Label:  l10n.Get("comparison" + string(comparison)),
+		Period: fmt.Sprintf("%s – %s", from.In(loc).Format("2006-01-02 15:04"), to.In(loc).Format("2006-01-02 15:04")),
+	}
+
+	now, ok := appmeasurements.StatsOf(numericValues(current.Values))
+	if !ok {
+		return model
+	}
+	before, ok := appmeasurements.StatsOf(numericValues(previous.Values))
+	if !ok {
+		return model
+	}
+
+	format := func(value float64) string {
+		if unit != "" {
+			return fmt.Sprintf("%.1f %s", value, unit)
+		}
+		return fmt.Sprintf("%.1f", value)
+	}
+
+	delta := func(label string, current, previous float64) featuresthings.MeasurementDeltaViewModel {
+		diff, percent := appmeasurements.Change(current, previous)
+
+		d := featuresthings.MeasurementDeltaViewModel{
+			Label:    l10n.Get(label),
+			Current:  format(current),
+			Previous: format(previous),
+			Change:   strings.TrimSpace(fmt.Sprintf("%+.1f %s", diff, unit)),
+		}
+		if percent != nil {
+			d.Change = fmt.Sprintf("%s (%+.0f %%)", d.Change, *percent)
+		}
+
+		switch {
+		case math.Round(diff*10) > 0:
+			d.Trend = "up"
+		case math.Round(diff*10) < 0:
+			d.Trend = "down"
+		}
+
+		return d
+	}
+
+	model.Stats = []featuresthings.MeasurementDeltaViewModel{
+		delta("aggregationavg", now.Mean, before.Mean),
+		delta("aggregationmin", now.Min, before.Min),
+		delta("aggregationmax", now.Max, before.Max),
+		delta("aggregationsum", now.Sum, before.Sum),
+	}
+
+	return model
+}
+
+func numericValues(groups [][]appthings.Measurement) []float64 {
+	values := make([]float64, 0, countMeasurements(groups))
+	for _, group := range groups {
+		for _, value := range group {
+			switch {
+			case value.Value != nil:
+				values = append(values, *value.Value)
+			case value.Count != nil:
+				values = append(values, *value.Count)
+			}
+		}
+	}
+	return values
+}
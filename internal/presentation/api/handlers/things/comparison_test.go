@@ -0,0 +1,55 @@
+package things
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appmeasurements "github.com/diwise/diwise-web/internal/application/measurements"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/matryer/is"
+)
+
+func TestComparisonValuesAreAlignedWithTheWindow(t *testing.T) {
+	is := is.New(t)
+	start := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 15, 23, 59, 0, 0, time.UTC)
+
+	lastWeek := []appthings.Measurement{
+		{Timestamp: start.AddDate(0, 0, -7).Add(time.Hour), Value: new(4.0)},
+		{Timestamp: start.AddDate(0, 0, -6), Value: new(6.0)},
+	}
+	app := &testThingsApp{thing: appthings.Thing{ID: "bin-1", Values: [][]appthings.Measurement{lastWeek}}}
+
+	previous, err := fetchComparisonValues(context.Background(), app, "bin-1", "3435-2", appmeasurements.ComparisonWeek, start, end, appmeasurements.ResolutionRaw, appmeasurements.AggregationAvg)
+	is.NoErr(err)
+	is.Equal(start.Add(time.Hour), previous.Values[0][0].Timestamp)
+	is.Equal(start.AddDate(0, 0, 1), previous.Values[0][1].Timestamp)
+
+	config := shared.AdvancedChartConfig{}
+	req := httptest.NewRequest("GET", "/components/things/bin-1/measurements", nil)
+	applyThingMeasurementComparison(&config, req, noopLocalizer{}, "3435-2", appmeasurements.ComparisonWeek, previous)
+	is.Equal(1, len(config.Data.Datasets))
+	is.Equal([]int{6, 4}, config.Data.Datasets[0].BorderDash)
+	is.Equal(shared.ChartPoint{X: "2026-03-09 01:00", Y: 4.0}, config.Data.Datasets[0].Data[0])
+
+	current := appthings.Thing{Values: [][]appthings.Measurement{{
+		{Timestamp: start.Add(time.Hour), Value: new(6.0)},
+		{Timestamp: start.AddDate(0, 0, 1), Value: new(6.0)},
+	}}}
+
+	model := comparisonViewModel(noopLocalizer{}, appmeasurements.ComparisonWeek, current, previous, start, end, "%", time.UTC)
+	is.Equal("comparisonweek", model.Label)
+	is.Equal("2026-03-02 00:00 – 2026-03-08 23:59", model.Period)
+	is.Equal(4, len(model.Stats))
+	is.Equal("6.0 %", model.Stats[0].Current)
+	is.Equal("5.0 %", model.Stats[0].Previous)
+	is.Equal("+1.0 % (+20 %)", model.Stats[0].Change)
+	is.Equal("up", model.Stats[0].Trend)
+	is.Equal("", model.Stats[2].Trend) // the max is 6 in both weeks
+
+	model = comparisonViewModel(noopLocalizer{}, appmeasurements.ComparisonWeek, current, appthings.Thing{}, start, end, "", time.UTC)
+	is.Equal(0, len(model.Stats))
+}
@@ -0,0 +1,144 @@
+package things
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	appwatermeters "github.com/diwise/diwise-web/internal/application/watermeters"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingConsumptionComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appwatermeters.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		loc := helpers.Location(ctx)
+
+		period, err := helpers.ParseDateRange(r, time.Now().In(loc).AddDate(0, 0, 1-appwatermeters.DefaultDays))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		consumption, err := app.GetConsumption(ctx, id, period.From, period.To)
+		if err != nil {
+			log.Error("could not fetch water consumption", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch water consumption", http.StatusInternalServerError)
+			return
+		}
+
+		groupBy := r.URL.Query().Get("groupby")
+		if groupBy != "week" && groupBy != "month" {
+			groupBy = "day"
+		}
+
+		model := waterConsumptionViewModel(period, consumption, groupBy)
+		model.Chart = consumptionChartConfig(r, localizer, model.Rows, nightFlows(groupBy, consumption))
+
+		component := featuresthings.ThingConsumption(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 24*1024, time.Minute)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func waterConsumptionViewModel(period helpers.DateRange, c appwatermeters.Consumption, groupBy string) featuresthings.WaterConsumptionViewModel {
+	model := featuresthings.WaterConsumptionViewModel{
+		ThingID:      c.ThingID,
+		From:         period.FirstDay(),
+		To:           period.LastDay(),
+		GroupBy:      groupBy,
+		Total:        c.Total,
+		Resets:       c.Resets,
+		Flags:        c.Flags.Names(),
+		ProbableLeak: c.ProbableLeak,
+		LeakSince:    c.LeakSince,
+	}
+
+	if len(c.Nights) > 0 {
+		model.LastNightFlow = new(c.Nights[len(c.Nights)-1].MinFlow * 1000)
+	}
+
+	buckets := c.Days
+	switch groupBy {
+	case "week":
+		buckets = c.Weeks
+	case "month":
+		buckets = c.Months
+	}
+
+	for _, b := range buckets {
+		model.Rows = append(model.Rows, featuresthings.ConsumptionBucketViewModel{
+			Label:  bucketLabel(groupBy, b.Start),
+			Volume: b.Volume,
+		})
+	}
+
+	return model
+}
+
+// nightFlows lines up the lowest night flow in l/h with the days of the chart. Nights only make
+// sense per day, so nothing is returned for weeks and months.
+func nightFlows(groupBy string, c appwatermeters.Consumption) []any {
+	if groupBy != "day" {
+		return nil
+	}
+
+	flows := make([]any, len(c.Days))
+	for i, day := range c.Days {
+		idx := slices.IndexFunc(c.Nights, func(n appwatermeters.Night) bool { return n.Date.Equal(day.Start) })
+		if idx >= 0 {
+			flows[i] = math.Round(c.Nights[idx].MinFlow * 1000)
+		}
+	}
+	return flows
+}
+
+func consumptionChartConfig(r *http.Request, l10n Localizer, rows []featuresthings.ConsumptionBucketViewModel, nightFlows []any) shared.AdvancedChartConfig {
+	isDark := helpers.IsDarkMode(r)
+	theme := chartTheme(isDark)
+
+	labels := make([]string, 0, len(rows))
+	volumes := make([]any, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, row.Label)
+		volumes = append(volumes, math.Round(row.Volume*1000)/1000)
+	}
+
+	datasets := []shared.AdvancedChartDataset{
+		{Label: l10n.Get("consumption") + " (m³)", Data: volumes, YAxisID: "y", BackgroundColor: thingChartColor(2, isDark)},
+	}
+	axes := map[string]shared.AxisScale{
+		"y": periodValueAxis(theme, "left", "m³", true),
+	}
+	if nightFlows != nil {
+		datasets = append(datasets, shared.AdvancedChartDataset{
+			Type:        "line",
+			Label:       l10n.Get("nightflow") + " (l/h)",
+			Data:        nightFlows,
+			YAxisID:     "y1",
+			BorderColor: thingChartColor(1, isDark),
+			BorderWidth: 2,
+			PointRadius: 2,
+		})
+		axes["y1"] = periodValueAxis(theme, "right", "l/h", false)
+	}
+
+	return periodBarChartConfig(theme, labels, datasets, axes)
+}
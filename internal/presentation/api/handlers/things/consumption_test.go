@@ -0,0 +1,31 @@
+package things
+
+import (
+	"testing"
+	"time"
+
+	appwatermeters "github.com/diwise/diwise-web/internal/application/watermeters"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestWaterConsumptionViewModelUsesLatestNightInLitres(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	c := appwatermeters.Consumption{
+		ThingID: "wm-1",
+		Months:  []appwatermeters.Bucket{{Start: day, Volume: 12.5}},
+		Days:    []appwatermeters.Bucket{{Start: day}, {Start: day.AddDate(0, 0, 1)}},
+		Nights:  []appwatermeters.Night{{Date: day.AddDate(0, 0, 1), MinFlow: 0.025}},
+		Flags:   appwatermeters.Flags{Leakage: true, Backflow: true},
+	}
+
+	model := waterConsumptionViewModel(helpers.DateRange{From: day, To: day.AddDate(0, 0, 2)}, c, "month")
+	is.Equal(25.0, *model.LastNightFlow)
+	is.Equal([]string{"backflow", "leakage"}, model.Flags)
+	is.Equal("2025-03", model.Rows[0].Label)
+
+	is.Equal(nil, nightFlows("month", c))
+	is.Equal([]any{nil, 25.0}, nightFlows("day", c))
+}
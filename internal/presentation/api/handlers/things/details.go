@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"net/http"
 	"net/url"
@@ -14,8 +15,15 @@ import (
 	"time"
 
 	"github.com/a-h/templ"
-	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	apphistory "github.com/diwise/diwise-web/internal/application/history"
+	appmeasurements "github.com/diwise/diwise-web/internal/application/measurements"
+	appnotes "github.com/diwise/diwise-web/internal/application/notes"
+	"github.com/diwise/diwise-web/internal/application/properties"
+	appquality "github.com/diwise/diwise-web/internal/application/quality"
 	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/notes"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/quality"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
 	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
@@ -29,6 +37,8 @@ import (
 
 func NewThingDetailsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app thingsApp) http.HandlerFunc {
 	version := helpers.GetVersion(ctx)
+	bounds := helpers.GetMunicipalBounds(ctx)
+	schemas := helpers.GetPropertySchemas(ctx)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
@@ -42,9 +52,9 @@ func NewThingDetailsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoa
 			v2layout.CurrentComponent, "things",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		editMode := r.URL.Query().Get("mode") == "edit"
-		model, err := composeDetailsModel(ctx, id, app, editMode)
+		model, err := composeDetailsModel(ctx, id, app, editMode, schemas)
 		if err != nil {
 			http.Error(w, "could not fetch thing", http.StatusInternalServerError)
 			return
@@ -52,6 +62,8 @@ func NewThingDetailsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoa
 
 		content := featuresthings.ThingDetailsPage(localizer, model)
 		if editMode {
+			model.Bounds = boundsViewModel(bounds)
+			model.Grids = gridOptions(r.Context())
 			content = featuresthings.EditThingDetailsPage(localizer, model)
 		}
 
@@ -68,6 +80,8 @@ func NewThingDetailsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoa
 
 func NewSaveThingDetailsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app thingsApp) http.HandlerFunc {
 	version := helpers.GetVersion(ctx)
+	bounds := helpers.GetMunicipalBounds(ctx)
+	schemas := helpers.GetPropertySchemas(ctx)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		id := r.PathValue("id")
@@ -81,22 +95,24 @@ func NewSaveThingDetailsPage(ctx context.Context, l10n LocaleBundle, assets Asse
 			return
 		}
 
-		fields, err := buildThingUpdateFields(r.Context(), app, id, r.Form)
+		fields, err := buildThingUpdateFields(r.Context(), app, id, r.Form, bounds, schemas)
 		if err != nil {
-			localizer := l10n.For(r.Header.Get("Accept-Language"))
+			localizer := l10n.For(helpers.Language(r))
 			message := localizeThingValidationMessage(localizer, err)
 			if helpers.IsHxRequest(r) {
 				helpers.WriteComponentResponse(r.Context(), w, r, thingValidationToast(message), 4*1024, 0)
 				return
 			}
 
-			model, modelErr := composeDetailsModel(r.Context(), id, app, true)
+			model, modelErr := composeDetailsModel(r.Context(), id, app, true, schemas)
 			if modelErr != nil {
 				http.Error(w, "could not fetch thing", http.StatusInternalServerError)
 				return
 			}
 			applySubmittedThingDetailsForm(&model, r.Form)
 			model.ToastMessage = message
+			model.Bounds = boundsViewModel(bounds)
+			model.Grids = gridOptions(r.Context())
 
 			content := featuresthings.EditThingDetailsPage(localizer, model)
 			page := templ.Component(v2layout.StartPage(version, localizer, assets, content))
@@ -146,8 +162,17 @@ func NewDeleteThingDetailsPage(_ context.Context, _ LocaleBundle, _ AssetLoaderF
 	return http.HandlerFunc(fn)
 }
 
-func NewThingMeasurementComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+// thingMeasurementApp also marks the maintenance log of the thing and its sensors, and when sensors
+// were connected or disconnected, on the chart.
+type thingMeasurementApp interface {
+	thingsApp
+	appnotes.Management
+	apphistory.Management
+}
+
+func NewThingMeasurementComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingMeasurementApp) http.HandlerFunc {
 	log := logging.GetFromContext(ctx)
+	pointBudget := helpers.GetChartPointBudget(ctx)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ctx := logging.NewContextWithLogger(r.Context(), log)
@@ -159,16 +184,24 @@ func NewThingMeasurementComponentHandler(ctx context.Context, l10n LocaleBundle,
 
 		activeMeasurement := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("measurement")))
 		if activeMeasurement == "" {
-			component := featuresthings.ThingMeasurementContent(l10n.For(r.Header.Get("Accept-Language")), featuresthings.ThingMeasurementPanelProps{
+			component := featuresthings.ThingMeasurementContent(l10n.For(helpers.Language(r)), featuresthings.ThingMeasurementPanelProps{
 				Empty: true,
 			})
 			helpers.WriteComponentResponse(ctx, w, r, component, 8*1024, 0)
 			return
 		}
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
-		startTime := getThingTime(r, "timeAt", startOfDay(time.Now().UTC()))
-		endTime := getThingTime(r, "endTimeAt", endOfDay(time.Now().UTC()))
+		localizer := l10n.For(helpers.Language(r))
+		loc := helpers.Location(ctx)
+		defaultStart, defaultEnd := startOfDay(time.Now().In(loc)), endOfDay(time.Now().In(loc))
+		if prefs := helpers.GetUserPreferences(ctx); !prefs.ChartStart.IsZero() {
+			defaultStart, defaultEnd = prefs.ChartStart, prefs.ChartEnd
+		}
+		startTime := getThingTime(r, "timeAt", defaultStart)
+		endTime := getThingTime(r, "endTimeAt", defaultEnd)
+		resolution := appmeasurements.ParseResolution(r.URL.Query().Get("resolution")).Resolve(startTime, endTime)
+		aggregation := appmeasurements.ParseAggregation(r.URL.Query().Get("aggregation"))
+		comparison := appmeasurements.ParseComparison(r.URL.Query().Get("compare"))
 		query := measurementQuery(activeMeasurement, startTime, endTime)
 		latestValues, err := app.GetLatestValues(ctx, id)
 		if err != nil {
@@ -176,18 +209,46 @@ func NewThingMeasurementComponentHandler(ctx context.Context, l10n LocaleBundle,
 			return
 		}
 
-		thing, err := app.GetThing(ctx, id, query)
+		series, err := fetchThingMeasurementSeries(ctx, app, id, query, resolution, aggregation)
 		if err != nil {
 			http.Error(w, "could not fetch thing measurements", http.StatusInternalServerError)
 			return
 		}
+		thing := series.Thing
+
+		config := thingMeasurementChartConfig(r, localizer, activeMeasurement, downsampleThingValues(thing, pointBudget))
+		applyThingMeasurementAggregation(&config, r, localizer, series, resolution)
+		notes.Annotate(ctx, &config, localizer, app, appnotes.KindThing, id, startTime, endTime)
+		for _, ref := range thing.RefDevices {
+			notes.Annotate(ctx, &config, localizer, app, appnotes.KindDevice, ref.DeviceID, startTime, endTime)
+		}
+		annotateSensorChanges(ctx, &config, localizer, app, id, startTime, endTime)
+		if !resolution.Aggregated() {
+			for _, group := range thing.Values {
+				quality.Annotate(ctx, &config, localizer, appquality.ThingPoints(group), 0, endTime)
+			}
+		}
+
+		summary := latestMeasurementViewModel(id, latestValues, activeMeasurement)
+
+		var comparisonModel *featuresthings.MeasurementComparisonViewModel
+		if comparison != appmeasurements.ComparisonNone {
+			previous, err := fetchComparisonValues(ctx, app, id, activeMeasurement, comparison, startTime, endTime, resolution, aggregation)
+			if err != nil {
+				log.Warn("could not fetch values to compare with", "thing_id", id, "err", err.Error())
+			} else {
+				applyThingMeasurementComparison(&config, r, localizer, activeMeasurement, comparison, downsampleThingValues(previous, pointBudget))
+				comparisonModel = comparisonViewModel(localizer, comparison, thing, previous, startTime, endTime, summary.Unit, loc)
+			}
+		}
 
 		content := featuresthings.ThingMeasurementContent(localizer, featuresthings.ThingMeasurementPanelProps{
-			Chart:               featuresthings.ThingMeasurementChartComponent(thingMeasurementChartConfig(r, localizer, activeMeasurement, thing)),
-			Rows:                measurementRows(thing.Values),
+			Chart:               featuresthings.ThingMeasurementChartComponent(config),
+			Rows:                measurementRows(thing.Values, loc),
 			Empty:               len(thing.Values) == 0 || countMeasurements(thing.Values) == 0,
-			SummaryMeasurement:  latestMeasurementViewModel(id, latestValues, activeMeasurement),
+			SummaryMeasurement:  summary,
 			SelectedMeasurement: activeMeasurement,
+			Comparison:          comparisonModel,
 		})
 		helpers.WriteComponentResponse(ctx, w, r, content, 24*1024, 5*time.Minute)
 	}
@@ -199,7 +260,7 @@ func NewCompatibleSensorSearchOptionsHandler(_ context.Context, l10n LocaleBundl
 	fn := func(w http.ResponseWriter, r *http.Request) {
 
 		query := strings.TrimSpace(r.URL.Query().Get("q"))
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		thingID := strings.TrimSpace(r.URL.Query().Get("thingID"))
 		if thingID == "" {
 			http.Error(w, "missing thing id", http.StatusBadRequest)
@@ -245,7 +306,7 @@ func NewCompatibleSensorSearchOptionsHandler(_ context.Context, l10n LocaleBundl
 	return http.HandlerFunc(fn)
 }
 
-func composeDetailsModel(ctx context.Context, id string, app thingsApp, includeEditOptions bool) (featuresthings.ThingDetailsPageViewModel, error) {
+func composeDetailsModel(ctx context.Context, id string, app thingsApp, includeEditOptions bool, schemas properties.Schemas) (featuresthings.ThingDetailsPageViewModel, error) {
 	thing, err := app.GetThing(ctx, id, nil)
 	if err != nil {
 		return featuresthings.ThingDetailsPageViewModel{}, err
@@ -263,7 +324,12 @@ func composeDetailsModel(ctx context.Context, id string, app thingsApp, includeE
 		AllowsMultipleConnectedSensors: allowsMultipleConnectedSensors(thing),
 		ValidSensors:                   make([]featuresthings.SensorOption, 0),
 		MeasurementOptions:             make([]featuresthings.MeasurementOption, 0, len(latestValues)),
+		ChartStart:                     helpers.GetUserPreferences(ctx).ChartStart,
+		ChartEnd:                       helpers.GetUserPreferences(ctx).ChartEnd,
+		Grid:                           helpers.GridPosition(ctx, thing.Location.Latitude, thing.Location.Longitude),
 	}
+	model.Thing.PropertyFields = propertyFieldsOf(schemas, thing)
+	loc := helpers.Location(ctx)
 
 	for _, ref := range thing.RefDevices {
 		if ref.DeviceID == "" || slices.ContainsFunc(model.ConnectedSensors, func(sensor featuresthings.ConnectedSensorViewModel) bool {
@@ -279,7 +345,7 @@ func composeDetailsModel(ctx context.Context, id string, app thingsApp, includeE
 		item := featuresthings.LatestMeasurementViewModel{
 			ID:        measurement.ID,
 			Label:     label,
-			Timestamp: measurement.Timestamp,
+			Timestamp: measurement.Timestamp.In(loc),
 			Unit:      measurement.Unit,
 			Value:     measurement.Value,
 			BoolValue: measurement.BoolValue,
@@ -351,7 +417,7 @@ func allowsMultipleConnectedSensors(thing appthings.Thing) bool {
 	}
 
 	switch kind {
-	case "room", "pointofinterest", "beach":
+	case "room", "pointofinterest", "beach", "sandstorage":
 		return true
 	default:
 		return false
@@ -397,12 +463,10 @@ func latestMeasurementViewModel(thingID string, measurements []appthings.Measure
 	return featuresthings.LatestMeasurementViewModel{Label: selected}
 }
 
-func buildThingUpdateFields(ctx context.Context, app thingsApp, thingID string, form url.Values) (map[string]any, error) {
-	asFloat := func(s string) (float64, bool) {
-		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
-			return f, true
-		}
-		return 0, false
+func buildThingUpdateFields(ctx context.Context, app thingsApp, thingID string, form url.Values, bounds geo.BoundingBox, schemas properties.Schemas) (map[string]any, error) {
+	thing, err := app.GetThing(ctx, thingID, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	fields := map[string]any{}
@@ -410,7 +474,7 @@ func buildThingUpdateFields(ctx context.Context, app thingsApp, thingID string,
 		fields["tags"] = tags
 	}
 	currentDeviceValues, hasCurrentDeviceField := form["currentDevice"]
-	if refs, err := resolveConnectedSensorRefs(ctx, app, thingID, currentDeviceValues); err != nil {
+	if refs, err := resolveConnectedSensorRefs(ctx, app, thing, currentDeviceValues); err != nil {
 		return nil, err
 	} else if hasCurrentDeviceField && len(refs) == 0 {
 		fields["refDevices"] = []appthings.RefDevice{}
@@ -432,45 +496,74 @@ func buildThingUpdateFields(ctx context.Context, app thingsApp, thingID string,
 		fields["tenant"] = organisation
 	}
 
-	for _, key := range []string{"latitude", "longitude"} {
-		if value := strings.TrimSpace(form.Get(key)); value != "" {
-			if _, ok := fields["location"]; !ok {
-				fields["location"] = client.Location{}
-			}
-			if parsed, ok := asFloat(value); ok {
-				location := fields["location"].(client.Location)
-				if key == "latitude" {
-					location.Latitude = parsed
-				} else {
-					location.Longitude = parsed
-				}
-				fields["location"] = location
-			}
-		}
+	if location, ok, err := locationFromForm(form, bounds); err != nil {
+		return nil, err
+	} else if ok {
+		fields["location"] = location
 	}
 
-	for _, key := range []string{"maxl", "maxd", "angle", "offset"} {
-		if value := strings.TrimSpace(form.Get(key)); value != "" {
-			if parsed, ok := asFloat(value); ok {
-				fields[key] = parsed
-			}
-		}
+	values, err := propertyFields(schemas, thing.Type, thing.SubType, form)
+	if err != nil {
+		return nil, err
 	}
+	maps.Copy(fields, values)
 
 	return fields, nil
 }
 
-func resolveConnectedSensorRefs(ctx context.Context, app thingsApp, thingID string, submittedValues []string) ([]appthings.RefDevice, error) {
+// propertyFields reads the properties of the type of a thing, such as the height of a container, and
+// checks them against the schema of the type. Types without a schema have no properties to set.
+func propertyFields(schemas properties.Schemas, thingType, subType string, form url.Values) (map[string]any, error) {
+	schema, ok := schemas.For(thingType, subType)
+	if !ok {
+		return map[string]any{}, nil
+	}
+	return schema.Values(form)
+}
+
+// propertyFieldsOf describes the properties that a thing has according to the schema of its type.
+func propertyFieldsOf(schemas properties.Schemas, thing appthings.Thing) []featuresthings.PropertyField {
+	schema, ok := schemas.For(thing.Type, thing.SubType)
+	if !ok {
+		return nil
+	}
+
+	number := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+
+	fields := make([]featuresthings.PropertyField, 0, len(schema.Fields))
+	for _, field := range schema.Fields {
+		step := "any"
+		if field.Step > 0 {
+			step = strconv.FormatFloat(field.Step, 'f', -1, 64)
+		} else if field.Type == properties.TypeInteger {
+			step = "1"
+		}
+
+		fields = append(fields, featuresthings.PropertyField{
+			Name:     field.Name,
+			Label:    field.Label,
+			Unit:     field.Unit,
+			Step:     step,
+			Min:      number(field.Min),
+			Max:      number(field.Max),
+			Decimals: field.Decimals(),
+			Required: field.Required,
+		})
+	}
+	return fields
+}
+
+func resolveConnectedSensorRefs(ctx context.Context, app thingsApp, thing appthings.Thing, submittedValues []string) ([]appthings.RefDevice, error) {
 	values := normalizeListValues(submittedValues)
 	if len(values) == 0 {
 		return nil, nil
 	}
 
-	thing, err := app.GetThing(ctx, thingID, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	refs := make([]appthings.RefDevice, 0, len(values))
 	for _, value := range values {
 		deviceID, err := resolveConnectedSensorDeviceID(ctx, app, thing.ValidURNs, value)
@@ -560,6 +653,18 @@ func applySubmittedThingDetailsForm(model *featuresthings.ThingDetailsPageViewMo
 			Label:    sensor,
 		})
 	}
+
+	for _, property := range model.Thing.PropertyFields {
+		if _, ok := form[property.Name]; !ok {
+			continue
+		}
+		value := strings.ReplaceAll(strings.TrimSpace(form.Get(property.Name)), ",", ".")
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			model.Thing.Properties[property.Name] = number
+		} else {
+			delete(model.Thing.Properties, property.Name)
+		}
+	}
 }
 
 func thingValidationToast(message string) templ.Component {
@@ -588,9 +693,38 @@ func localizeThingValidationMessage(localizer Localizer, err error) string {
 		})
 	}
 
+	var fieldErr properties.FieldError
+	if errors.As(err, &fieldErr) {
+		return localizePropertyError(localizer, fieldErr)
+	}
+
+	if errors.Is(err, geo.ErrInvalid) || errors.Is(err, geo.ErrOutside) || errors.Is(err, errNoLocation) {
+		return localizeLocationError(localizer, err)
+	}
+
 	return err.Error()
 }
 
+func localizePropertyError(localizer Localizer, err properties.FieldError) string {
+	data := map[string]any{"property": localizer.Get(err.Field.Label)}
+
+	switch {
+	case errors.Is(err, properties.ErrRequired):
+		return localizer.GetWithData("propertyrequired", data)
+	case errors.Is(err, properties.ErrTooSmall):
+		data["min"] = strconv.FormatFloat(*err.Field.Min, 'f', -1, 64)
+		return localizer.GetWithData("propertytoosmall", data)
+	case errors.Is(err, properties.ErrTooLarge):
+		data["max"] = strconv.FormatFloat(*err.Field.Max, 'f', -1, 64)
+		return localizer.GetWithData("propertytoolarge", data)
+	case errors.Is(err, properties.ErrStep):
+		data["step"] = strconv.FormatFloat(err.Field.Step, 'f', -1, 64)
+		return localizer.GetWithData("propertystep", data)
+	default:
+		return localizer.GetWithData("propertyinvalid", data)
+	}
+}
+
 func getThingTime(r *http.Request, key string, def time.Time) time.Time {
 	layout := "2006-01-02T15:04"
 	value := strings.TrimSpace(r.URL.Query().Get(key))
@@ -598,7 +732,7 @@ func getThingTime(r *http.Request, key string, def time.Time) time.Time {
 		return def
 	}
 
-	parsed, err := time.Parse(layout, value)
+	parsed, err := time.ParseInLocation(layout, value, def.Location())
 	if err != nil {
 		return def
 	}
@@ -645,10 +779,10 @@ func measurementQuery(measurement string, startTime, endTime time.Time) url.Valu
 func thingMeasurementChartConfig(r *http.Request, l10n Localizer, measurement string, thing appthings.Thing) shared.AdvancedChartConfig {
 	isDark := helpers.IsDarkMode(r)
 	theme := chartTheme(isDark)
+	loc := helpers.Location(r.Context())
 	datasets := make([]shared.AdvancedChartDataset, 0, len(thing.Values))
-	labels := thingMeasurementLabels(thing.Values)
 	for index, group := range thing.Values {
-		datasets = append(datasets, thingMeasurementDataset(l10n, measurement, group, index, isDark))
+		datasets = append(datasets, thingMeasurementDataset(l10n, measurement, group, index, isDark, loc))
 	}
 	if len(datasets) == 0 {
 		datasets = append(datasets, shared.AdvancedChartDataset{
@@ -678,7 +812,6 @@ func thingMeasurementChartConfig(r *http.Request, l10n Localizer, measurement st
 	return shared.AdvancedChartConfig{
 		Type: thingChartType(measurement),
 		Data: shared.AdvancedChartData{
-			Labels:   labels,
 			Datasets: datasets,
 		},
 		Options: shared.AdvancedChartOptions{
@@ -766,7 +899,7 @@ func maxDistanceChartMeasurement(measurement string) bool {
 	return strings.HasSuffix(urn, "/3")
 }
 
-func thingMeasurementDataset(l10n Localizer, measurement string, values []appthings.Measurement, index int, isDark bool) shared.AdvancedChartDataset {
+func thingMeasurementDataset(l10n Localizer, measurement string, values []appthings.Measurement, index int, isDark bool, loc *time.Location) shared.AdvancedChartDataset {
 	color := thingChartColor(index, isDark)
 	dataset := shared.AdvancedChartDataset{
 		Label:                localizedMeasurementSeriesLabel(l10n, measurement, values, index),
@@ -785,48 +918,76 @@ func thingMeasurementDataset(l10n Localizer, measurement string, values []appthi
 
 	previousBool := 0
 	for _, value := range values {
+		x := value.Timestamp.In(loc).Format("2006-01-02 15:04")
 		switch {
 		case value.Value != nil:
-			dataset.Data = append(dataset.Data, *value.Value)
+			dataset.Data = append(dataset.Data, shared.ChartPoint{X: x, Y: *value.Value})
 		case value.Count != nil:
-			dataset.Data = append(dataset.Data, *value.Count)
+			dataset.Data = append(dataset.Data, shared.ChartPoint{X: x, Y: *value.Count})
 		case value.BoolValue != nil:
 			current := 0
 			if *value.BoolValue {
 				current = 1
 			}
 			if current != previousBool {
-				dataset.Data = append(dataset.Data, previousBool)
+				dataset.Data = append(dataset.Data, shared.ChartPoint{X: x, Y: previousBool})
 				previousBool = current
 			}
-			dataset.Data = append(dataset.Data, current)
+			dataset.Data = append(dataset.Data, shared.ChartPoint{X: x, Y: current})
 		default:
-			dataset.Data = append(dataset.Data, nil)
+			dataset.Data = append(dataset.Data, shared.ChartPoint{X: x, Y: nil})
 		}
 	}
 
 	return dataset
 }
 
-func localizedMeasurementSeriesLabel(l10n Localizer, measurement string, values []appthings.Measurement, index int) string {
-	if measurement != "" {
-		return featuresthings.LocalizedMeasurementLabel(l10n, measurement)
-	}
-	return fmt.Sprintf("Series %d", index+1)
-}
+// downsampleThingValues reduces every numeric series of the thing to at most budget points while preserving
+// the shape of the series. Boolean series are left untouched since dropping points would hide state changes.
+func downsampleThingValues(thing appthings.Thing, budget int) appthings.Thing {
+	values := make([][]appthings.Measurement, 0, len(thing.Values))
 
-func thingMeasurementLabels(groups [][]appthings.Measurement) []string {
-	for _, group := range groups {
-		if len(group) == 0 {
+	for _, group := range thing.Values {
+		if len(group) <= budget {
+			values = append(values, group)
 			continue
 		}
-		labels := make([]string, 0, len(group))
+
+		x := make([]float64, 0, len(group))
+		y := make([]float64, 0, len(group))
 		for _, value := range group {
-			labels = append(labels, value.Timestamp.Format("2006-01-02 15:04"))
+			switch {
+			case value.Value != nil:
+				y = append(y, *value.Value)
+			case value.Count != nil:
+				y = append(y, *value.Count)
+			default:
+				continue
+			}
+			x = append(x, float64(value.Timestamp.Unix()))
 		}
-		return labels
+
+		if len(y) != len(group) {
+			values = append(values, group)
+			continue
+		}
+
+		downsampled := make([]appthings.Measurement, 0, budget)
+		for _, i := range appmeasurements.Downsample(x, y, budget) {
+			downsampled = append(downsampled, group[i])
+		}
+		values = append(values, downsampled)
+	}
+
+	thing.Values = values
+	return thing
+}
+
+func localizedMeasurementSeriesLabel(l10n Localizer, measurement string, values []appthings.Measurement, index int) string {
+	if measurement != "" {
+		return featuresthings.LocalizedMeasurementLabel(l10n, measurement)
 	}
-	return []string{}
+	return fmt.Sprintf("Series %d", index+1)
 }
 
 func thingChartColor(index int, isDark bool) string {
@@ -853,12 +1014,12 @@ func countMeasurements(groups [][]appthings.Measurement) int {
 	return total
 }
 
-func measurementRows(groups [][]appthings.Measurement) []featuresthings.MeasurementTableRow {
+func measurementRows(groups [][]appthings.Measurement, loc *time.Location) []featuresthings.MeasurementTableRow {
 	rows := make([]featuresthings.MeasurementTableRow, 0)
 	for _, group := range groups {
 		for _, value := range group {
 			rows = append(rows, featuresthings.MeasurementTableRow{
-				Timestamp: value.Timestamp.Format("2006-01-02 15:04"),
+				Timestamp: value.Timestamp.In(loc).Format("2006-01-02 15:04"),
 				Value:     measurementRowValue(value),
 			})
 		}
@@ -936,13 +1097,62 @@ func measurementTimeScale(theme chartThemeConfig) shared.AxisScale {
 			TooltipFormat: "yyyy-MM-dd HH:mm",
 			Parser:        "yyyy-MM-dd HH:mm",
 			DisplayFormats: map[string]string{
-				"hour": "HH:mm",
-				"day":  "yyyy-MM-dd",
+				"hour":  "HH:mm",
+				"day":   "yyyy-MM-dd",
+				"week":  "yyyy-MM-dd",
+				"month": "yyyy-MM",
+			},
+		},
+	}
+}
+
+// periodBarChartConfig shows values per day, week or month as bars, with a left and an optional right value axis.
+func periodBarChartConfig(theme chartThemeConfig, labels []string, datasets []shared.AdvancedChartDataset, axes map[string]shared.AxisScale) shared.AdvancedChartConfig {
+	scales := map[string]shared.AxisScale{
+		"x": {
+			Ticks: &shared.AxisTicks{Color: theme.MutedForeground},
+			Grid:  &shared.AxisGrid{Display: new(false)},
+		},
+	}
+	maps.Copy(scales, axes)
+
+	return shared.AdvancedChartConfig{
+		Type: "bar",
+		Data: shared.AdvancedChartData{
+			Labels:   labels,
+			Datasets: datasets,
+		},
+		Options: shared.AdvancedChartOptions{
+			Responsive:          true,
+			MaintainAspectRatio: false,
+			Animation:           false,
+			Interaction:         &shared.Interaction{Intersect: false, Mode: "index"},
+			Plugins: &shared.Plugins{
+				Legend: &shared.PluginLegend{Display: true, Labels: &shared.PluginLegendLabels{Color: theme.Foreground}},
+				Tooltip: &shared.PluginTooltip{
+					BackgroundColor: theme.Background,
+					BodyColor:       theme.MutedForeground,
+					TitleColor:      theme.Foreground,
+					BorderColor:     theme.Border,
+					BorderWidth:     1,
+				},
 			},
+			Scales: scales,
 		},
 	}
 }
 
+func periodValueAxis(theme chartThemeConfig, position, title string, drawGrid bool) shared.AxisScale {
+	return shared.AxisScale{
+		Position:    position,
+		BeginAtZero: new(true),
+		Title:       &shared.AxisTitle{Display: true, Text: title, Color: theme.MutedForeground},
+		Ticks:       &shared.AxisTicks{Color: theme.MutedForeground},
+		Grid:        &shared.AxisGrid{Display: new(drawGrid), Color: theme.Grid},
+		Border:      &shared.AxisBorder{Display: true, Color: theme.Border},
+	}
+}
+
 //go:fix inline
 func boolPtr(v bool) *bool {
 	return new(v)
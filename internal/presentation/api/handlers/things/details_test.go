@@ -2,6 +2,7 @@ package things
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,9 +14,14 @@ import (
 
 	"github.com/diwise/diwise-web/internal/application/client"
 	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	appmeasurements "github.com/diwise/diwise-web/internal/application/measurements"
+	"github.com/diwise/diwise-web/internal/application/properties"
 	appthings "github.com/diwise/diwise-web/internal/application/things"
 	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
 	frontendtoolkit "github.com/diwise/frontend-toolkit"
 	ftkmock "github.com/diwise/frontend-toolkit/mock"
 	"github.com/diwise/frontend-toolkit/pkg/locale"
@@ -36,17 +42,17 @@ func TestBuildThingUpdateFieldsMapsEditForm(t *testing.T) {
 		"longitude":       {"11.974560"},
 		"maxl":            {"1.25"},
 		"maxd":            {"2.50"},
-		"angle":           {"3.75"},
+		"angle":           {"30"},
 		"offset":          {"4.50"},
 	}
 
 	fields, err := buildThingUpdateFields(context.Background(), &testThingsApp{
-		thing: appthings.Thing{ID: "thing-1", ValidURNs: []string{"urn:1"}},
+		thing: appthings.Thing{ID: "thing-1", Type: "Container", SubType: "Sandstorage", ValidURNs: []string{"urn:1"}},
 		validSensors: []appthings.SensorIdentifier{
 			{SensorID: "70B3D57ED00627A1", DeviceID: "device-1"},
 			{SensorID: "70B3D57ED00627A2", DeviceID: "device-2"},
 		},
-	}, "thing-1", form)
+	}, "thing-1", form, geo.BoundingBox{}, properties.Defaults)
 
 	is.NoErr(err)
 	is.Equal("Container A", fields["name"])
@@ -58,8 +64,33 @@ func TestBuildThingUpdateFieldsMapsEditForm(t *testing.T) {
 	is.Equal(client.Location{Latitude: 57.70887, Longitude: 11.97456}, fields["location"])
 	is.Equal(1.25, fields["maxl"])
 	is.Equal(2.5, fields["maxd"])
-	is.Equal(3.75, fields["angle"])
-	is.Equal(4.5, fields["offset"])
+	is.Equal(30.0, fields["angle"])
+	is.Equal(nil, fields["offset"]) // sand storages have no offset
+}
+
+func TestBuildThingUpdateFieldsValidatesPropertiesAgainstTheSchema(t *testing.T) {
+	is := is.New(t)
+	app := &testThingsApp{thing: appthings.Thing{ID: "thing-1", Type: "Container", SubType: "Sandstorage"}}
+
+	_, err := buildThingUpdateFields(context.Background(), app, "thing-1", url.Values{"angle": {"120"}}, geo.BoundingBox{}, properties.Defaults)
+	is.True(errors.Is(err, properties.ErrTooLarge))
+	is.Equal("propertytoolarge", localizeThingValidationMessage(noopLocalizer{}, err))
+
+	schemas := properties.Schemas{{Types: []string{"Sandstorage"}, Fields: []properties.Field{
+		{Name: "volume", Label: "volume", Unit: "m3", Type: properties.TypeInteger, Required: true},
+	}}}
+	ctx := context.Background()
+
+	_, err = buildThingUpdateFields(ctx, app, "thing-1", url.Values{"volume": {""}}, geo.BoundingBox{}, schemas)
+	is.True(errors.Is(err, properties.ErrRequired))
+
+	fields, err := buildThingUpdateFields(ctx, app, "thing-1", url.Values{"volume": {"12"}, "angle": {"120"}}, geo.BoundingBox{}, schemas)
+	is.NoErr(err)
+	is.Equal(12.0, fields["volume"])
+	is.Equal(nil, fields["angle"])
+
+	described := propertyFieldsOf(schemas, app.thing)
+	is.Equal([]featuresthings.PropertyField{{Name: "volume", Label: "volume", Unit: "m3", Step: "1", Required: true}}, described)
 }
 
 func TestBuildThingUpdateFieldsHandlesRepeatedTagValues(t *testing.T) {
@@ -69,7 +100,7 @@ func TestBuildThingUpdateFieldsHandlesRepeatedTagValues(t *testing.T) {
 		"tags": {"waste", "downtown", "waste"},
 	}
 
-	fields, err := buildThingUpdateFields(context.Background(), &testThingsApp{}, "thing-1", form)
+	fields, err := buildThingUpdateFields(context.Background(), &testThingsApp{}, "thing-1", form, geo.BoundingBox{}, properties.Defaults)
 
 	is.NoErr(err)
 	is.Equal([]string{"waste", "downtown"}, fields["tags"])
@@ -84,7 +115,7 @@ func TestBuildThingUpdateFieldsAcceptsLegacyDeviceIDCurrentDevice(t *testing.T)
 		},
 	}, "thing-1", url.Values{
 		"currentDevice": {"device-1"},
-	})
+	}, geo.BoundingBox{}, properties.Defaults)
 
 	is.NoErr(err)
 	is.Equal([]appthings.RefDevice{{DeviceID: "device-1"}}, fields["refDevices"])
@@ -97,7 +128,7 @@ func TestBuildThingUpdateFieldsRejectsUnknownConnectedSensor(t *testing.T) {
 		thing: appthings.Thing{ID: "thing-1", ValidURNs: []string{"urn:1"}},
 	}, "thing-1", url.Values{
 		"currentDevice": {"missing-sensor"},
-	})
+	}, geo.BoundingBox{}, properties.Defaults)
 
 	is.True(err != nil)
 }
@@ -226,10 +257,9 @@ func pathValue(value string) string {
 func TestAllowsMultipleConnectedSensorsByThingType(t *testing.T) {
 	is := is.New(t)
 
-	is.True(allowsMultipleConnectedSensors(appthings.Thing{Type: "Building"}))
 	is.True(allowsMultipleConnectedSensors(appthings.Thing{Type: "Room"}))
-	is.True(allowsMultipleConnectedSensors(appthings.Thing{Type: "Sewer"}))
 	is.True(allowsMultipleConnectedSensors(appthings.Thing{Type: "Container", SubType: "Sandstorage"}))
+	is.True(!allowsMultipleConnectedSensors(appthings.Thing{Type: "Sewer", SubType: "CombinedSewerOverflow"}))
 	is.True(!allowsMultipleConnectedSensors(appthings.Thing{Type: "Container", SubType: "WasteContainer"}))
 	is.True(!allowsMultipleConnectedSensors(appthings.Thing{Type: "Lifebuoy"}))
 }
@@ -270,6 +300,43 @@ func TestMeasurementQueryForPresenceSeries(t *testing.T) {
 	is.Equal("3302/0", query.Get("n"))
 }
 
+func TestMeasurementAggregationOnlyAppliesToNumericSeries(t *testing.T) {
+	is := is.New(t)
+
+	startTime := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	numeric := measurementQuery("3303-5700", startTime, endTime)
+	is.True(aggregatedMeasurementQuery(numeric, appmeasurements.ResolutionDay))
+	is.True(!aggregatedMeasurementQuery(numeric, appmeasurements.ResolutionRaw))
+	is.True(!aggregatedMeasurementQuery(measurementQuery("3302-0", startTime, endTime), appmeasurements.ResolutionDay))
+
+	query := withMeasurementAggregation(numeric, appmeasurements.ResolutionDay, appmeasurements.AggregationMax)
+	is.Equal("day", query.Get("timeunit"))
+	is.Equal("max", query.Get("aggrMethods"))
+	is.Equal("", numeric.Get("aggrMethods"))
+}
+
+func TestDownsampleThingValuesKeepsBooleanSeries(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	numeric := make([]appthings.Measurement, 0, 50)
+	boolean := make([]appthings.Measurement, 0, 50)
+	for i := range 50 {
+		v := float64(i % 7)
+		b := i%2 == 0
+		numeric = append(numeric, appthings.Measurement{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: &v})
+		boolean = append(boolean, appthings.Measurement{Timestamp: start.Add(time.Duration(i) * time.Minute), BoolValue: &b})
+	}
+
+	thing := downsampleThingValues(appthings.Thing{Values: [][]appthings.Measurement{numeric, boolean}}, 10)
+
+	is.Equal(10, len(thing.Values[0]))
+	is.Equal(50, len(thing.Values[1]))
+	is.Equal(start, thing.Values[0][0].Timestamp)
+}
+
 func TestThingMeasurementChartConfigUsesLegacyPercentageScaleForFillingLevel(t *testing.T) {
 	is := is.New(t)
 
@@ -285,6 +352,23 @@ func TestThingMeasurementChartConfigUsesLegacyPercentageScaleForFillingLevel(t *
 	is.Equal(10.0, *config.Options.Scales["y"].Ticks.StepSize)
 }
 
+func TestThingMeasurementChartConfigShowsTimesInTheTimeZoneOfTheUser(t *testing.T) {
+	is := is.New(t)
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	is.NoErr(err)
+
+	req := httptest.NewRequest("GET", "/components/things/thing-1/measurements", nil)
+	req = req.WithContext(helpers.WithUserPreferences(req.Context(), helpers.UserPreferences{Location: stockholm}))
+	config := thingMeasurementChartConfig(req, noopLocalizer{}, "3303-5700", appthings.Thing{
+		Values: [][]appthings.Measurement{{
+			{ID: "thing-1/3303/5700", Timestamp: time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC), Value: new(21.5)},
+		}},
+	})
+
+	is.Equal(shared.ChartPoint{X: "2026-06-01 12:00", Y: 21.5}, config.Data.Datasets[0].Data[0])
+}
+
 func TestThingMeasurementChartConfigUsesLegacyPresenceScale(t *testing.T) {
 	is := is.New(t)
 
@@ -381,7 +465,7 @@ func TestComposeDetailsModelUsesConnectedSensorDevEUIForEditDisplay(t *testing.T
 		devices: map[string]devices.Device{
 			"device-1": {DeviceID: "device-1", SensorID: "70B3D57ED00627A1"},
 		},
-	}, true)
+	}, true, properties.Defaults)
 
 	is.NoErr(err)
 	is.True(!model.AllowsMultipleConnectedSensors)
@@ -401,7 +485,7 @@ func TestComposeDetailsModelMarksSandstorageAsMultiSensor(t *testing.T) {
 			SubType:   "Sandstorage",
 			ValidURNs: []string{"urn:1"},
 		},
-	}, true)
+	}, true, properties.Defaults)
 
 	is.NoErr(err)
 	is.True(model.AllowsMultipleConnectedSensors)
@@ -415,7 +499,7 @@ func TestComposeDetailsModelFallsBackToDeviceIDWhenSensorLookupFails(t *testing.
 			ID:         "thing-1",
 			RefDevices: []appthings.RefDevice{{DeviceID: "device-1"}},
 		},
-	}, false)
+	}, false, properties.Defaults)
 
 	is.NoErr(err)
 	is.Equal([]featuresthings.ConnectedSensorViewModel{{
@@ -0,0 +1,137 @@
+package things
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apphierarchy "github.com/diwise/diwise-web/internal/application/hierarchy"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingHierarchyComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apphierarchy.Management) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		writeHierarchy(w, r, l10n, app, id)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewThingParentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apphierarchy.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		err := app.SetParent(r.Context(), id, r.FormValue("parent"))
+		if errors.Is(err, apphierarchy.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Error("could not save parent", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not save parent", http.StatusInternalServerError)
+			return
+		}
+
+		writeHierarchy(w, r, l10n, app, id)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func writeHierarchy(w http.ResponseWriter, r *http.Request, l10n LocaleBundle, app apphierarchy.Management, id string) {
+	ctx := r.Context()
+	log := logging.GetFromContext(ctx)
+	localizer := l10n.For(helpers.Language(r))
+
+	h, err := app.GetHierarchy(ctx, id)
+	if err != nil {
+		log.Error("could not fetch hierarchy", "thing_id", id, "err", err.Error())
+		http.Error(w, "could not fetch hierarchy", http.StatusInternalServerError)
+		return
+	}
+
+	var candidates []appthings.Thing
+	if apphierarchy.CanHaveParent(h.Thing.Type) {
+		candidates, err = app.GetParentCandidates(ctx, id)
+		if err != nil {
+			log.Error("could not fetch parent candidates", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch hierarchy", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	component := featuresthings.ThingHierarchy(localizer, hierarchyViewModel(localizer, h, candidates))
+	helpers.WriteComponentResponse(ctx, w, r, component, 16*1024, 0)
+}
+
+func hierarchyViewModel(localizer Localizer, h apphierarchy.Hierarchy, candidates []appthings.Thing) featuresthings.HierarchyViewModel {
+	rollup := h.Rollup()
+
+	model := featuresthings.HierarchyViewModel{
+		ThingID:       h.Thing.ID,
+		CanHaveParent: apphierarchy.CanHaveParent(h.Thing.Type),
+		Parent:        h.Thing.Parent,
+		Children:      hierarchyNodes(localizer, h.Children),
+		Rollup: featuresthings.RollupViewModel{
+			Things:     rollup.Things,
+			Energy:     rollup.Energy,
+			Power:      rollup.Power,
+			Present:    rollup.Present,
+			Occupiable: rollup.Occupiable,
+		},
+	}
+
+	for _, a := range h.Ancestors {
+		model.Ancestors = append(model.Ancestors, hierarchyNode(localizer, a))
+	}
+	for _, c := range candidates {
+		model.Candidates = append(model.Candidates, hierarchyNode(localizer, c))
+	}
+
+	return model
+}
+
+func hierarchyNodes(localizer Localizer, nodes []apphierarchy.Node) []featuresthings.HierarchyNodeViewModel {
+	result := make([]featuresthings.HierarchyNodeViewModel, 0, len(nodes))
+	for _, n := range nodes {
+		node := hierarchyNode(localizer, n.Thing)
+		node.Children = hierarchyNodes(localizer, n.Children)
+		result = append(result, node)
+	}
+	return result
+}
+
+// hierarchyNode shows the presence of a room or a desk and the power of a building.
+func hierarchyNode(localizer Localizer, t appthings.Thing) featuresthings.HierarchyNodeViewModel {
+	node := featuresthings.HierarchyNodeViewModel{ID: t.ID, Name: cmp.Or(t.Name, t.ID), Type: t.Type}
+
+	switch {
+	case t.TypeValues.Presence != nil && *t.TypeValues.Presence:
+		node.Value = localizer.Get("occupied")
+	case t.TypeValues.Presence != nil:
+		node.Value = localizer.Get("available")
+	case t.TypeValues.Power != nil:
+		node.Value = fmt.Sprintf("%0.1f kW", *t.TypeValues.Power)
+	}
+
+	return node
+}
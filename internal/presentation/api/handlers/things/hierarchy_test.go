@@ -0,0 +1,34 @@
+package things
+
+import (
+	"testing"
+
+	apphierarchy "github.com/diwise/diwise-web/internal/application/hierarchy"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	ftkmock "github.com/diwise/frontend-toolkit/mock"
+	"github.com/matryer/is"
+)
+
+func TestHierarchyViewModelShowsTheTreeAndRollup(t *testing.T) {
+	is := is.New(t)
+
+	localizer := &ftkmock.LocalizerMock{GetFunc: func(key string) string { return key }}
+	model := hierarchyViewModel(localizer, apphierarchy.Hierarchy{
+		Thing:     appthings.Thing{ID: "room-1", Type: "Room", Parent: "building"},
+		Ancestors: []appthings.Thing{{ID: "building", Type: "Building", Name: "Town hall"}},
+		Children: []apphierarchy.Node{
+			{Thing: appthings.Thing{ID: "desk-1", Type: "Desk", TypeValues: appthings.TypeValues{Presence: new(true)}}},
+			{Thing: appthings.Thing{ID: "desk-2", Type: "Desk", Name: "Window", TypeValues: appthings.TypeValues{Presence: new(false)}}},
+		},
+	}, []appthings.Thing{{ID: "building", Type: "Building", Name: "Town hall"}})
+
+	is.True(model.CanHaveParent)
+	is.Equal("building", model.Parent)
+	is.Equal("Town hall", model.Ancestors[0].Name)
+	is.Equal("desk-1", model.Children[0].Name)
+	is.Equal("occupied", model.Children[0].Value)
+	is.Equal("available", model.Children[1].Value)
+	is.Equal(1, model.Rollup.Present)
+	is.Equal(2, model.Rollup.Occupiable)
+	is.Equal(1, len(model.Candidates))
+}
@@ -0,0 +1,190 @@
+package things
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	apphistory "github.com/diwise/diwise-web/internal/application/history"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// sensorChangeColor sets the markers of connected and disconnected sensors apart from the notes of
+// the maintenance log on the measurement charts.
+const sensorChangeColor = "#d97706"
+
+var historyFieldLabels = map[string]string{
+	apphistory.FieldName:            "name",
+	apphistory.FieldAlternativeName: "alternativeName",
+	apphistory.FieldDescription:     "description",
+	apphistory.FieldType:            "thingtype",
+	apphistory.FieldTenant:          "organisation",
+	apphistory.FieldTags:            "tags",
+	apphistory.FieldSensors:         "sensors",
+	apphistory.FieldLocation:        "location",
+}
+
+type historyApp interface {
+	apphistory.Management
+	GetThing(ctx context.Context, id string, args map[string][]string) (appthings.Thing, error)
+}
+
+// NewThingHistoryComponentHandler shows how a thing has changed, and with asof=<date> what it
+// looked like at the end of that day. The history is only shown for things that the user can fetch,
+// since it keeps the names and tenants of what the thing has been.
+func NewThingHistoryComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app historyApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := logging.NewContextWithLogger(r.Context(), log)
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		loc := helpers.Location(ctx)
+		var asOf time.Time
+		if value := r.URL.Query().Get("asof"); value != "" {
+			day, err := time.ParseInLocation(time.DateOnly, value, loc)
+			if err != nil {
+				http.Error(w, "invalid date", http.StatusBadRequest)
+				return
+			}
+			asOf = day
+		}
+
+		if _, err := app.GetThing(ctx, id, nil); err != nil {
+			http.Error(w, "thing not found", http.StatusNotFound)
+			return
+		}
+
+		entries, err := app.GetHistory(ctx, id)
+		if err != nil {
+			log.Error("could not fetch history", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch history", http.StatusInternalServerError)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		component := featuresthings.ThingHistory(localizer, historyViewModel(localizer, id, entries, asOf, loc))
+		helpers.WriteComponentResponse(ctx, w, r, component, 16*1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func historyViewModel(localizer Localizer, thingID string, entries []apphistory.Entry, asOf time.Time, loc *time.Location) featuresthings.HistoryViewModel {
+	model := featuresthings.HistoryViewModel{
+		ThingID: thingID,
+		Entries: make([]featuresthings.HistoryEntryViewModel, 0, len(entries)),
+		Today:   time.Now().In(loc).Format(time.DateOnly),
+	}
+
+	for _, entry := range entries {
+		e := featuresthings.HistoryEntryViewModel{
+			Time:     entry.Time.In(loc).Format("2006-01-02 15:04"),
+			DateTime: entry.Time.Format(time.RFC3339),
+			Kind:     entry.Kind,
+			Author:   entry.Author,
+		}
+		for _, change := range entry.Changes {
+			e.Changes = append(e.Changes, featuresthings.HistoryChangeViewModel{
+				Label:   localizer.Get(historyFieldLabels[change.Field]),
+				From:    historyFieldValue(localizer, change.Field, change.From),
+				To:      historyFieldValue(localizer, change.Field, change.To),
+				Added:   change.Added,
+				Removed: change.Removed,
+			})
+		}
+		model.Entries = append(model.Entries, e)
+	}
+
+	if len(entries) > 0 {
+		model.Since = entries[len(entries)-1].Time.In(loc).Format("2006-01-02 15:04")
+	}
+
+	if asOf.IsZero() {
+		return model
+	}
+
+	model.AsOf = asOf.Format(time.DateOnly)
+	if version, ok := apphistory.AsOf(entries, asOf.AddDate(0, 0, 1).Add(-time.Second)); ok {
+		model.Version = &featuresthings.HistoryVersionViewModel{
+			Time:   version.Time.In(loc).Format("2006-01-02 15:04"),
+			Author: version.Author,
+			Items:  historySnapshotItems(localizer, version.Snapshot),
+		}
+	}
+
+	return model
+}
+
+func historySnapshotItems(localizer Localizer, s apphistory.Snapshot) []shared.DetailListItem {
+	item := func(field, value string) shared.DetailListItem {
+		return shared.DetailListItem{
+			Label:   localizer.Get(historyFieldLabels[field]),
+			Value:   value,
+			Missing: strings.TrimSpace(value) == "",
+		}
+	}
+
+	items := []shared.DetailListItem{
+		item(apphistory.FieldType, historyFieldValue(localizer, apphistory.FieldType, s.Type)),
+		item(apphistory.FieldName, s.Name),
+	}
+	if s.AlternativeName != "" {
+		items = append(items, item(apphistory.FieldAlternativeName, s.AlternativeName))
+	}
+
+	return append(items,
+		item(apphistory.FieldDescription, s.Description),
+		item(apphistory.FieldTenant, s.Tenant),
+		item(apphistory.FieldTags, strings.Join(s.Tags, ", ")),
+		item(apphistory.FieldSensors, strings.Join(s.Sensors, ", ")),
+		item(apphistory.FieldLocation, s.Location()),
+	)
+}
+
+// historyFieldValue shows the type of a thing by its name, such as "Waste container" rather than
+// Container-WasteContainer.
+func historyFieldValue(localizer Localizer, field, value string) string {
+	if field != apphistory.FieldType || value == "" {
+		return value
+	}
+
+	thingType, subType, _ := strings.Cut(value, "-")
+	return localizer.Get(cmp.Or(subType, thingType))
+}
+
+// annotateSensorChanges marks on a measurement chart when sensors were connected to or
+// disconnected from a thing, so that jumps in the data can be explained.
+func annotateSensorChanges(ctx context.Context, config *shared.AdvancedChartConfig, localizer Localizer, app apphistory.Management, thingID string, from, to time.Time) {
+	entries, err := app.GetHistory(ctx, thingID)
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not fetch history for chart", "thing_id", thingID, "err", err.Error())
+		return
+	}
+
+	for _, entry := range apphistory.SensorChanges(entries, from, to) {
+		change, _ := entry.SensorChange()
+
+		label := localizer.Get("sensorschanged")
+		switch {
+		case len(change.Removed) == 0:
+			label = localizer.Get("sensorconnectedmarker")
+		case len(change.Added) == 0:
+			label = localizer.Get("sensordisconnectedmarker")
+		}
+
+		config.Annotate(helpers.ChartAnnotation(ctx, entry.Time, time.Time{}, label, sensorChangeColor))
+	}
+}
@@ -0,0 +1,100 @@
+package things
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/client"
+	apphistory "github.com/diwise/diwise-web/internal/application/history"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/matryer/is"
+)
+
+func TestHistoryViewModelShowsTheThingAsOfTheEndOfADay(t *testing.T) {
+	is := is.New(t)
+	at := func(d, h int) time.Time { return time.Date(2024, 5, d, h, 0, 0, 0, time.UTC) }
+
+	entries := []apphistory.Entry{
+		{Time: at(3, 9), Kind: apphistory.KindChanged, Author: "Bo", Snapshot: apphistory.Snapshot{Name: "Bin 2", Type: "Container-WasteContainer", Tags: []string{"a", "b"}},
+			Changes: []apphistory.Change{{Field: apphistory.FieldName, From: "Bin", To: "Bin 2"}}},
+		{Time: at(2, 9), Kind: apphistory.KindCreated, Author: "Alice", Snapshot: apphistory.Snapshot{Name: "Bin", Type: "Container-WasteContainer"}},
+	}
+
+	model := historyViewModel(noopLocalizer{}, "bin-1", entries, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), time.UTC)
+	is.Equal(2, len(model.Entries))
+	is.Equal("name", model.Entries[0].Changes[0].Label)
+	is.Equal("2024-05-02", model.AsOf)
+	is.True(model.Version != nil)
+	is.Equal("Alice", model.Version.Author)
+	is.Equal("WasteContainer", model.Version.Items[0].Value)
+	is.Equal("Bin", model.Version.Items[1].Value)
+
+	model = historyViewModel(noopLocalizer{}, "bin-1", entries, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.UTC)
+	is.True(model.Version == nil)
+	is.Equal("2024-05-02 09:00", model.Since)
+}
+
+func TestSensorChangesAreMarkedOnTheChart(t *testing.T) {
+	is := is.New(t)
+	at := func(d int) time.Time { return time.Date(2024, 5, d, 12, 30, 0, 0, time.UTC) }
+
+	app := historyEntries{
+		{Time: at(4), Kind: apphistory.KindChanged, Changes: []apphistory.Change{{Field: apphistory.FieldSensors, Added: []string{"s2"}, Removed: []string{"s1"}}}},
+		{Time: at(3), Kind: apphistory.KindChanged, Changes: []apphistory.Change{{Field: apphistory.FieldTags, Added: []string{"x"}}}},
+		{Time: at(2), Kind: apphistory.KindChanged, Changes: []apphistory.Change{{Field: apphistory.FieldSensors, Added: []string{"s1"}}}},
+	}
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	is.NoErr(err)
+	ctx := helpers.WithUserPreferences(context.Background(), helpers.UserPreferences{Location: stockholm})
+
+	config := shared.AdvancedChartConfig{}
+	annotateSensorChanges(ctx, &config, noopLocalizer{}, app, "bin-1", at(1), at(5))
+
+	is.True(config.Options.Plugins != nil)
+	lines := config.Options.Plugins.Annotations.Lines
+	is.Equal(2, len(lines))
+	is.Equal(shared.ChartAnnotation{X: "2024-05-04 14:30", Label: "sensorschanged", Color: sensorChangeColor}, lines[0])
+	is.Equal("sensorconnectedmarker", lines[1].Label)
+}
+
+func TestHistoryIsOnlyShownForThingsThatTheUserCanSee(t *testing.T) {
+	is := is.New(t)
+
+	app := historyThingApp{historyEntries: historyEntries{{Time: time.Now(), Kind: apphistory.KindCreated, Author: "Alice"}}, visible: "bin-1"}
+	handler := NewThingHistoryComponentHandler(context.Background(), testLocaleBundle(), nil, app)
+
+	history := func(id string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/components/things/"+id+"/history", nil)
+		r.SetPathValue("id", id)
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	is.Equal(http.StatusOK, history("bin-1"))
+	is.Equal(http.StatusNotFound, history("other-tenant"))
+}
+
+type historyThingApp struct {
+	historyEntries
+	visible string
+}
+
+func (a historyThingApp) GetThing(_ context.Context, id string, _ map[string][]string) (appthings.Thing, error) {
+	if id != a.visible {
+		return appthings.Thing{}, client.ErrNotFound
+	}
+	return appthings.Thing{ID: id}, nil
+}
+
+type historyEntries []apphistory.Entry
+
+func (h historyEntries) GetHistory(context.Context, string) ([]apphistory.Entry, error) {
+	return h, nil
+}
@@ -0,0 +1,120 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// NewPositionComponentHandler reads coordinates pasted into the location picker, so that the
+// browser can move the marker to them. Northings and eastings are read in the grid chosen next to
+// the paste field.
+func NewPositionComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, _ thingsApp) http.HandlerFunc {
+	bounds := helpers.GetMunicipalBounds(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		position := strings.TrimSpace(r.URL.Query().Get("position"))
+		if position == "" {
+			helpers.WriteComponentResponse(r.Context(), w, r, templ.NopComponent, 0, 0)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		model := featuresthings.PositionViewModel{}
+
+		projection, ok := geo.Lookup(r.URL.Query().Get("system"))
+		if !ok {
+			projection = geo.Sweref99TM
+		}
+
+		p, err := geo.ParseIn(position, projection)
+		if err == nil {
+			err = bounds.Check(p)
+		}
+		if err != nil {
+			model.Error = localizeLocationError(localizer, err)
+		} else {
+			model.Latitude = strconv.FormatFloat(p.Latitude, 'f', 6, 64)
+			model.Longitude = strconv.FormatFloat(p.Longitude, 'f', 6, 64)
+		}
+
+		component := featuresthings.PositionFeedback(localizer, model)
+		helpers.WriteComponentResponse(r.Context(), w, r, component, 1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// errNoLocation is returned for a new thing that has not been placed on the map.
+var errNoLocation = errors.New("no location")
+
+// locationFromForm reads the latitude and longitude of a thing form. The location is left out when
+// both are empty and is otherwise checked against the bounds.
+func locationFromForm(form url.Values, bounds geo.BoundingBox) (client.Location, bool, error) {
+	latitude := strings.TrimSpace(form.Get("latitude"))
+	longitude := strings.TrimSpace(form.Get("longitude"))
+	if latitude == "" && longitude == "" {
+		return client.Location{}, false, nil
+	}
+
+	lat, latErr := strconv.ParseFloat(latitude, 64)
+	lon, lonErr := strconv.ParseFloat(longitude, 64)
+	if latErr != nil || lonErr != nil {
+		return client.Location{}, false, fmt.Errorf("%w: %q, %q", geo.ErrInvalid, latitude, longitude)
+	}
+
+	if err := bounds.Check(geo.Position{Latitude: lat, Longitude: lon}); err != nil {
+		return client.Location{}, false, err
+	}
+
+	return client.Location{Latitude: lat, Longitude: lon}, true, nil
+}
+
+func localizeLocationError(localizer Localizer, err error) string {
+	if errors.Is(err, errNoLocation) {
+		return localizer.Get("locationrequired")
+	}
+	if errors.Is(err, geo.ErrOutside) {
+		return localizer.Get("locationoutside")
+	}
+	return localizer.Get("invalidlocation")
+}
+
+func boundsViewModel(bounds geo.BoundingBox) *featuresthings.BoundsViewModel {
+	if bounds.IsZero() {
+		return nil
+	}
+	return &featuresthings.BoundsViewModel{
+		South: bounds.MinLatitude,
+		West:  bounds.MinLongitude,
+		North: bounds.MaxLatitude,
+		East:  bounds.MaxLongitude,
+	}
+}
+
+// gridOptions lists the grids of the location picker, with the one the user prefers chosen. Users
+// who prefer latitude and longitude get SWEREF 99 TM.
+func gridOptions(ctx context.Context) []featuresthings.GridOption {
+	preferred, ok := helpers.Projection(ctx)
+	if !ok {
+		preferred = geo.Sweref99TM
+	}
+
+	options := make([]featuresthings.GridOption, 0, len(geo.Projections))
+	for _, p := range geo.Projections {
+		options = append(options, featuresthings.GridOption{Value: p.ID, Label: p.Name, Selected: p.ID == preferred.ID})
+	}
+	return options
+}
@@ -0,0 +1,41 @@
+package things
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestPositionComponentReadsPastedCoordinates(t *testing.T) {
+	is := is.New(t)
+
+	ctx := helpers.WithMunicipalBounds(context.Background(), geo.BoundingBox{MinLongitude: 16.5, MinLatitude: 62.1, MaxLongitude: 17.9, MaxLatitude: 62.75})
+	handler := NewPositionComponentHandler(ctx, testLocaleBundle(), nil, &testThingsApp{})
+
+	get := func(position string) string {
+		req := httptest.NewRequest(http.MethodGet, "/components/things/position?position="+position, nil)
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		is.Equal(http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	body := get("N+6919000+E+617000")
+	is.True(strings.Contains(body, `data-latitude="62.383940"`))
+	is.True(strings.Contains(body, `data-longitude="17.262648"`))
+
+	is.True(strings.Contains(get("57.70887,11.97456"), "locationoutside"))
+	is.True(strings.Contains(get("somewhere"), "invalidlocation"))
+	is.Equal("", get(""))
+
+	rt90 := get("6920756.946+1577680.258&system=rt90_25gonv")
+	is.True(strings.Contains(rt90, `data-latitude="62.390800"`))
+	is.True(strings.Contains(rt90, `data-longitude="17.306900"`))
+}
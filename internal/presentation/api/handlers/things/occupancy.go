@@ -0,0 +1,92 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	appoccupancy "github.com/diwise/diwise-web/internal/application/occupancy"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingOccupancyComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appoccupancy.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		loc := helpers.Location(ctx)
+
+		period, err := helpers.ParseDateRange(r, time.Now().In(loc).AddDate(0, 0, 1-appoccupancy.DefaultDays))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		space, err := app.GetOccupancy(ctx, id, period.From, period.To)
+		if err != nil {
+			log.Error("could not fetch occupancy", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch occupancy", http.StatusInternalServerError)
+			return
+		}
+
+		model := occupancyViewModel(period, space)
+		model.Chart = occupancyChartConfig(r, localizer, space)
+
+		component := featuresthings.ThingOccupancy(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 16*1024, time.Minute)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func occupancyViewModel(period helpers.DateRange, space appoccupancy.Space) featuresthings.OccupancyViewModel {
+	model := featuresthings.OccupancyViewModel{
+		ThingID:       space.ThingID,
+		From:          period.FirstDay(),
+		To:            period.LastDay(),
+		Utilisation:   space.Percent(),
+		OccupiedHours: space.Occupied,
+		OfficeHours:   space.Total,
+	}
+
+	for _, hour := range space.PeakHours() {
+		model.PeakHours = append(model.PeakHours, shared.FormatHourOfDay(hour))
+	}
+
+	return model
+}
+
+// occupancyChartConfig shows how often each office hour of the day was occupied.
+func occupancyChartConfig(r *http.Request, l10n Localizer, space appoccupancy.Space) shared.AdvancedChartConfig {
+	isDark := helpers.IsDarkMode(r)
+	theme := chartTheme(isDark)
+
+	labels := []string{}
+	shares := []any{}
+	for hour := appoccupancy.OfficeStartHour; hour < appoccupancy.OfficeEndHour; hour++ {
+		labels = append(labels, fmt.Sprintf("%02d:00", hour))
+		shares = append(shares, math.Round(space.ByHour[hour].Percent()))
+	}
+
+	datasets := []shared.AdvancedChartDataset{
+		{Label: l10n.Get("utilisation") + " (%)", Data: shares, YAxisID: "y", BackgroundColor: thingChartColor(0, isDark)},
+	}
+
+	return periodBarChartConfig(theme, labels, datasets, map[string]shared.AxisScale{
+		"y": periodValueAxis(theme, "left", "%", true),
+	})
+}
@@ -0,0 +1,26 @@
+package things
+
+import (
+	"testing"
+	"time"
+
+	appoccupancy "github.com/diwise/diwise-web/internal/application/occupancy"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestOccupancyViewModelNamesPeakHours(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	space := appoccupancy.Space{ThingID: "room-1", HourShare: appoccupancy.HourShare{Occupied: 4, Total: 16}}
+	space.ByHour[9] = appoccupancy.HourShare{Occupied: 2, Total: 2}
+	space.ByHour[13] = appoccupancy.HourShare{Occupied: 2, Total: 2}
+	space.ByHour[10] = appoccupancy.HourShare{Occupied: 0, Total: 2}
+
+	model := occupancyViewModel(helpers.DateRange{From: day, To: day.AddDate(0, 0, 2)}, space)
+	is.Equal(25.0, model.Utilisation)
+	is.Equal(4, model.OccupiedHours)
+	is.Equal(16, model.OfficeHours)
+	is.Equal([]string{"09–10", "13–14"}, model.PeakHours)
+}
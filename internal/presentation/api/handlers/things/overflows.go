@@ -0,0 +1,78 @@
+package things
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	appoverflows "github.com/diwise/diwise-web/internal/application/overflows"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingOverflowEventsComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app appoverflows.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		loc := helpers.Location(ctx)
+
+		// overflows are reported to the authorities per calendar year
+		now := time.Now().In(loc)
+		period, err := helpers.ParseDateRange(r, time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		events, err := app.GetOverflowEvents(ctx, id, period.From, period.To)
+		if err != nil {
+			log.Error("could not fetch overflow events", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch overflow events", http.StatusInternalServerError)
+			return
+		}
+
+		component := featuresthings.ThingOverflowEvents(localizer, overflowEventsViewModel(id, period, events, loc))
+		helpers.WriteComponentResponse(ctx, w, r, component, 16*1024, time.Minute)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func overflowEventsViewModel(thingID string, period helpers.DateRange, events []appoverflows.Event, loc *time.Location) featuresthings.OverflowEventsViewModel {
+	model := featuresthings.OverflowEventsViewModel{
+		ThingID: thingID,
+		From:    period.FirstDay(),
+		To:      period.LastDay(),
+		Count:   len(events),
+		Events:  make([]featuresthings.OverflowEventViewModel, 0, len(events)),
+	}
+
+	for _, e := range events {
+		d := e.Duration()
+		model.Total += d
+		model.Longest = max(model.Longest, d)
+		model.Events = append(model.Events, featuresthings.OverflowEventViewModel{
+			Start:    e.Start.In(loc),
+			End:      e.End.In(loc),
+			Duration: d,
+			Ongoing:  e.Ongoing,
+		})
+	}
+
+	// the most recent overflow is the most interesting one when looking at a single outlet
+	slices.Reverse(model.Events)
+
+	return model
+}
@@ -0,0 +1,191 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	apppassages "github.com/diwise/diwise-web/internal/application/passages"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingPassagesComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apppassages.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+
+		period, err := passagesPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stats, err := app.GetPassageStatistics(ctx, id, period.From, period.To)
+		if err != nil {
+			log.Error("could not fetch passages", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch passages", http.StatusInternalServerError)
+			return
+		}
+
+		model := passageStatisticsViewModel(period, stats, passagesGroupBy(r))
+		model.Chart = passagesChartConfig(r, localizer, model.Rows, stats.LastYear != nil)
+
+		component := featuresthings.ThingPassages(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 32*1024, time.Minute)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// NewThingPassagesExportHandler exports the passages of a thing as CSV with one row per hour, day or week.
+func NewThingPassagesExportHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app apppassages.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		period, err := passagesPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stats, err := app.GetPassageStatistics(ctx, id, period.From, period.To)
+		if err != nil {
+			log.Error("could not fetch passages", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch passages", http.StatusInternalServerError)
+			return
+		}
+
+		groupBy := passagesGroupBy(r)
+		b, err := apppassages.BucketsCSV(passageBuckets(stats, groupBy), helpers.Location(ctx))
+		if err != nil {
+			http.Error(w, "could not export passages", http.StatusInternalServerError)
+			return
+		}
+
+		filename := fmt.Sprintf("passages-%s-%s-%s-%s.csv", id, groupBy, period.FirstDay(), period.LastDay())
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		w.Write(b)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func passagesPeriod(r *http.Request) (helpers.DateRange, error) {
+	now := time.Now().In(helpers.Location(r.Context()))
+	return helpers.ParseDateRange(r, now.AddDate(0, 0, 1-apppassages.DefaultDays))
+}
+
+func passagesGroupBy(r *http.Request) string {
+	groupBy := r.URL.Query().Get("groupby")
+	if groupBy != "hour" && groupBy != "week" {
+		groupBy = "day"
+	}
+	return groupBy
+}
+
+func passageBuckets(stats apppassages.Statistics, groupBy string) []apppassages.Bucket {
+	switch groupBy {
+	case "hour":
+		return stats.Hours
+	case "week":
+		return stats.Weeks
+	default:
+		return stats.Days
+	}
+}
+
+func passageStatisticsViewModel(period helpers.DateRange, stats apppassages.Statistics, groupBy string) featuresthings.PassageStatisticsViewModel {
+	model := featuresthings.PassageStatisticsViewModel{
+		ThingID:    stats.ThingID,
+		From:       period.FirstDay(),
+		To:         period.LastDay(),
+		GroupBy:    groupBy,
+		Total:      stats.Total,
+		Heatmap:    stats.Heatmap,
+		HeatmapMax: stats.Heatmap.Max(),
+	}
+
+	var lastYear []apppassages.Bucket
+	if stats.LastYear != nil {
+		model.LastYearTotal = new(stats.LastYear.Total)
+		lastYear = passageBuckets(*stats.LastYear, groupBy)
+	}
+	if change, ok := stats.Change(); ok {
+		model.Change = new(change)
+	}
+
+	// last year is the same period moved back a year, so its buckets line up with this year's
+	for i, b := range passageBuckets(stats, groupBy) {
+		row := featuresthings.PassageBucketViewModel{
+			Label:    bucketLabel(groupBy, b.Start),
+			Passages: b.Passages,
+		}
+		if i < len(lastYear) {
+			row.LastYear = new(lastYear[i].Passages)
+		}
+		model.Rows = append(model.Rows, row)
+	}
+
+	return model
+}
+
+func passagesChartConfig(r *http.Request, l10n Localizer, rows []featuresthings.PassageBucketViewModel, compare bool) shared.AdvancedChartConfig {
+	isDark := helpers.IsDarkMode(r)
+	theme := chartTheme(isDark)
+
+	labels := make([]string, 0, len(rows))
+	passages := make([]any, 0, len(rows))
+	lastYear := make([]any, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, row.Label)
+		passages = append(passages, row.Passages)
+		if row.LastYear != nil {
+			lastYear = append(lastYear, *row.LastYear)
+		} else {
+			lastYear = append(lastYear, nil)
+		}
+	}
+
+	datasets := []shared.AdvancedChartDataset{
+		{Label: l10n.Get("passages"), Data: passages, YAxisID: "y", BackgroundColor: thingChartColor(0, isDark)},
+	}
+	if compare {
+		datasets = append(datasets, shared.AdvancedChartDataset{
+			Type:        "line",
+			Label:       l10n.Get("sameperiodlastyear"),
+			Data:        lastYear,
+			YAxisID:     "y",
+			BorderColor: thingChartColor(1, isDark),
+			BorderWidth: 2,
+			PointRadius: 2,
+		})
+	}
+
+	return periodBarChartConfig(theme, labels, datasets, map[string]shared.AxisScale{
+		"y": periodValueAxis(theme, "left", l10n.Get("passages"), true),
+	})
+}
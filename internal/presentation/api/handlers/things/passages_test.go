@@ -0,0 +1,42 @@
+package things
+
+import (
+	"testing"
+	"time"
+
+	apppassages "github.com/diwise/diwise-web/internal/application/passages"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestPassageStatisticsViewModelLinesUpLastYear(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	stats := apppassages.Statistics{
+		ThingID: "passage-1",
+		Total:   30,
+		Days:    []apppassages.Bucket{{Start: day, Passages: 10}, {Start: day.AddDate(0, 0, 1), Passages: 20}},
+		LastYear: &apppassages.Statistics{
+			Total: 20,
+			Days:  []apppassages.Bucket{{Start: day.AddDate(-1, 0, 0), Passages: 20}},
+		},
+	}
+	stats.Heatmap[0][8] = 10
+
+	model := passageStatisticsViewModel(helpers.DateRange{From: day, To: day.AddDate(0, 0, 2)}, stats, "day")
+	is.Equal("2026-03-02", model.From)
+	is.Equal("2026-03-03", model.To)
+	is.Equal(2, len(model.Rows))
+	is.Equal("2026-03-02", model.Rows[0].Label)
+	is.Equal(20, *model.Rows[0].LastYear)
+	is.Equal(nil, model.Rows[1].LastYear)
+	is.Equal(20, *model.LastYearTotal)
+	is.Equal(50.0, *model.Change)
+	is.Equal(10, model.HeatmapMax)
+}
+
+func TestBucketLabelForHours(t *testing.T) {
+	is := is.New(t)
+	is.Equal("2026-03-02 08:00", bucketLabel("hour", time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,136 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	apppumping "github.com/diwise/diwise-web/internal/application/pumping"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingPumpingComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app apppumping.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
+		loc := helpers.Location(ctx)
+
+		period, err := helpers.ParseDateRange(r, time.Now().In(loc).AddDate(0, 0, 1-apppumping.DefaultDays))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stats, err := app.GetPumpingStats(ctx, id, period.From, period.To)
+		if err != nil {
+			log.Error("could not fetch pumping statistics", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not fetch pumping statistics", http.StatusInternalServerError)
+			return
+		}
+
+		groupBy := r.URL.Query().Get("groupby")
+		if groupBy != "week" {
+			groupBy = "day"
+		}
+
+		model := pumpingStatsViewModel(period, stats, groupBy)
+		model.Chart = pumpingChartConfig(r, localizer, model.Rows)
+
+		component := featuresthings.ThingPumpingStats(localizer, model)
+		helpers.WriteComponentResponse(ctx, w, r, component, 24*1024, time.Minute)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func pumpingStatsViewModel(period helpers.DateRange, stats apppumping.Stats, groupBy string) featuresthings.PumpingStatsViewModel {
+	model := featuresthings.PumpingStatsViewModel{
+		ThingID:      stats.ThingID,
+		From:         period.FirstDay(),
+		To:           period.LastDay(),
+		GroupBy:      groupBy,
+		Starts:       stats.Starts,
+		StartsPerDay: stats.AverageStartsPerDay(),
+		Runtime:      stats.Runtime,
+		AverageCycle: stats.AverageCycle,
+		LongestCycle: stats.LongestCycle,
+		Running:      stats.Running,
+	}
+
+	buckets := stats.Days
+	if groupBy == "week" {
+		buckets = stats.Weeks
+	}
+
+	for _, b := range buckets {
+		model.Rows = append(model.Rows, featuresthings.PumpingBucketViewModel{
+			Label:   bucketLabel(groupBy, b.Start),
+			Starts:  b.Starts,
+			Runtime: b.Runtime,
+		})
+	}
+
+	for _, a := range stats.Anomalies {
+		model.Anomalies = append(model.Anomalies, featuresthings.PumpingAnomalyViewModel{
+			Kind:     string(a.Kind),
+			At:       a.At.In(period.From.Location()),
+			Value:    a.Value,
+			Baseline: a.Baseline,
+		})
+	}
+
+	return model
+}
+
+// pumpingChartConfig shows the starts and the runtime in minutes per day or week as bars on separate axes.
+func pumpingChartConfig(r *http.Request, l10n Localizer, rows []featuresthings.PumpingBucketViewModel) shared.AdvancedChartConfig {
+	isDark := helpers.IsDarkMode(r)
+	theme := chartTheme(isDark)
+
+	labels := make([]string, 0, len(rows))
+	starts := make([]any, 0, len(rows))
+	runtime := make([]any, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, row.Label)
+		starts = append(starts, row.Starts)
+		runtime = append(runtime, math.Round(row.Runtime.Minutes()*10)/10)
+	}
+
+	return periodBarChartConfig(theme, labels, []shared.AdvancedChartDataset{
+		{Label: l10n.Get("pumpstarts"), Data: starts, YAxisID: "y", BackgroundColor: thingChartColor(0, isDark)},
+		{Label: l10n.Get("runtime") + " (min)", Data: runtime, YAxisID: "y1", BackgroundColor: thingChartColor(1, isDark)},
+	}, map[string]shared.AxisScale{
+		"y":  periodValueAxis(theme, "left", l10n.Get("pumpstarts"), true),
+		"y1": periodValueAxis(theme, "right", l10n.Get("runtime")+" (min)", false),
+	})
+}
+
+// bucketLabel names the hour, the day, the week or the month that starts at t.
+func bucketLabel(groupBy string, t time.Time) string {
+	switch groupBy {
+	case "hour":
+		return t.Format("2006-01-02 15:00")
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return t.Format("2006-01")
+	default:
+		return t.Format(time.DateOnly)
+	}
+}
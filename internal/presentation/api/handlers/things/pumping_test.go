@@ -0,0 +1,30 @@
+package things
+
+import (
+	"testing"
+	"time"
+
+	apppumping "github.com/diwise/diwise-web/internal/application/pumping"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestPumpingStatsViewModelGroupsByWeek(t *testing.T) {
+	is := is.New(t)
+
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	period := helpers.DateRange{From: monday, To: monday.AddDate(0, 0, 14)}
+	stats := apppumping.Stats{
+		ThingID: "ps-1",
+		Starts:  3,
+		Days:    make([]apppumping.Bucket, 14),
+		Weeks:   []apppumping.Bucket{{Start: monday, Starts: 2}, {Start: monday.AddDate(0, 0, 7), Starts: 1}},
+	}
+
+	model := pumpingStatsViewModel(period, stats, "week")
+	is.Equal("2025-03-03", model.From)
+	is.Equal("2025-03-16", model.To)
+	is.Equal(2, len(model.Rows))
+	is.Equal("2025-W10", model.Rows[0].Label)
+	is.Equal(3.0/14, model.StartsPerDay)
+}
@@ -0,0 +1,201 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/rules"
+	appsandstorage "github.com/diwise/diwise-web/internal/application/sandstorage"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+func NewThingSandStorageComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app sandStorageApp) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		writeSandStorage(w, r, l10n, app, id)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+type sandStorageApp interface {
+	appthings.Management
+	appsandstorage.Management
+	rules.Management
+}
+
+// NewThingSandStorageFillComponentHandler renders the level of a sand storage with several sensors in
+// the list of things as the fill estimate of the storage. The level from the things service is kept if
+// the estimate cannot be fetched.
+func NewThingSandStorageFillComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app sandStorageApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		thing, err := app.GetThing(ctx, id, nil)
+		if err != nil {
+			http.Error(w, "thing not found", http.StatusNotFound)
+			return
+		}
+
+		model := toViewModel(thing)
+		model.Properties["sensors"] = 0.0
+
+		storage, err := app.GetSandStorage(ctx, id)
+		if err != nil {
+			log.Debug("could not fetch sand storage", "thing_id", id, "err", err.Error())
+		} else {
+			if storage.Fill != nil {
+				model.Properties["percent"] = *storage.Fill
+			}
+			model.Properties["sensors"] = float64(len(storage.Sensors))
+		}
+
+		models := []featuresthings.ThingViewModel{model}
+		withTones(ctx, app, []appthings.Thing{thing}, models)
+
+		localizer := l10n.For(helpers.Language(r))
+		helpers.WriteComponentResponse(ctx, w, r, featuresthings.ThingStatusCell(localizer, models[0]), 1024, 0)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewThingSandStorageAggregationHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app sandStorageApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if id == "" {
+			http.Error(w, "no id found in url", http.StatusBadRequest)
+			return
+		}
+
+		err := app.SetFillAggregation(r.Context(), id, r.FormValue("aggregation"))
+		if errors.Is(err, appsandstorage.ErrInvalid) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, appsandstorage.ErrNotFound) {
+			http.Error(w, "sand storage not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Error("could not save fill aggregation", "thing_id", id, "err", err.Error())
+			http.Error(w, "could not save fill aggregation", http.StatusInternalServerError)
+			return
+		}
+
+		writeSandStorage(w, r, l10n, app, id)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func writeSandStorage(w http.ResponseWriter, r *http.Request, l10n LocaleBundle, app sandStorageApp, id string) {
+	ctx := r.Context()
+	log := logging.GetFromContext(ctx)
+	localizer := l10n.For(helpers.Language(r))
+	loc := helpers.Location(ctx)
+
+	period, err := helpers.ParseDateRange(r, time.Now().In(loc).AddDate(0, 0, 1-appsandstorage.DefaultRefillDays))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	thing, err := app.GetThing(ctx, id, nil)
+	if err != nil {
+		http.Error(w, "thing not found", http.StatusNotFound)
+		return
+	}
+
+	storage, err := app.GetSandStorage(ctx, id)
+	if err != nil {
+		log.Error("could not fetch sand storage", "thing_id", id, "err", err.Error())
+		http.Error(w, "could not fetch sand storage", http.StatusInternalServerError)
+		return
+	}
+
+	refills, err := app.GetRefills(ctx, id, period.From, period.To)
+	if err != nil {
+		log.Error("could not fetch refills", "thing_id", id, "err", err.Error())
+		http.Error(w, "could not fetch refills", http.StatusInternalServerError)
+		return
+	}
+
+	model := sandStorageViewModel(period, loc, storage, refills)
+	model.Tone = fillTone(ctx, app, thing, storage.Fill)
+
+	component := featuresthings.ThingSandStorage(localizer, model)
+	helpers.WriteComponentResponse(ctx, w, r, component, 8*1024, 0)
+}
+
+// fillTone tells how the fill estimate of a sand storage compares to the limits of the rule for the
+// filling level of the storage, the same rule that the list of things is toned by.
+func fillTone(ctx context.Context, app rules.Management, thing appthings.Thing, fill *float64) string {
+	if fill == nil {
+		return ""
+	}
+
+	all, err := app.GetRules(ctx)
+	if err != nil {
+		logging.GetFromContext(ctx).Error("could not fetch rules", "err", err.Error())
+		all = rules.Defaults
+	}
+
+	rule, ok := rules.Select(all, rules.SubjectOf(thing), "percent")
+	if !ok {
+		return ""
+	}
+	return rule.Tone(*fill)
+}
+
+// sandStorageViewModel lists the latest refill first.
+func sandStorageViewModel(period helpers.DateRange, loc *time.Location, storage appsandstorage.Storage, refills []appsandstorage.Refill) featuresthings.SandStorageViewModel {
+	model := featuresthings.SandStorageViewModel{
+		ThingID:      storage.ThingID,
+		From:         period.FirstDay(),
+		To:           period.LastDay(),
+		Aggregation:  storage.Aggregation,
+		Aggregations: appsandstorage.Aggregations,
+		Fill:         storage.Fill,
+		Silent:       storage.Silent,
+	}
+
+	for _, s := range storage.Sensors {
+		model.Sensors = append(model.Sensors, featuresthings.SandStorageSensorViewModel{
+			DeviceID:   s.DeviceID,
+			Percent:    s.Percent,
+			ObservedAt: s.ObservedAt.In(loc).Format("2006-01-02 15:04"),
+		})
+	}
+
+	for _, refill := range refills {
+		model.Refills = append([]featuresthings.RefillViewModel{{
+			At:     refill.At.In(loc).Format("2006-01-02 15:04"),
+			Before: refill.Before,
+			After:  refill.After,
+		}}, model.Refills...)
+	}
+
+	return model
+}
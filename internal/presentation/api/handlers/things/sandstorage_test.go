@@ -0,0 +1,110 @@
+package things
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diwise/diwise-web/internal/application/rules"
+	appsandstorage "github.com/diwise/diwise-web/internal/application/sandstorage"
+	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	"github.com/matryer/is"
+)
+
+func TestSandStorageViewModelListsTheLatestRefillFirst(t *testing.T) {
+	is := is.New(t)
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	model := sandStorageViewModel(helpers.DateRange{From: day, To: day.AddDate(0, 0, 14)}, time.UTC, appsandstorage.Storage{
+		ThingID:     "storage-1",
+		Aggregation: appsandstorage.AggregationMin,
+		Sensors:     []appsandstorage.Sensor{{DeviceID: "a", Percent: 55, ObservedAt: day.Add(6 * time.Hour)}},
+		Silent:      []string{"b"},
+		Fill:        new(55.0),
+	}, []appsandstorage.Refill{
+		{At: day.Add(24 * time.Hour), Before: 10, After: 80},
+		{At: day.Add(240 * time.Hour), Before: 30, After: 75},
+	})
+
+	is.Equal("2026-10-01 06:00", model.Sensors[0].ObservedAt)
+	is.Equal([]string{"b"}, model.Silent)
+	is.Equal(2, len(model.Refills))
+	is.Equal("2026-10-11 00:00", model.Refills[0].At)
+	is.Equal(appsandstorage.Aggregations, model.Aggregations)
+}
+
+func TestFillToneUsesTheRuleOfTheSandStorage(t *testing.T) {
+	is := is.New(t)
+
+	thing := appthings.Thing{ID: "storage-1", Type: "Container", SubType: "Sandstorage", Tenant: "default"}
+	app := testSandStorageFillApp{}
+
+	// the default rule of sand storages
+	is.Equal(rules.ToneWarning, fillTone(context.Background(), app, thing, new(55.0)))
+	is.Equal("", fillTone(context.Background(), app, thing, nil))
+
+	app.rules = append(rules.Defaults, rules.Rule{ID: "r1", Tenant: "default", Scope: rules.ScopeSubType, Target: "Sandstorage", Property: "percent", Warning: new(40.0), Critical: new(20.0), Below: true})
+	is.Equal(rules.ToneGood, fillTone(context.Background(), app, thing, new(55.0)))
+	is.Equal(rules.ToneCritical, fillTone(context.Background(), app, thing, new(20.0)))
+}
+
+func TestSandStorageFillComponentShowsTheFillEstimate(t *testing.T) {
+	is := is.New(t)
+
+	app := testSandStorageFillApp{
+		testThingsApp: &testThingsApp{thing: appthings.Thing{
+			ID:         "storage-1",
+			Type:       "Container",
+			SubType:    "Sandstorage",
+			ObservedAt: time.Now(),
+			RefDevices: []appthings.RefDevice{{DeviceID: "a"}, {DeviceID: "b"}},
+		}},
+		storage: appsandstorage.Storage{ThingID: "storage-1", Fill: new(42.0), Sensors: []appsandstorage.Sensor{{DeviceID: "a"}, {DeviceID: "b"}}},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/components/things/storage-1/sandstorage/fill", nil)
+	r.SetPathValue("id", "storage-1")
+	NewThingSandStorageFillComponentHandler(context.Background(), testLocaleBundle(), nil, app).ServeHTTP(w, r)
+
+	is.Equal(http.StatusOK, w.Code)
+	body := w.Body.String()
+	is.True(strings.Contains(body, "fillestimate, 2 sensors"))
+	is.True(strings.Contains(body, "42"))
+	// the estimate is not loaded again
+	is.True(!strings.Contains(body, "hx-get"))
+}
+
+type testSandStorageFillApp struct {
+	*testThingsApp
+	appsandstorage.Management
+	storage appsandstorage.Storage
+	rules   []rules.Rule
+}
+
+func (a testSandStorageFillApp) GetSandStorage(context.Context, string) (appsandstorage.Storage, error) {
+	return a.storage, nil
+}
+
+func (a testSandStorageFillApp) GetRules(context.Context) ([]rules.Rule, error) {
+	if a.rules == nil {
+		return rules.Defaults, nil
+	}
+	return a.rules, nil
+}
+
+func (a testSandStorageFillApp) SaveRule(_ context.Context, rule rules.Rule) (rules.Rule, error) {
+	return rule, nil
+}
+
+func (a testSandStorageFillApp) DeleteRule(context.Context, string) error {
+	return nil
+}
+
+func (a testSandStorageFillApp) GetAlerts(context.Context) ([]rules.Alert, error) {
+	return nil, nil
+}
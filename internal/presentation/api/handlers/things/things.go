@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"net/url"
@@ -13,11 +14,14 @@ import (
 
 	"github.com/a-h/templ"
 	"github.com/diwise/diwise-web/internal/application/admin"
-	"github.com/diwise/diwise-web/internal/application/client"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/application/rules"
 	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/handlers/views"
 	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
 	featuresthings "github.com/diwise/diwise-web/internal/presentation/web/components/features/things"
 	v2layout "github.com/diwise/diwise-web/internal/presentation/web/components/layout"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
 	"github.com/google/uuid"
 
 	. "github.com/diwise/frontend-toolkit"
@@ -28,7 +32,18 @@ type thingsApp interface {
 	appthings.Management
 }
 
-func NewThingsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+// thingsListApp also compares the values shown in the list with the threshold rules.
+type thingsListApp interface {
+	thingsApp
+	rules.Management
+}
+
+type thingsPageApp interface {
+	thingsListApp
+	views.Lister
+}
+
+func NewThingsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFunc, app thingsPageApp) http.HandlerFunc {
 	version := helpers.GetVersion(ctx)
 
 	fn := func(w http.ResponseWriter, r *http.Request) {
@@ -37,12 +52,17 @@ func NewThingsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFun
 			v2layout.CurrentComponent, "things",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		if views.ApplyDefaultView(w, r, app, "things") {
+			return
+		}
+
+		localizer := l10n.For(helpers.Language(r))
 		model, err := composeListModel(ctx, r, localizer, app)
 		if err != nil {
 			http.Error(w, "could not fetch things", http.StatusInternalServerError)
 			return
 		}
+		model.SavedViews = views.SavedViews(ctx, app, "things")
 
 		content := featuresthings.ThingsPage(localizer, model)
 		page := templ.Component(v2layout.StartPage(version, localizer, assets, content))
@@ -56,14 +76,14 @@ func NewThingsPage(ctx context.Context, l10n LocaleBundle, assets AssetLoaderFun
 	return http.HandlerFunc(fn)
 }
 
-func NewThingsDataList(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+func NewThingsDataList(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingsListApp) http.HandlerFunc {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ctx := helpers.Decorate(
 			r.Context(),
 			v2layout.CurrentComponent, "things",
 		)
 
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		model, err := composeListModel(ctx, r, localizer, app)
 		if err != nil {
 			http.Error(w, "could not fetch things", http.StatusInternalServerError)
@@ -77,14 +97,31 @@ func NewThingsDataList(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc,
 	return http.HandlerFunc(fn)
 }
 
-func NewThingComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+func NewThingComponentHandler(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+	bounds := helpers.GetMunicipalBounds(ctx)
+	schemas := helpers.GetPropertySchemas(ctx)
+
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		localizer := l10n.For(r.Header.Get("Accept-Language"))
+		localizer := l10n.For(helpers.Language(r))
 		model, err := composeNewThingModel(r.Context(), localizer, app)
 		if err != nil {
 			http.Error(w, "could not load new thing form", http.StatusInternalServerError)
 			return
 		}
+		model.Bounds = boundsViewModel(bounds)
+		model.Grids = gridOptions(r.Context())
+
+		if from := r.URL.Query().Get("from"); from != "" {
+			source, err := app.GetThing(r.Context(), from, nil)
+			if err != nil {
+				http.Error(w, "could not fetch thing to duplicate", http.StatusNotFound)
+				return
+			}
+			vm := toViewModel(source)
+			vm.PropertyFields = propertyFieldsOf(schemas, source)
+			model.Source = &vm
+			model.SourceType = typeOptionOf(model.TypeOptions, source)
+		}
 
 		component := featuresthings.NewThingModal(localizer, model)
 		helpers.WriteComponentResponse(r.Context(), w, r, component, 16*1024, 0)
@@ -93,7 +130,13 @@ func NewThingComponentHandler(_ context.Context, l10n LocaleBundle, _ AssetLoade
 	return http.HandlerFunc(fn)
 }
 
-func NewCreateThingPage(_ context.Context, _ LocaleBundle, _ AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+// NewCreateThingPage creates the thing, or the copies of a thing, in the form. What is wrong with the
+// form is shown in a toast in the form.
+func NewCreateThingPage(ctx context.Context, l10n LocaleBundle, _ AssetLoaderFunc, app thingsApp) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+	bounds := helpers.GetMunicipalBounds(ctx)
+	schemas := helpers.GetPropertySchemas(ctx)
+
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		if err := r.ParseForm(); err != nil {
 			http.Error(w, "could not parse form data", http.StatusBadRequest)
@@ -105,23 +148,117 @@ func NewCreateThingPage(_ context.Context, _ LocaleBundle, _ AssetLoaderFunc, ap
 			return
 		}
 
-		newThing := newThingFromForm(r.Form)
-		err := app.NewThing(r.Context(), newThing)
+		copies := 1
+		if value := strings.TrimSpace(r.Form.Get("copies")); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > featuresthings.MaxCopies {
+				http.Error(w, "invalid number of copies", http.StatusBadRequest)
+				return
+			}
+			copies = n
+		}
+
+		thingType, subType := splitThingType(r.Form.Get("type"))
+		properties, err := propertyFields(schemas, thingType, subType, r.Form)
 		if err != nil {
-			http.Error(w, "could not create new thing", http.StatusInternalServerError)
+			writeNewThingError(w, r, l10n, err)
 			return
 		}
 
-		http.Redirect(w, r, "/things/"+newThing.ID+"?mode=edit", http.StatusFound)
+		// every copy is read before any is created, so that an invalid form creates none of them
+		names := copyNames(r.Form.Get("name"), copies)
+		newThings := make([]appthings.Thing, 0, len(names))
+		for _, name := range names {
+			newThing, err := newThingFromForm(r.Form, bounds)
+			if err != nil {
+				writeNewThingError(w, r, l10n, err)
+				return
+			}
+			newThing.Name = name
+			newThings = append(newThings, newThing)
+		}
+
+		for i, newThing := range newThings {
+			if err := app.NewThing(r.Context(), newThing); err != nil {
+				log.Error("could not create new thing", "name", newThing.Name, "created", i, "copies", copies, "err", err.Error())
+				writeCopiesNotCreated(w, r, l10n, newThings, i, false)
+				return
+			}
+			// the things service takes the properties of a type as an update rather than on creation
+			if len(properties) > 0 {
+				if err := app.UpdateThing(r.Context(), newThing.ID, properties); err != nil {
+					log.Error("could not set the properties of new thing", "name", newThing.Name, "created", i+1, "copies", copies, "err", err.Error())
+					writeCopiesNotCreated(w, r, l10n, newThings, i+1, true)
+					return
+				}
+			}
+		}
+
+		target := "/things/" + newThings[0].ID + "?mode=edit"
+		if copies > 1 {
+			target = "/things"
+		}
+
+		if helpers.IsHxRequest(r) {
+			w.Header().Set("HX-Redirect", target)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		http.Redirect(w, r, target, http.StatusFound)
 	}
 
 	return http.HandlerFunc(fn)
 }
 
-func composeListModel(ctx context.Context, r *http.Request, localizer Localizer, app thingsApp) (featuresthings.ThingsPageViewModel, error) {
+// writeCopiesNotCreated tells which of the new things were created before one failed, since the
+// things service cannot create them all at once. When withoutProperties is set the last of the created
+// things is the one that failed, as it was created but its properties could not be set.
+func writeCopiesNotCreated(w http.ResponseWriter, r *http.Request, l10n LocaleBundle, newThings []appthings.Thing, created int, withoutProperties bool) {
+	failed, keys := created, [2]string{"newthingfailed", "copiesnotcreated"}
+	if withoutProperties {
+		failed, keys = created-1, [2]string{"newthingpropertiesfailed", "copiespropertiesfailed"}
+	}
+
+	names := make([]string, 0, created)
+	for _, t := range newThings[:created] {
+		names = append(names, t.Name)
+	}
+
+	localizer := l10n.For(helpers.Language(r))
+	message := localizer.GetWithData(keys[0], map[string]any{"name": newThings[failed].Name})
+	if len(newThings) > 1 && len(names) > 0 {
+		message = localizer.GetWithData(keys[1], map[string]any{
+			"name":    newThings[failed].Name,
+			"count":   len(names),
+			"total":   len(newThings),
+			"created": strings.Join(names, ", "),
+		})
+	}
+
+	if helpers.IsHxRequest(r) {
+		helpers.WriteComponentResponse(r.Context(), w, r, thingValidationToast(message), 4*1024, 0)
+		return
+	}
+
+	http.Error(w, message, http.StatusInternalServerError)
+}
+
+// writeNewThingError shows what is wrong with the form of a new thing in a toast in the form.
+func writeNewThingError(w http.ResponseWriter, r *http.Request, l10n LocaleBundle, err error) {
+	message := localizeThingValidationMessage(l10n.For(helpers.Language(r)), err)
+	if helpers.IsHxRequest(r) {
+		helpers.WriteComponentResponse(r.Context(), w, r, thingValidationToast(message), 4*1024, 0)
+		return
+	}
+
+	http.Error(w, message, http.StatusBadRequest)
+}
+
+func composeListModel(ctx context.Context, r *http.Request, localizer Localizer, app thingsListApp) (featuresthings.ThingsPageViewModel, error) {
 	pageIndex := helpers.UrlParamOrDefault(r, "page", "1")
 	offset, limit := helpers.GetOffsetAndLimit(r)
-	showMap := r.URL.Query().Get("mapview") == "true"
+	showMap := helpers.ShowMapView(r)
 
 	args := r.URL.Query()
 	helpers.SanitizeParams(args, "mapview", "page", "limit", "offset")
@@ -197,9 +334,29 @@ func composeListModel(ctx context.Context, r *http.Request, localizer Localizer,
 		model.Things = append(model.Things, toViewModel(thing))
 	}
 
+	withTones(ctx, app, result.Things, model.Things)
+
 	return model, nil
 }
 
+// withTones compares the values shown in the list with the threshold rules. The fill estimate of a
+// sand storage is compared rather than the level from the backend.
+func withTones(ctx context.Context, app rules.Management, things []appthings.Thing, models []featuresthings.ThingViewModel) {
+	all, err := app.GetRules(ctx)
+	if err != nil {
+		logging.GetFromContext(ctx).Error("could not fetch rules", "err", err.Error())
+		all = rules.Defaults
+	}
+
+	for i, t := range things {
+		values := rules.Values(t)
+		if percent, ok := models[i].Properties["percent"].(float64); ok {
+			values["percent"] = percent
+		}
+		models[i].Tones = rules.Tones(all, rules.SubjectOf(t), values)
+	}
+}
+
 func composeNewThingModel(ctx context.Context, localizer Localizer, app thingsApp) (featuresthings.NewThingViewModel, error) {
 	types, err := app.GetTypes(ctx)
 	if err != nil {
@@ -226,20 +383,19 @@ func composeNewThingModel(ctx context.Context, localizer Localizer, app thingsAp
 	}, nil
 }
 
-func newThingFromForm(form url.Values) appthings.Thing {
-	id := uuid.NewString()
-	thingType := strings.TrimSpace(form.Get("type"))
-	thingSubType := ""
-	switch {
-	case strings.Contains(thingType, ":"):
-		parts := strings.SplitN(thingType, ":", 2)
-		thingType = parts[0]
-		thingSubType = parts[1]
-	case strings.Contains(thingType, "-"):
-		parts := strings.SplitN(thingType, "-", 2)
-		thingType = parts[0]
-		thingSubType = parts[1]
+// newThingFromForm reads a new thing from the form. Unlike when a thing is edited, the location
+// cannot be left out, since the things service would place the thing at 0,0.
+func newThingFromForm(form url.Values, bounds geo.BoundingBox) (appthings.Thing, error) {
+	location, ok, err := locationFromForm(form, bounds)
+	if err != nil {
+		return appthings.Thing{}, err
 	}
+	if !ok {
+		return appthings.Thing{}, errNoLocation
+	}
+
+	id := uuid.NewString()
+	thingType, thingSubType := splitThingType(form.Get("type"))
 
 	return appthings.Thing{
 		ID:          id,
@@ -247,12 +403,50 @@ func newThingFromForm(form url.Values) appthings.Thing {
 		SubType:     thingSubType,
 		Name:        strings.TrimSpace(form.Get("name")),
 		Description: strings.TrimSpace(form.Get("description")),
-		Location: client.Location{
-			Latitude:  0,
-			Longitude: 0,
-		},
-		Tenant: strings.TrimSpace(form.Get("organisation")),
+		Location:    location,
+		Tags:        normalizeListValues(form["tags"]),
+		Tenant:      strings.TrimSpace(form.Get("organisation")),
+	}, nil
+}
+
+// splitThingType splits a type option such as Container-WasteContainer into type and subtype.
+func splitThingType(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	for _, separator := range []string{":", "-"} {
+		if thingType, subType, ok := strings.Cut(value, separator); ok {
+			return thingType, subType
+		}
+	}
+	return value, ""
+}
+
+// typeOptionOf returns the type option that a thing was created with, or an empty string if the
+// type is no longer offered.
+func typeOptionOf(options []featuresthings.TypeOption, thing appthings.Thing) string {
+	for _, option := range options {
+		thingType, subType := splitThingType(option.Value)
+		if strings.EqualFold(thingType, thing.Type) && strings.EqualFold(subType, thing.SubType) {
+			return option.Value
+		}
+	}
+	return ""
+}
+
+// copyNames returns the names of copies of a thing. A {n} in the pattern is replaced by the number
+// of the copy, padded so that the names sort in order, and is added at the end of the pattern when
+// several copies are made without it.
+func copyNames(pattern string, copies int) []string {
+	pattern = strings.TrimSpace(pattern)
+	if copies > 1 && !strings.Contains(pattern, "{n}") {
+		pattern = strings.TrimSpace(pattern + " {n}")
+	}
+
+	width := len(strconv.Itoa(copies))
+	names := make([]string, 0, copies)
+	for i := 1; i <= copies; i++ {
+		names = append(names, strings.ReplaceAll(pattern, "{n}", fmt.Sprintf("%0*d", width, i)))
 	}
+	return names
 }
 
 func normalizeTypeFilter(args url.Values) []string {
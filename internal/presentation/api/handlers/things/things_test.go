@@ -2,16 +2,24 @@ package things
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/diwise/diwise-web/internal/application/admin"
 	"github.com/diwise/diwise-web/internal/application/client"
 	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/application/properties"
 	appthings "github.com/diwise/diwise-web/internal/application/things"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	frontendtoolkit "github.com/diwise/frontend-toolkit"
+	ftkmock "github.com/diwise/frontend-toolkit/mock"
 	"github.com/matryer/is"
 )
 
@@ -78,10 +86,13 @@ func TestNewThingFromFormSplitsSubtypeAndMapsFields(t *testing.T) {
 		"name":         {"Sandficka A"},
 		"description":  {"Beskrivning"},
 		"organisation": {"tenant-a"},
+		"latitude":     {"62.3908"},
+		"longitude":    {"17.3069"},
 	}
 
-	thing := newThingFromForm(form)
+	thing, err := newThingFromForm(form, geo.BoundingBox{})
 
+	is.NoErr(err)
 	is.True(thing.ID != "")
 	is.Equal(appthings.Thing{
 		ID:          thing.ID,
@@ -90,13 +101,41 @@ func TestNewThingFromFormSplitsSubtypeAndMapsFields(t *testing.T) {
 		Name:        "Sandficka A",
 		Description: "Beskrivning",
 		Location: client.Location{
-			Latitude:  0,
-			Longitude: 0,
+			Latitude:  62.3908,
+			Longitude: 17.3069,
 		},
 		Tenant: "tenant-a",
 	}, thing)
 }
 
+func TestNewThingFromFormPlacesThingWithinBounds(t *testing.T) {
+	is := is.New(t)
+
+	bounds := geo.BoundingBox{MinLongitude: 16.5, MinLatitude: 62.1, MaxLongitude: 17.9, MaxLatitude: 62.75}
+	form := url.Values{
+		"type":      {"Container"},
+		"latitude":  {"62.390800"},
+		"longitude": {"17.306900"},
+	}
+
+	thing, err := newThingFromForm(form, bounds)
+	is.NoErr(err)
+	is.Equal(client.Location{Latitude: 62.3908, Longitude: 17.3069}, thing.Location)
+
+	form.Set("latitude", "57.708870")
+	_, err = newThingFromForm(form, bounds)
+	is.True(errors.Is(err, geo.ErrOutside))
+
+	form.Del("latitude")
+	_, err = newThingFromForm(form, bounds)
+	is.True(errors.Is(err, geo.ErrInvalid))
+
+	// a new thing is not placed at 0,0 when it has no location
+	form.Del("longitude")
+	_, err = newThingFromForm(form, geo.BoundingBox{})
+	is.True(errors.Is(err, errNoLocation))
+}
+
 func TestNewCreateThingPageRedirectsWithoutSaveFlag(t *testing.T) {
 	is := is.New(t)
 
@@ -129,6 +168,8 @@ func TestNewCreateThingPageCreatesThingWhenSaveFlagPresent(t *testing.T) {
 		"type":         {"Container-Sandstorage"},
 		"name":         {"Sandficka A"},
 		"organisation": {"tenant-a"},
+		"latitude":     {"62.3908"},
+		"longitude":    {"17.3069"},
 		"save":         {"true"},
 	}
 	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
@@ -145,18 +186,248 @@ func TestNewCreateThingPageCreatesThingWhenSaveFlagPresent(t *testing.T) {
 	is.Equal("tenant-a", app.createdThing.Tenant)
 }
 
+func TestNewCreateThingPageCreatesNumberedCopies(t *testing.T) {
+	is := is.New(t)
+
+	app := &testThingsApp{}
+	handler := NewCreateThingPage(context.Background(), nil, nil, app)
+
+	form := url.Values{
+		"type":         {"Container-WasteContainer"},
+		"name":         {"Kärl {n} Stora torget"},
+		"organisation": {"tenant-a"},
+		"tags":         {"torget", "sopor"},
+		"maxd":         {"1.20"},
+		"maxl":         {"0.95"},
+		"copies":       {"12"},
+		"latitude":     {"62.3908"},
+		"longitude":    {"17.3069"},
+		"save":         {"true"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusFound, rec.Code)
+	is.Equal("/things", rec.Header().Get("Location"))
+	is.Equal(12, len(app.createdThings))
+	is.Equal("Kärl 01 Stora torget", app.createdThings[0].Name)
+	is.Equal("Kärl 12 Stora torget", app.createdThings[11].Name)
+
+	ids := map[string]bool{}
+	for _, thing := range app.createdThings {
+		ids[thing.ID] = true
+		is.Equal("WasteContainer", thing.SubType)
+		is.Equal("tenant-a", thing.Tenant)
+		is.Equal([]string{"torget", "sopor"}, thing.Tags)
+		is.Equal(map[string]any{"maxd": 1.2, "maxl": 0.95}, app.updatedFields[thing.ID])
+	}
+	is.Equal(12, len(ids))
+}
+
+func TestNewCreateThingPageRejectsTooManyCopies(t *testing.T) {
+	is := is.New(t)
+
+	app := &testThingsApp{}
+	handler := NewCreateThingPage(context.Background(), nil, nil, app)
+
+	form := url.Values{"type": {"Container"}, "copies": {"1000"}, "save": {"true"}}
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusBadRequest, rec.Code)
+	is.Equal(false, app.newThingCalled)
+}
+
+func TestNewCreateThingPageUsesTheConfiguredSchemas(t *testing.T) {
+	is := is.New(t)
+
+	schemas := properties.Schemas{{Types: []string{"Sandstorage"}, Fields: []properties.Field{
+		{Name: "volume", Label: "volume", Unit: "m3", Type: properties.TypeInteger, Required: true},
+	}}}
+	app := &testThingsApp{}
+	handler := NewCreateThingPage(helpers.WithPropertySchemas(context.Background(), schemas), testLocaleBundle(), nil, app)
+
+	form := url.Values{
+		"type":      {"Container-Sandstorage"},
+		"name":      {"Sandficka A"},
+		"volume":    {"12"},
+		"angle":     {"120"},
+		"latitude":  {"62.3908"},
+		"longitude": {"17.3069"},
+		"save":      {"true"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusFound, rec.Code)
+	is.Equal(map[string]any{"volume": 12.0}, app.updatedFields[app.createdThing.ID])
+}
+
+func TestNewCreateThingPageShowsWhyTheFormIsInvalid(t *testing.T) {
+	is := is.New(t)
+
+	app := &testThingsApp{}
+	handler := NewCreateThingPage(context.Background(), testLocaleBundle(), nil, app)
+
+	form := url.Values{"type": {"Container"}, "name": {"Kärl"}, "organisation": {"tenant-a"}, "save": {"true"}}
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusOK, rec.Code)
+	is.True(strings.Contains(rec.Body.String(), "locationrequired"))
+	is.Equal(false, app.newThingCalled)
+}
+
+func TestNewCreateThingPageTellsWhichCopiesWereCreated(t *testing.T) {
+	is := is.New(t)
+
+	app := &testThingsApp{failOn: "Kärl 3"}
+	bundle := testLocaleBundle()
+	bundle.ForFunc = func(string) frontendtoolkit.Localizer {
+		return &ftkmock.LocalizerMock{
+			GetWithDataFunc: func(key string, data map[string]any) string {
+				return fmt.Sprintf("%s: %v of %v (%v), %v", key, data["count"], data["total"], data["created"], data["name"])
+			},
+		}
+	}
+	handler := NewCreateThingPage(context.Background(), bundle, nil, app)
+
+	form := url.Values{
+		"type":      {"Container"},
+		"name":      {"Kärl {n}"},
+		"copies":    {"4"},
+		"latitude":  {"62.3908"},
+		"longitude": {"17.3069"},
+		"save":      {"true"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.Equal(2, len(app.createdThings))
+	is.True(strings.Contains(rec.Body.String(), "copiesnotcreated: 2 of 4 (Kärl 1, Kärl 2), Kärl 3"))
+}
+
+func TestNewCreateThingPageTellsWhichCopyIsMissingItsProperties(t *testing.T) {
+	is := is.New(t)
+
+	app := &testThingsApp{failUpdateOn: "Sandficka 2"}
+	bundle := testLocaleBundle()
+	bundle.ForFunc = func(string) frontendtoolkit.Localizer {
+		return &ftkmock.LocalizerMock{
+			GetWithDataFunc: func(key string, data map[string]any) string {
+				return fmt.Sprintf("%s: %v of %v (%v), %v", key, data["count"], data["total"], data["created"], data["name"])
+			},
+		}
+	}
+	handler := NewCreateThingPage(context.Background(), bundle, nil, app)
+
+	form := url.Values{
+		"type":      {"Container-Sandstorage"},
+		"name":      {"Sandficka {n}"},
+		"copies":    {"3"},
+		"angle":     {"45"},
+		"latitude":  {"62.3908"},
+		"longitude": {"17.3069"},
+		"save":      {"true"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.Equal(2, len(app.createdThings))
+	is.True(strings.Contains(rec.Body.String(), "copiespropertiesfailed: 2 of 3 (Sandficka 1, Sandficka 2), Sandficka 2"))
+}
+
+func TestCopyNames(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal([]string{"Kärl"}, copyNames(" Kärl ", 1))
+	is.Equal([]string{"Kärl 1"}, copyNames("Kärl {n}", 1))
+	is.Equal([]string{"Kärl 1", "Kärl 2", "Kärl 3"}, copyNames("Kärl", 3))
+	is.Equal([]string{"1", "2"}, copyNames("", 2))
+}
+
+func TestNewThingComponentFillsInTheThingToDuplicate(t *testing.T) {
+	is := is.New(t)
+
+	maxd := 1.5
+	app := &testThingsApp{
+		types: []string{"Container-WasteContainer", "Container-Sandstorage"},
+		thing: appthings.Thing{
+			ID:          "source",
+			Type:        "Container",
+			SubType:     "WasteContainer",
+			Name:        "Kärl 7",
+			Description: "Grönt kärl",
+			Tenant:      "tenant-a",
+			Tags:        []string{"torget"},
+			Location:    client.Location{Latitude: 62.39, Longitude: 17.3},
+			RefDevices:  []appthings.RefDevice{{DeviceID: "sensor-7"}},
+			TypeValues:  appthings.TypeValues{MaxDistance: &maxd},
+		},
+	}
+	handler := NewThingComponentHandler(context.Background(), testLocaleBundle(), nil, app)
+
+	req := httptest.NewRequest(http.MethodGet, "/components/things/new?from=source", nil)
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	is.Equal(http.StatusOK, rec.Code)
+	is.True(strings.Contains(body, "Grönt kärl"))
+	is.True(strings.Contains(body, `name="tags" value="torget"`))
+	is.True(strings.Contains(body, `value="1.50"`))
+	is.True(strings.Contains(body, `name="copies"`))
+	is.True(!strings.Contains(body, "sensor-7"))
+	is.True(!strings.Contains(body, `value="Kärl 7"`))
+	is.True(!strings.Contains(body, "62.390000"))
+}
+
 type testThingsApp struct {
 	newThingCalled bool
 	createdThing   appthings.Thing
+	createdThings  []appthings.Thing
 	thing          appthings.Thing
+	types          []string
 	validSensors   []appthings.SensorIdentifier
 	devices        map[string]devices.Device
 	updateCalled   bool
+	updatedFields  map[string]map[string]any
+	// failOn is the name of a thing that cannot be created
+	failOn string
+	// failUpdateOn is the name of a thing that cannot be updated
+	failUpdateOn string
 }
 
 func (a *testThingsApp) NewThing(_ context.Context, thing appthings.Thing) error {
+	if thing.Name == a.failOn {
+		return errors.New("could not create thing")
+	}
 	a.newThingCalled = true
 	a.createdThing = thing
+	a.createdThings = append(a.createdThings, thing)
 	return nil
 }
 
@@ -191,8 +462,15 @@ func (a *testThingsApp) ConnectSensor(context.Context, string, []string) error {
 	return nil
 }
 
-func (a *testThingsApp) UpdateThing(context.Context, string, map[string]any) error {
+func (a *testThingsApp) UpdateThing(_ context.Context, id string, fields map[string]any) error {
+	if slices.ContainsFunc(a.createdThings, func(t appthings.Thing) bool { return t.ID == id && t.Name == a.failUpdateOn }) {
+		return errors.New("could not update thing")
+	}
 	a.updateCalled = true
+	if a.updatedFields == nil {
+		a.updatedFields = map[string]map[string]any{}
+	}
+	a.updatedFields[id] = fields
 	return nil
 }
 
@@ -205,7 +483,7 @@ func (a *testThingsApp) GetTags(context.Context) ([]string, error) {
 }
 
 func (a *testThingsApp) GetTypes(context.Context) ([]string, error) {
-	return nil, nil
+	return a.types, nil
 }
 
 func (a *testThingsApp) GetTenants(context.Context) []string {
@@ -0,0 +1,199 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+
+	appviews "github.com/diwise/diwise-web/internal/application/views"
+	"github.com/diwise/diwise-web/internal/presentation/api/authz"
+	"github.com/diwise/diwise-web/internal/presentation/api/helpers"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// pages that support saved views
+var pages = []string{"sensors", "things"}
+
+// defaultViewCookie is followed by the page name and marks that the default view of the page has
+// been applied in the current browser session
+const defaultViewCookie = "defaultview_"
+
+type Lister interface {
+	GetViews(ctx context.Context, page string) ([]appviews.View, error)
+	GetDefaultView(ctx context.Context, page string) (appviews.View, error)
+}
+
+func NewSaveViewHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app appviews.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, "could not parse form", http.StatusBadRequest)
+			return
+		}
+
+		page := r.Form.Get("page")
+		if !slices.Contains(pages, page) {
+			http.Error(w, "unknown page", http.StatusBadRequest)
+			return
+		}
+
+		view, err := app.SaveView(ctx, appviews.View{
+			Name:   r.Form.Get("name"),
+			Page:   page,
+			Query:  r.Form.Get("query"),
+			Shared: r.Form.Get("shared") == "true",
+		})
+		if err != nil {
+			log.Error("could not save view", "err", err.Error())
+			http.Error(w, "could not save view", http.StatusBadRequest)
+			return
+		}
+
+		if r.Form.Get("default") == "true" {
+			err = app.SetDefaultView(ctx, page, view.ID)
+			if err != nil {
+				log.Error("could not set default view", "err", err.Error())
+			}
+		}
+
+		http.Redirect(w, r, ViewHref(view), http.StatusSeeOther)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewDeleteViewHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app appviews.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+
+		view, err := app.GetView(ctx, id)
+		if err != nil {
+			http.Error(w, "view not found", http.StatusNotFound)
+			return
+		}
+
+		err = app.DeleteView(ctx, id)
+		if errors.Is(err, appviews.ErrForbidden) {
+			http.Error(w, "only the owner can delete a view", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			log.Error("could not delete view", "view_id", id, "err", err.Error())
+			http.Error(w, "could not delete view", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/"+view.Page, http.StatusSeeOther)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+func NewDefaultViewHandler(ctx context.Context, _ LocaleBundle, _ AssetLoaderFunc, app appviews.Management) http.HandlerFunc {
+	log := logging.GetFromContext(ctx)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.PathValue("id")
+
+		view, err := app.GetView(ctx, id)
+		if err != nil {
+			http.Error(w, "view not found", http.StatusNotFound)
+			return
+		}
+
+		if r.FormValue("clear") == "true" {
+			id = ""
+		}
+
+		err = app.SetDefaultView(ctx, view.Page, id)
+		if err != nil {
+			log.Error("could not set default view", "view_id", id, "err", err.Error())
+			http.Error(w, "could not set default view", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, ViewHref(view), http.StatusSeeOther)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// SavedViews returns the saved views of a page as view models for the saved views menu.
+func SavedViews(ctx context.Context, app Lister, page string) []shared.SavedView {
+	views, err := app.GetViews(ctx, page)
+	if err != nil {
+		logging.GetFromContext(ctx).Warn("could not fetch saved views", "page", page, "err", err.Error())
+		return nil
+	}
+
+	subject := authz.Subject(ctx)
+	result := make([]shared.SavedView, 0, len(views))
+	for _, view := range views {
+		result = append(result, shared.SavedView{
+			ID:      view.ID,
+			Name:    view.Name,
+			Href:    ViewHref(view),
+			Shared:  view.Shared,
+			Default: view.Default,
+			Owned:   view.Owner == subject,
+		})
+	}
+
+	return result
+}
+
+// ApplyDefaultView applies the user's default view to the first request in a browser session for a page
+// without any filters. Full page requests are redirected to the view and true is returned. For htmx requests
+// the query of the request is replaced with the one from the view, and the browser is told to replace its
+// url. A session cookie remembers that the view has been applied, so that clearing the filters later on
+// shows the unfiltered page.
+func ApplyDefaultView(w http.ResponseWriter, r *http.Request, app Lister, page string) bool {
+	if r.URL.RawQuery != "" || r.Method != http.MethodGet {
+		return false
+	}
+
+	cookieName := defaultViewCookie + page
+	if _, err := r.Cookie(cookieName); err == nil {
+		return false
+	}
+
+	view, err := app.GetDefaultView(r.Context(), page)
+	if err != nil || view.Query == "" {
+		return false
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    "applied",
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if helpers.IsHxRequest(r) {
+		r.URL.RawQuery = view.Query
+		w.Header().Set("HX-Replace-Url", ViewHref(view))
+		return false
+	}
+
+	http.Redirect(w, r, ViewHref(view), http.StatusFound)
+	return true
+}
+
+func ViewHref(view appviews.View) string {
+	u := url.URL{Path: "/" + view.Page, RawQuery: view.Query}
+	return u.String()
+}
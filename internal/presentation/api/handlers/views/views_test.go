@@ -0,0 +1,50 @@
+package views
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appviews "github.com/diwise/diwise-web/internal/application/views"
+	"github.com/matryer/is"
+)
+
+func TestApplyDefaultViewOnlyOnTheFirstVisit(t *testing.T) {
+	is := is.New(t)
+	app := testLister{view: appviews.View{ID: "v1", Page: "things", Query: "type=Container"}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/things", nil)
+	is.True(ApplyDefaultView(w, r, app, "things"))
+	is.Equal(http.StatusFound, w.Code)
+	is.Equal("/things?type=Container", w.Header().Get("Location"))
+
+	cookies := w.Result().Cookies()
+	is.Equal(1, len(cookies))
+
+	// once the view has been applied, the unfiltered page is shown
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/things", nil)
+	r.AddCookie(cookies[0])
+	is.True(!ApplyDefaultView(w, r, app, "things"))
+	is.Equal("", r.URL.RawQuery)
+
+	// requests with filters are never changed
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/things?limit=15", nil)
+	is.True(!ApplyDefaultView(w, r, app, "things"))
+	is.Equal("limit=15", r.URL.RawQuery)
+}
+
+type testLister struct {
+	view appviews.View
+}
+
+func (l testLister) GetViews(context.Context, string) ([]appviews.View, error) {
+	return []appviews.View{l.view}, nil
+}
+
+func (l testLister) GetDefaultView(context.Context, string) (appviews.View, error) {
+	return l.view, nil
+}
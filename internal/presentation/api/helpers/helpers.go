@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -16,6 +17,9 @@ import (
 	"time"
 
 	"github.com/a-h/templ"
+	"github.com/diwise/diwise-web/internal/application/geo"
+	"github.com/diwise/diwise-web/internal/application/properties"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
 	"github.com/diwise/frontend-toolkit/pkg/middleware/csp"
 	"github.com/diwise/service-chassis/pkg/infrastructure/o11y/logging"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -24,6 +28,16 @@ import (
 type versionKeyType string
 
 const versionKey versionKeyType = "version"
+const chartPointBudgetKey versionKeyType = "chartPointBudget"
+const userPreferencesKey versionKeyType = "userPreferences"
+const municipalBoundsKey versionKeyType = "municipalBounds"
+const propertySchemasKey versionKeyType = "propertySchemas"
+
+// DefaultPageSize is the number of rows in a paged list when neither the request nor the user preferences say otherwise.
+const DefaultPageSize int = 15
+
+// DefaultChartPointBudget is the maximum number of points per chart series when no budget has been configured.
+const DefaultChartPointBudget int = 500
 
 func Decorate(ctx context.Context, kv ...any) context.Context {
 	for kvidx := range len(kv) / 2 {
@@ -48,6 +62,185 @@ func GetVersion(ctx context.Context) string {
 	return ""
 }
 
+func WithChartPointBudget(ctx context.Context, budget int) context.Context {
+	return context.WithValue(ctx, chartPointBudgetKey, budget)
+}
+
+func GetChartPointBudget(ctx context.Context) int {
+	budget := FromCtxInt(ctx, chartPointBudgetKey, DefaultChartPointBudget)
+	if budget <= 0 {
+		return DefaultChartPointBudget
+	}
+	return budget
+}
+
+// WithMunicipalBounds sets the area that things may be placed in.
+func WithMunicipalBounds(ctx context.Context, bounds geo.BoundingBox) context.Context {
+	return context.WithValue(ctx, municipalBoundsKey, bounds)
+}
+
+// GetMunicipalBounds returns the area that things may be placed in. The zero bounding box allows
+// things anywhere.
+func GetMunicipalBounds(ctx context.Context) geo.BoundingBox {
+	bounds, _ := ctx.Value(municipalBoundsKey).(geo.BoundingBox)
+	return bounds
+}
+
+// WithPropertySchemas sets the properties that things of each type have.
+func WithPropertySchemas(ctx context.Context, schemas properties.Schemas) context.Context {
+	return context.WithValue(ctx, propertySchemasKey, schemas)
+}
+
+// GetPropertySchemas returns the properties that things of each type have, or the default schemas
+// if none have been set.
+func GetPropertySchemas(ctx context.Context) properties.Schemas {
+	if schemas, ok := ctx.Value(propertySchemasKey).(properties.Schemas); ok {
+		return schemas
+	}
+	return properties.Defaults
+}
+
+// UserPreferences are the personal defaults of the current user that handlers consult instead of
+// hard-coded defaults. Zero values mean that the user has no preference.
+type UserPreferences struct {
+	PageSize   int
+	MapView    bool
+	Location   *time.Location
+	ChartStart time.Time
+	ChartEnd   time.Time
+
+	// CoordinateSystem is the system that positions are shown in, see geo.Systems.
+	CoordinateSystem string
+	// Language is the language tag that pages are shown in, rather than the one asked for by the browser.
+	Language string
+}
+
+func WithUserPreferences(ctx context.Context, prefs UserPreferences) context.Context {
+	return context.WithValue(ctx, userPreferencesKey, prefs)
+}
+
+func GetUserPreferences(ctx context.Context) UserPreferences {
+	prefs, _ := ctx.Value(userPreferencesKey).(UserPreferences)
+	return prefs
+}
+
+// Language returns the preferred language of the current user, or else the Accept-Language header of
+// the request, for localizers and locale aware formatting.
+func Language(r *http.Request) string {
+	if language := GetUserPreferences(r.Context()).Language; language != "" {
+		return language
+	}
+	return r.Header.Get("Accept-Language")
+}
+
+// Location returns the preferred time zone of the current user, or the local time zone of the server.
+func Location(ctx context.Context) *time.Location {
+	if loc := GetUserPreferences(ctx).Location; loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// ChartAnnotation marks at on a measurement chart in the preferred time zone of the user, like the
+// values of the chart. A span is shaded until to if to is after at.
+func ChartAnnotation(ctx context.Context, at, to time.Time, label, color string) shared.ChartAnnotation {
+	loc := Location(ctx)
+
+	annotation := shared.ChartAnnotation{
+		X:     at.In(loc).Format("2006-01-02 15:04"),
+		Label: label,
+		Color: color,
+	}
+	if to.After(at) {
+		annotation.To = to.In(loc).Format("2006-01-02 15:04")
+	}
+
+	return annotation
+}
+
+// Projection returns the grid that the current user prefers to see positions in, if any.
+func Projection(ctx context.Context) (geo.Projection, bool) {
+	return geo.Lookup(GetUserPreferences(ctx).CoordinateSystem)
+}
+
+// GridPosition returns a position in the grid that the current user prefers, or nil if the user
+// prefers latitude and longitude or if there is no position.
+func GridPosition(ctx context.Context, latitude, longitude float64) *shared.GridPosition {
+	projection, ok := Projection(ctx)
+	if !ok || (latitude == 0 && longitude == 0) {
+		return nil
+	}
+
+	northing, easting := projection.FromPosition(geo.Position{Latitude: latitude, Longitude: longitude})
+	return &shared.GridPosition{
+		System:   projection.Name,
+		Northing: strconv.FormatFloat(northing, 'f', 2, 64),
+		Easting:  strconv.FormatFloat(easting, 'f', 2, 64),
+	}
+}
+
+// ShowMapView reports if a list should be shown as a map, either because the request asks for
+// it or because the user prefers the map when the request does not say.
+func ShowMapView(r *http.Request) bool {
+	if r.URL.Query().Has("mapview") {
+		return r.URL.Query().Get("mapview") == "true"
+	}
+	return GetUserPreferences(r.Context()).MapView
+}
+
+// DateRange is a period of whole days in the preferred time zone of the user. To is exclusive and
+// lies at midnight after the last day of the period.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// FirstDay formats the first day of the period for a date input.
+func (d DateRange) FirstDay() string {
+	return d.From.Format(time.DateOnly)
+}
+
+// LastDay formats the last day of the period for a date input.
+func (d DateRange) LastDay() string {
+	return d.To.AddDate(0, 0, -1).Format(time.DateOnly)
+}
+
+// ParseDateRange reads the dates in the from and to query parameters of r. The period defaults to
+// the days from defaultFrom up to and including today.
+func ParseDateRange(r *http.Request, defaultFrom time.Time) (DateRange, error) {
+	loc := Location(r.Context())
+	now := time.Now().In(loc)
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	defaultFrom = defaultFrom.In(loc)
+	period := DateRange{
+		From: time.Date(defaultFrom.Year(), defaultFrom.Month(), defaultFrom.Day(), 0, 0, 0, 0, loc),
+		To:   today.AddDate(0, 0, 1),
+	}
+
+	if from := r.URL.Query().Get("from"); from != "" {
+		t, err := time.ParseInLocation(time.DateOnly, from, loc)
+		if err != nil {
+			return period, fmt.Errorf("invalid from date: %w", err)
+		}
+		period.From = t
+	}
+
+	if to := r.URL.Query().Get("to"); to != "" {
+		t, err := time.ParseInLocation(time.DateOnly, to, loc)
+		if err != nil {
+			return period, fmt.Errorf("invalid to date: %w", err)
+		}
+		period.To = t.AddDate(0, 0, 1)
+	}
+
+	if !period.From.Before(period.To) {
+		return period, errors.New("the period must end after it starts")
+	}
+
+	return period, nil
+}
+
 func FromCtxInt(ctx context.Context, key any, defaultValue int) int {
 	v := ctx.Value(key)
 	if v == nil {
@@ -78,7 +271,11 @@ func UrlParamOrDefault(r *http.Request, param, defaultValue string) string {
 
 func GetOffsetAndLimit(r *http.Request) (offset, limit int) {
 	pageIndex := UrlParamOrDefault(r, "page", "1")
-	pageSize := UrlParamOrDefault(r, "limit", "15")
+	defaultPageSize := DefaultPageSize
+	if size := GetUserPreferences(r.Context()).PageSize; size > 0 {
+		defaultPageSize = size
+	}
+	pageSize := UrlParamOrDefault(r, "limit", strconv.Itoa(defaultPageSize))
 
 	limit, _ = strconv.Atoi(pageSize)
 	index, _ := strconv.Atoi(pageIndex)
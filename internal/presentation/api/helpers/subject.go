@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/diwise/diwise-web/internal/application/devices"
+	"github.com/diwise/diwise-web/internal/application/things"
+)
+
+// SubjectFinder fetches the things and sensors that notes, attachments and history are kept for.
+type SubjectFinder interface {
+	GetThing(ctx context.Context, id string, args map[string][]string) (things.Thing, error)
+	GetDevice(ctx context.Context, id string) (devices.Device, error)
+}
+
+// Subject reads the kind and id of the thing or sensor that a request is about from the url. The
+// thing or sensor is fetched so that nothing is read or written about those that the user cannot
+// see, and a 404 is written if it cannot be fetched.
+func Subject(w http.ResponseWriter, r *http.Request, app SubjectFinder) (string, string, bool) {
+	kind, id := r.PathValue("kind"), r.PathValue("id")
+	if id == "" {
+		http.Error(w, "unknown thing or sensor", http.StatusBadRequest)
+		return "", "", false
+	}
+
+	var err error
+	switch kind {
+	case "thing":
+		_, err = app.GetThing(r.Context(), id, nil)
+	case "device":
+		_, err = app.GetDevice(r.Context(), id)
+	default:
+		http.Error(w, "unknown thing or sensor", http.StatusBadRequest)
+		return "", "", false
+	}
+	if err != nil {
+		http.Error(w, "thing or sensor not found", http.StatusNotFound)
+		return "", "", false
+	}
+
+	return kind, id, true
+}
@@ -0,0 +1,33 @@
+package collection
+
+type CollectionRunPageViewModel struct {
+	Threshold      float64
+	Tenant         string
+	Tag            string
+	DepotLatitude  float64
+	DepotLongitude float64
+	Tenants        []string
+	Tags           []string
+
+	Run *RunViewModel
+}
+
+type RunViewModel struct {
+	// Query holds the criteria of the run so that it can be exported again.
+	Query          string
+	Distance       float64
+	DepotLatitude  float64
+	DepotLongitude float64
+	Stops          []StopViewModel
+	Unplaced       []StopViewModel
+}
+
+type StopViewModel struct {
+	Order     int
+	ID        string
+	Name      string
+	Percent   float64
+	Latitude  float64
+	Longitude float64
+	Tags      []string
+}
@@ -0,0 +1,236 @@
+package reports
+
+import "time"
+
+type OverflowReportPageViewModel struct {
+	Report OverflowReportViewModel
+}
+
+// OverflowReportViewModel summarizes the overflows of all combined sewer overflow outlets between From and To.
+type OverflowReportViewModel struct {
+	// Query holds the period of the report so that it can be exported again.
+	Query   string
+	From    string
+	To      string
+	Count   int
+	Total   time.Duration
+	Outlets []OverflowOutletViewModel
+	Events  []OverflowEventViewModel
+}
+
+type OverflowOutletViewModel struct {
+	ID        string
+	Name      string
+	Tenant    string
+	Count     int
+	Total     time.Duration
+	Longest   time.Duration
+	LastStart *time.Time
+	Failed    bool
+}
+
+type OverflowEventViewModel struct {
+	ThingID  string
+	Name     string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Ongoing  bool
+}
+
+type PumpingReportPageViewModel struct {
+	Report PumpingReportViewModel
+}
+
+// PumpingReportViewModel compares how the pumping stations have been running between From and To.
+type PumpingReportViewModel struct {
+	Query    string
+	From     string
+	To       string
+	Stations []PumpingStationViewModel
+}
+
+type PumpingStationViewModel struct {
+	ID             string
+	Name           string
+	Tenant         string
+	Starts         int
+	StartsPerDay   float64
+	Runtime        time.Duration
+	AverageCycle   time.Duration
+	LongestCycle   time.Duration
+	Running        bool
+	ContinuousRuns int
+	FrequentStarts int
+	Failed         bool
+}
+
+type WaterMeterReportPageViewModel struct {
+	Report WaterMeterReportViewModel
+}
+
+// WaterMeterReportViewModel ranks the water meters by the flags they have raised and their consumption between From and To.
+type WaterMeterReportViewModel struct {
+	Query  string
+	From   string
+	To     string
+	Total  float64
+	Meters []WaterMeterViewModel
+}
+
+type WaterMeterViewModel struct {
+	ID           string
+	Name         string
+	Tenant       string
+	Consumption  float64
+	Flags        []string
+	ProbableLeak bool
+	Failed       bool
+}
+
+type OccupancyReportPageViewModel struct {
+	Report OccupancyReportViewModel
+}
+
+// OccupancyReportViewModel describes the utilisation of rooms and desks during office hours between
+// From and To, with the least used spaces first.
+type OccupancyReportViewModel struct {
+	Query       string
+	From        string
+	To          string
+	Utilisation float64
+	// Hours names the office hours that the heatmap has a column for.
+	Hours  []string
+	Spaces []OccupancySpaceViewModel
+	Groups []OccupancyGroupViewModel
+}
+
+type OccupancySpaceViewModel struct {
+	ID          string
+	Name        string
+	Type        string
+	Tags        []string
+	Utilisation float64
+	PeakHours   []string
+	Failed      bool
+}
+
+// OccupancyGroupViewModel is a row of the heatmap, with the utilisation of every office hour of the
+// spaces that share a tag.
+type OccupancyGroupViewModel struct {
+	Name        string
+	Spaces      int
+	Utilisation float64
+	ByHour      []float64
+}
+
+// ReadinessBoardViewModel describes the lifebuoys, grouped by beach or by tag, and the beaches.
+type ReadinessBoardViewModel struct {
+	Lifebuoys int
+	Missing   int
+	NoStatus  int
+	Groups    []LifebuoyGroupViewModel
+	Beaches   []BeachViewModel
+	// Printed is when the checklist was made.
+	Printed string
+}
+
+type LifebuoyGroupViewModel struct {
+	Name string
+	// BeachID is empty when the lifebuoys are grouped by tag.
+	BeachID   string
+	Missing   int
+	Lifebuoys []LifebuoyViewModel
+}
+
+type LifebuoyViewModel struct {
+	ID        string
+	Name      string
+	Tags      []string
+	Latitude  float64
+	Longitude float64
+	// Status is one of present, missing or nostatus.
+	Status string
+	// MissingFor is how long a missing lifebuoy has been gone, if that is known.
+	MissingFor string
+}
+
+type BeachViewModel struct {
+	ID          string
+	Name        string
+	Latitude    float64
+	Longitude   float64
+	Temperature *float64
+	ObservedAt  string
+	// Trend is the change of the water temperature during the last day.
+	Trend *float64
+}
+
+type WinterReadinessPageViewModel struct {
+	Tenants []string
+	Report  WinterReadinessViewModel
+}
+
+// WinterReadinessViewModel describes the fill state of the sand storages of a tenant, with the
+// storages that need refilling first.
+type WinterReadinessViewModel struct {
+	Query    string
+	Tenant   string
+	From     string
+	To       string
+	Ready    int
+	Low      int
+	Critical int
+	Storages []SandStorageViewModel
+}
+
+type SandStorageViewModel struct {
+	ID     string
+	Name   string
+	Tenant string
+	Fill   *float64
+	// Status is one of ready, low, critical or unknown.
+	Status     string
+	Sensors    int
+	LastRefill string
+	Failed     bool
+}
+
+type DataQualityPageViewModel struct {
+	Tenants []string
+	Report  DataQualityViewModel
+}
+
+// DataQualityViewModel lists the sensors of a tenant that the last check found suspect data in,
+// the most issues first.
+type DataQualityViewModel struct {
+	Query     string
+	Tenant    string
+	CheckedAt string
+	Gaps      int
+	Stuck     int
+	Outliers  int
+	Sensors   []SuspectSensorViewModel
+}
+
+type SuspectSensorViewModel struct {
+	ID       string
+	Name     string
+	Tenant   string
+	Gaps     int
+	Stuck    int
+	Outliers int
+	Issues   []DataIssueViewModel
+	// Partial is set when only the newest values of a measurement of the sensor were checked.
+	Partial bool
+}
+
+// DataIssueViewModel describes an issue in a measurement series. Kind is one of gap, stuck or outlier.
+type DataIssueViewModel struct {
+	Measurement string
+	Kind        string
+	From        string
+	To          string
+	Value       string
+	Count       int
+}
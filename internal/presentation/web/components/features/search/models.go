@@ -0,0 +1,17 @@
+package search
+
+type ResultsViewModel struct {
+	Query  string
+	Groups []GroupViewModel
+}
+
+type GroupViewModel struct {
+	Title string
+	Items []ItemViewModel
+}
+
+type ItemViewModel struct {
+	Title       string
+	Description string
+	Href        string
+}
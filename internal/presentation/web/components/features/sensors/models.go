@@ -1,6 +1,10 @@
 package sensors
 
-import "time"
+import (
+	"time"
+
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
+)
 
 type SensorsPageViewModel struct {
 	Statistics     StatisticsViewModel
@@ -9,6 +13,7 @@ type SensorsPageViewModel struct {
 	DeviceProfiles []string
 	Filters        FiltersViewModel
 	MapView        bool
+	SavedViews     []shared.SavedView
 }
 
 type SensorViewModel struct {
@@ -51,6 +56,7 @@ type FiltersViewModel struct {
 	SelectedTypes []string
 	Active        string
 	Online        string
+	Suspect       string
 	PageSize      int
 }
 
@@ -76,6 +82,12 @@ type SensorDetailsPageViewModel struct {
 	MeasurementTypes  []string
 	Measurements      []MeasurementViewModel
 	DeviceStatus      *DeviceStatusViewModel
+	// Grid is the position in the grid that the user prefers, nil for latitude and longitude.
+	Grid *shared.GridPosition
+
+	// ChartStart and ChartEnd are the preferred default range of the measurement chart, zero if none.
+	ChartStart time.Time
+	ChartEnd   time.Time
 }
 
 type DeviceProfileOption struct {
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/a-h/templ"
+	shared "github.com/diwise/diwise-web/internal/presentation/web/components/shared"
 )
 
 type ThingsPageViewModel struct {
@@ -14,6 +15,7 @@ type ThingsPageViewModel struct {
 	TagOptions    []TagOption
 	Organisations []string
 	MapView       bool
+	SavedViews    []shared.SavedView
 }
 
 type PagingViewModel struct {
@@ -44,6 +46,37 @@ type TagOption struct {
 type NewThingViewModel struct {
 	TypeOptions   []TypeOption
 	Organisations []string
+	Bounds        *BoundsViewModel
+	Grids         []GridOption
+
+	// Source is the thing being duplicated, nil when a new thing is added from scratch. Its type,
+	// organisation, description, tags and properties are filled in, but not its name, location or
+	// connected sensors.
+	Source *ThingViewModel
+	// SourceType is the type option of the thing being duplicated.
+	SourceType string
+}
+
+// BoundsViewModel is the area that things may be placed in.
+type BoundsViewModel struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// GridOption is a grid that pasted northings and eastings can be read in.
+type GridOption struct {
+	Value    string
+	Label    string
+	Selected bool
+}
+
+// PositionViewModel is a pasted position as read by the server, or why it could not be read.
+type PositionViewModel struct {
+	Latitude  string
+	Longitude string
+	Error     string
 }
 
 type ThingViewModel struct {
@@ -62,6 +95,10 @@ type ThingViewModel struct {
 	Latest          map[string]MeasurementViewModel
 	ObservedAt      time.Time
 	Properties      map[string]any
+	// PropertyFields are the properties that things of this type have, as described by its schema.
+	PropertyFields []PropertyField
+	// Tones tell how the values of the thing compare to the limits of the threshold rules.
+	Tones map[string]string
 }
 
 func (t ThingViewModel) HasWarning() bool {
@@ -122,6 +159,14 @@ type ThingDetailsPageViewModel struct {
 	MeasurementOptions             []MeasurementOption
 	SelectedMeasurement            string
 	ToastMessage                   string
+	Bounds                         *BoundsViewModel
+	Grids                          []GridOption
+	// Grid is the position in the grid that the user prefers, nil for latitude and longitude.
+	Grid *shared.GridPosition
+
+	// ChartStart and ChartEnd are the preferred default range of the measurement chart, zero if none.
+	ChartStart time.Time
+	ChartEnd   time.Time
 }
 
 type ConnectedSensorViewModel struct {
@@ -155,6 +200,26 @@ type ThingMeasurementPanelProps struct {
 	Empty               bool
 	SummaryMeasurement  LatestMeasurementViewModel
 	SelectedMeasurement string
+	// Comparison is nil unless the values are compared with an earlier period.
+	Comparison *MeasurementComparisonViewModel
+}
+
+// MeasurementComparisonViewModel compares the values of the chosen time window with those of an
+// earlier period, such as the same week last year.
+type MeasurementComparisonViewModel struct {
+	Label  string
+	Period string
+	Stats  []MeasurementDeltaViewModel
+}
+
+// MeasurementDeltaViewModel is a statistic of both periods and how it has changed. Trend is up,
+// down or empty when nothing changed.
+type MeasurementDeltaViewModel struct {
+	Label    string
+	Current  string
+	Previous string
+	Change   string
+	Trend    string
 }
 
 type MeasurementTableRow struct {
@@ -163,11 +228,226 @@ type MeasurementTableRow struct {
 }
 
 type ThingMeasurementField struct {
-	Label string
-	Name  string
-	Value string
-	Unit  string
-	Step  string
-	Min   string
-	Max   string
+	Label    string
+	Name     string
+	Value    string
+	Unit     string
+	Step     string
+	Min      string
+	Max      string
+	Required bool
+}
+
+// PropertyField is a property of the type of a thing, such as the height of a container. Label and
+// Unit are keys in the localization files.
+type PropertyField struct {
+	Name     string
+	Label    string
+	Unit     string
+	Step     string
+	Min      string
+	Max      string
+	Decimals int
+	Required bool
+}
+
+// OverflowEventsViewModel holds the overflow events of a combined sewer overflow during a period.
+type OverflowEventsViewModel struct {
+	ThingID string
+	From    string
+	To      string
+	Count   int
+	Total   time.Duration
+	Longest time.Duration
+	Events  []OverflowEventViewModel
+}
+
+type OverflowEventViewModel struct {
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Ongoing  bool
+}
+
+// PumpingStatsViewModel describes how a pumping station has been running during a period.
+type PumpingStatsViewModel struct {
+	ThingID      string
+	From         string
+	To           string
+	GroupBy      string
+	Starts       int
+	StartsPerDay float64
+	Runtime      time.Duration
+	AverageCycle time.Duration
+	LongestCycle time.Duration
+	Running      bool
+	Rows         []PumpingBucketViewModel
+	Anomalies    []PumpingAnomalyViewModel
+	Chart        shared.AdvancedChartConfig
+}
+
+type PumpingBucketViewModel struct {
+	Label   string
+	Starts  int
+	Runtime time.Duration
+}
+
+type PumpingAnomalyViewModel struct {
+	Kind     string
+	At       time.Time
+	Value    float64
+	Baseline float64
+}
+
+// WaterConsumptionViewModel describes the consumption of a water meter during a period.
+type WaterConsumptionViewModel struct {
+	ThingID string
+	From    string
+	To      string
+	GroupBy string
+	Total   float64
+	Resets  int
+	// Flags holds the localization keys of the flags that the meter has raised.
+	Flags         []string
+	ProbableLeak  bool
+	LeakSince     *time.Time
+	LastNightFlow *float64
+	Rows          []ConsumptionBucketViewModel
+	Chart         shared.AdvancedChartConfig
+}
+
+type ConsumptionBucketViewModel struct {
+	Label  string
+	Volume float64
+}
+
+// PassageStatisticsViewModel describes the passages through a passage during a period, compared
+// with the same period last year.
+type PassageStatisticsViewModel struct {
+	ThingID string
+	From    string
+	To      string
+	GroupBy string
+	Total   int
+	// LastYearTotal and Change are nil when there is nothing to compare with.
+	LastYearTotal *int
+	Change        *float64
+	Rows          []PassageBucketViewModel
+	// Heatmap holds the passages per weekday, starting with Monday, and hour of the day.
+	Heatmap    [7][24]int
+	HeatmapMax int
+	Chart      shared.AdvancedChartConfig
+}
+
+type PassageBucketViewModel struct {
+	Label    string
+	Passages int
+	LastYear *int
+}
+
+// OccupancyViewModel describes how much a room or a desk has been used during office hours.
+type OccupancyViewModel struct {
+	ThingID       string
+	From          string
+	To            string
+	Utilisation   float64
+	OccupiedHours int
+	OfficeHours   int
+	// PeakHours names the office hours, such as 09–10, when the space was occupied most often.
+	PeakHours []string
+	Chart     shared.AdvancedChartConfig
+}
+
+// SandStorageViewModel shows the level of every sensor of a sand storage, the fill estimate that
+// they are combined into and when the storage was refilled during the period.
+type SandStorageViewModel struct {
+	ThingID      string
+	From         string
+	To           string
+	Aggregation  string
+	Aggregations []string
+	Fill         *float64
+	// Tone tells how the fill estimate compares to the limits of the rule for the filling level.
+	Tone    string
+	Sensors []SandStorageSensorViewModel
+	// Silent are the connected sensors that have not reported a level.
+	Silent  []string
+	Refills []RefillViewModel
+}
+
+type SandStorageSensorViewModel struct {
+	DeviceID   string
+	Percent    float64
+	ObservedAt string
+}
+
+type RefillViewModel struct {
+	At     string
+	Before float64
+	After  float64
+}
+
+// HierarchyViewModel shows the things that a thing is part of, the tree of things that are part
+// of it and the combined state of that tree.
+type HierarchyViewModel struct {
+	ThingID   string
+	Ancestors []HierarchyNodeViewModel
+	Children  []HierarchyNodeViewModel
+	// CanHaveParent is set when the thing can be made part of one of the Candidates.
+	CanHaveParent bool
+	Parent        string
+	Candidates    []HierarchyNodeViewModel
+	Rollup        RollupViewModel
+}
+
+type HierarchyNodeViewModel struct {
+	ID       string
+	Name     string
+	Type     string
+	Value    string
+	Children []HierarchyNodeViewModel
+}
+
+type RollupViewModel struct {
+	Things     int
+	Energy     *float64
+	Power      *float64
+	Present    int
+	Occupiable int
+}
+
+// HistoryViewModel shows how a thing has changed, and what it looked like at the end of a day.
+type HistoryViewModel struct {
+	ThingID string
+	Entries []HistoryEntryViewModel
+	// AsOf is the day that the thing is shown as of, formatted for a date input. The as of tab is
+	// shown when it is set.
+	AsOf    string
+	Version *HistoryVersionViewModel
+	// Since is when the history of the thing begins.
+	Since string
+	Today string
+}
+
+type HistoryEntryViewModel struct {
+	Time     string
+	DateTime string
+	Kind     string
+	Author   string
+	Changes  []HistoryChangeViewModel
+}
+
+type HistoryChangeViewModel struct {
+	Label   string
+	From    string
+	To      string
+	Added   []string
+	Removed []string
+}
+
+// HistoryVersionViewModel is a recorded version of a thing.
+type HistoryVersionViewModel struct {
+	Time   string
+	Author string
+	Items  []shared.DetailListItem
 }
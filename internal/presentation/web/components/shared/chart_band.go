@@ -0,0 +1,36 @@
+package shared
+
+// ChartBandColor is the color of the band drawn between the min and max values of an averaged series.
+func ChartBandColor(isDark bool) string {
+	if isDark {
+		return "#FFFFFF33"
+	}
+	return "#1F1F2526"
+}
+
+// BandDatasets draws a band between the lower and upper values of an averaged series. The upper
+// dataset fills down to the lower dataset, so it must come right after it.
+func BandDatasets(lowerLabel, upperLabel, color string, lower, upper []ChartPoint) []AdvancedChartDataset {
+	band := func(label string, points []ChartPoint, fill any) AdvancedChartDataset {
+		data := make([]any, 0, len(points))
+		for _, p := range points {
+			data = append(data, p)
+		}
+
+		return AdvancedChartDataset{
+			Label:           label,
+			Data:            data,
+			BorderColor:     color,
+			BackgroundColor: color,
+			BorderWidth:     1,
+			PointRadius:     0,
+			Fill:            fill,
+			Tension:         0.2,
+		}
+	}
+
+	return []AdvancedChartDataset{
+		band(lowerLabel, lower, false),
+		band(upperLabel, upper, "-1"),
+	}
+}
@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"fmt"
+
+	. "github.com/diwise/frontend-toolkit"
+)
+
+// GridPosition is a position in a grid such as SWEREF 99 TM, shown instead of latitude and
+// longitude to users that prefer that grid.
+type GridPosition struct {
+	System   string
+	Northing string
+	Easting  string
+}
+
+// CoordinateItems lists a position for a DetailList, in the grid when there is one and as latitude
+// and longitude otherwise.
+func CoordinateItems(l10n Localizer, latitude, longitude float64, grid *GridPosition) []DetailListItem {
+	if grid != nil {
+		return []DetailListItem{
+			{Label: fmt.Sprintf("%s (%s)", l10n.Get("northing"), grid.System), Value: grid.Northing},
+			{Label: fmt.Sprintf("%s (%s)", l10n.Get("easting"), grid.System), Value: grid.Easting},
+		}
+	}
+
+	coordinate := func(value float64) string {
+		if value == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.6f", value)
+	}
+
+	return []DetailListItem{
+		{Label: l10n.Get("latitude"), Value: coordinate(latitude)},
+		{Label: l10n.Get("longitude"), Value: coordinate(longitude)},
+	}
+}
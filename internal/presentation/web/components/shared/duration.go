@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatDuration formats d in hours and minutes, or in seconds when it is shorter than a minute.
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%d s", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%d h %d min", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// FormatHourOfDay names the hour of the day that starts at hour, such as 09–10.
+func FormatHourOfDay(hour int) string {
+	return fmt.Sprintf("%02d–%02d", hour, (hour+1)%24)
+}
+
+// FormatAge formats how long ago something happened in days and hours, or as FormatDuration when
+// it is less than a day.
+func FormatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return FormatDuration(d)
+	}
+	return fmt.Sprintf("%d d %d h", int(d.Hours())/24, int(d.Hours())%24)
+}